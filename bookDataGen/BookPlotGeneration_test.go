@@ -21,25 +21,15 @@ func TestBook_GenerateSlidingWindowData(t *testing.T) {
     sw,_:=stateGen.NewSlidingWindowStateGen(timeFrame,window,1);
     c,_:=db.GetClientByEmail(&testDB,"one");
     // Earilest data point is 8/10/2021, this date is small enough to get all values
+    surfs,_:=potSurf.SurfaceFactory(
+        potSurf.BasicSurfaceId,potSurf.VolumeBaseSurfaceId,
+    );
     sw.GenerateClientModelStates(&testDB,c,time.Date(
         2020,time.Month(1),1,0,0,0,0,time.UTC,
-    ),func() []potSurf.Surface {
-        return []potSurf.Surface{
-            potSurf.NewBasicSurface().ToGenericSurf(),
-            potSurf.NewVolumeBaseSurface().ToGenericSurf(),
-        };
-    });
-    db.ReadAll[db.TrainingLog](&testDB).ForEach(
-    func(index int, val *db.TrainingLog) (iter.IteratorFeedback, error) {
-        if pred,err:=model.GeneratePrediction(&testDB,
-            val,
-            stateGen.SlidingWindowStateGenId,
-            potSurf.BasicSurfaceId,
-        ); err==nil {
-            db.Create(&testDB,pred);
-        }
-        return iter.Continue,nil;
-    });
+    ),surfs);
+    model.GeneratePredictionsForClient(&testDB,c,
+        stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,1,
+    );
 }
 
 func TestBook_SaveGeneratedData(t *testing.T) {
@@ -119,7 +109,7 @@ func TestBook_SaveSlidingWindowBasicSurfaceEstimated1RM(t *testing.T){
                 TrainingLog.DatePerformed,
                 Exercise.Name,
                 TrainingLog.Effort,
-                Prediction.IntensityPred,
+                Prediction.Val,
                 TrainingLog.Intensity,
                 0.0 AS Difference
             FROM Prediction 
@@ -5,8 +5,11 @@ import (
     customerr "github.com/barbell-math/engine/util/err"
 )
 
+//Buffers rows and creates them in batches. If conflict columns are given
+//(see NewBufferedUpsert) the rows are upserted instead.
 type BufferedCreate[R DBTable] struct {
     buf []R;
+    conflictCols []string;
     bufCntr int;
     succeeded int;
     failed int;
@@ -23,6 +26,23 @@ func NewBufferedCreate[R DBTable](bufSize int) (BufferedCreate[R],error) {
     },nil;
 }
 
+//The same as NewBufferedCreate except rows that conflict with an existing row
+//on the given columns update the existing row. See Upsert.
+func NewBufferedUpsert[R DBTable](
+        bufSize int,
+        conflictCols ...string) (BufferedCreate[R],error) {
+    rv,err:=NewBufferedCreate[R](bufSize);
+    if err!=nil {
+        return rv,err;
+    } else if len(conflictCols)==0 {
+        return rv,customerr.InvalidValue(
+            "at least one conflict column is needed to upsert",
+        );
+    }
+    rv.conflictCols=append([]string{},conflictCols...);
+    return rv,nil;
+}
+
 func (b *BufferedCreate[R])Succeeded() int { return b.succeeded; }
 func (b *BufferedCreate[R])Failed() int { return b.failed; }
 
@@ -46,7 +66,7 @@ func (b *BufferedCreate[R])Flush(c *DB) error {
         tmp:=b.buf[0:b.bufCntr];
         bufPntr=&tmp;
     }
-    added,err:=Create(c,*bufPntr...);
+    added,err:=insertRows(c,b.conflictCols,*bufPntr...);
     succeeded:=0;
     for _,v:=range(added) {
         if v>0 {
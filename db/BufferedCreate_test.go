@@ -90,3 +90,30 @@ func TestBufferedCreateFlush(t *testing.T) {
         "The correct number of values were not created.",t,
     );
 }
+
+func TestBufferedUpsert(t *testing.T) {
+    setup();
+    _,err:=NewBufferedUpsert[Client](10);
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Incorrect error was returned.",t,
+        );
+    }
+    tmp,err:=NewBufferedUpsert[Client](10,"Email");
+    test.BasicTest(nil,err,
+        "An error was returned when it should not have been.",t,
+    );
+    for i:=0; i<5; i++ {
+        tmp.Write(&testDB,Client{FirstName: "old", Email: fmt.Sprintf("%d",i)});
+    }
+    for i:=0; i<5; i++ {
+        tmp.Write(&testDB,Client{FirstName: "new", Email: fmt.Sprintf("%d",i)});
+    }
+    test.BasicTest(10,tmp.Succeeded(),
+        "Not every upsert succeeded.",t,
+    );
+    cnt,_:=ReadAll[Client](&testDB).Count();
+    test.BasicTest(5,cnt,"Upserting created duplicate rows.",t);
+    c,_:=GetClientByEmail(&testDB,"0");
+    test.BasicTest("new",c.FirstName,"Upserting did not update the values.",t);
+}
@@ -4,14 +4,33 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/barbell-math/engine/util/algo"
 	"github.com/barbell-math/engine/util/algo/iter"
 	"github.com/barbell-math/engine/util/io/csv"
 	customReflect "github.com/barbell-math/engine/util/reflect"
+	customerr "github.com/barbell-math/engine/util/err"
 )
 
 func Create[R DBTable](c *DB, rows ...R) ([]int,error) {
+    return insertRows(c,[]string{},rows...);
+}
+
+//Inserts the rows, updating the existing row instead when a row conflicts with
+//an existing row on the given columns. The conflict columns need to match a
+//unique constraint on the table. The ids of the inserted or updated rows are
+//returned.
+func Upsert[R DBTable](c *DB, conflictCols []string, rows ...R) ([]int,error) {
+    if len(conflictCols)==0 {
+        return []int{},customerr.InvalidValue(
+            "at least one conflict column is needed to upsert",
+        );
+    }
+    return insertRows(c,conflictCols,rows...);
+}
+
+func insertRows[R DBTable](c *DB, conflictCols []string, rows ...R) ([]int,error) {
     if len(rows)==0 {
         return []int{},sql.ErrNoRows;
     }
@@ -26,16 +45,31 @@ func Create[R DBTable](c *DB, rows ...R) ([]int,error) {
     valuesStr:=csv.CSVGenerator(",",func(iter int) (string,bool) {
         return fmt.Sprintf("$%d",iter+1), iter+1<len(columns);
     });
+    conflictStr:="";
+    if len(conflictCols)>0 {
+        conflictStr=fmt.Sprintf(" ON CONFLICT(%s) DO UPDATE SET %s",
+            strings.Join(conflictCols,","),
+            csv.CSVGenerator(",",func(iter int) (string,bool) {
+                return fmt.Sprintf(
+                    "%s=EXCLUDED.%s",columns[iter],columns[iter],
+                ), iter+1<len(columns);
+            }),
+        );
+    }
     sqlStmt:=fmt.Sprintf(
-        "INSERT INTO %s(%s) VALUES (%s) RETURNING Id;",
-        getTableName(&rows[0]),intoStr,valuesStr,
+        "INSERT INTO %s(%s) VALUES (%s)%s RETURNING Id;",
+        getTableName(&rows[0]),intoStr,valuesStr,conflictStr,
     );
     var err error=nil;
     rv:=make([]int,len(rows));
     for i:=0; err==nil && i<len(rows); i++ {
+        row:=rows[i];
+        if err=toCanonical(&row); err!=nil {
+            break;
+        }
         rv[i],err=getQueryRowReflectResults(c,algo.AppendWithPreallocation(
                 []reflect.Value{reflect.ValueOf(sqlStmt)},
-                getTableVals(&rows[i],AllButIDFilter),
+                getTableVals(&row,AllButIDFilter),
         ));
     }
     return rv,err;
@@ -54,6 +88,9 @@ func Read[R DBTable](
             FilterRemovedAllColumns("No value rows were selected."),1,
         );
     }
+    if err:=toCanonical(&rowVals); err!=nil {
+        return iter.ValElem[*R](nil,err,1);
+    }
     valuesStr,_,_:=csv.Flatten(iter.SliceElems([][]string{columns})," AND ").Nth(0);
     sqlStmt:=fmt.Sprintf(
         "SELECT * FROM %s WHERE %s;",getTableName(&rowVals),valuesStr,
@@ -63,7 +100,7 @@ func Read[R DBTable](
             []reflect.Value{reflect.ValueOf(sqlStmt)},
             getTableVals(&rowVals,filter),
         ),
-    );
+    ).Map(fromCanonicalRow[R]);
 }
 
 func ReadAll[R DBTable](c *DB) iter.Iter[*R] {
@@ -71,7 +108,14 @@ func ReadAll[R DBTable](c *DB) iter.Iter[*R] {
     sqlStmt:=fmt.Sprintf("SELECT * FROM %s;",getTableName(&tmp));
     return getQueryReflectResults[R](c,
         []reflect.Value{reflect.ValueOf(sqlStmt)},
-    );
+    ).Map(fromCanonicalRow[R]);
+}
+
+func fromCanonicalRow[R DBTable](index int, val *R) (*R,error) {
+    if val!=nil {
+        fromCanonical(val);
+    }
+    return val,nil;
 }
 
 func Update[R DBTable](
@@ -80,6 +124,11 @@ func Update[R DBTable](
         searchValsFilter algo.Filter[string],
         updateVals R,
         updateValsFilter algo.Filter[string]) (int64,error) {
+    if err:=toCanonical(&searchVals); err!=nil {
+        return 0,err;
+    } else if err:=toCanonical(&updateVals); err!=nil {
+        return 0,err;
+    }
     updateColumns,_:=iter.SliceElems(getTableColumns(&updateVals,updateValsFilter)).Map(
     func(index int, val string) (string, error) {
         return fmt.Sprintf("%s=$%d",val,index+1),nil;
@@ -109,6 +158,9 @@ func UpdateAll[R DBTable](
         c *DB,
         updateVals R,
         updateValsFilter algo.Filter[string]) (int64,error) {
+    if err:=toCanonical(&updateVals); err!=nil {
+        return 0,err;
+    }
     updateColumns,_:=iter.SliceElems(getTableColumns(&updateVals,updateValsFilter)).Map(
     func(index int, val string) (string, error) {
         return fmt.Sprintf("%s=$%d",val,index+1),nil;
@@ -130,6 +182,9 @@ func Delete[R DBTable](
         c *DB,
         searchVals R,
         searchValsFilter algo.Filter[string]) (int64,error) {
+    if err:=toCanonical(&searchVals); err!=nil {
+        return 0,err;
+    }
     columns,_:=iter.SliceElems(getTableColumns(&searchVals,searchValsFilter)).Map(
     func(index int, val string) (string, error) {
         return fmt.Sprintf("%s=$%d",val,index+1),nil;
@@ -8,6 +8,7 @@ import (
     "github.com/barbell-math/engine/util/test"
     "github.com/barbell-math/engine/util/algo"
     "github.com/barbell-math/engine/util/algo/iter"
+    customerr "github.com/barbell-math/engine/util/err"
 )
 
 func createTestHelper[R DBTable](
@@ -126,6 +127,28 @@ func TestCreate(t *testing.T){
     );
 }
 
+func TestUpsert(t *testing.T){
+    setup();
+    ids,err:=Upsert(&testDB,[]string{"Email"},
+        Client{FirstName: "test", LastName: "test", Email: "test@test.com"},
+    );
+    test.BasicTest(nil,err,"Upsert returned an error when inserting.",t);
+    ids2,err:=Upsert(&testDB,[]string{"Email"},
+        Client{FirstName: "new", LastName: "new", Email: "test@test.com"},
+    );
+    test.BasicTest(nil,err,"Upsert returned an error when updating.",t);
+    test.BasicTest(ids[0],ids2[0],"Upsert did not update the existing row.",t);
+    c,_:=GetClientByEmail(&testDB,"test@test.com");
+    test.BasicTest("new",c.FirstName,"Upsert did not update the values.",t);
+    cnt,_:=ReadAll[Client](&testDB).Count();
+    test.BasicTest(1,cnt,"Upsert created a duplicate row.",t);
+    if _,err=Upsert(&testDB,[]string{},Client{}); !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Upserting without conflict columns did not return an error.",t,
+        );
+    }
+}
+
 func TestRead(t *testing.T){
     setup();
     vals:=[]ExerciseType{
@@ -39,3 +39,16 @@ func CustomDeleteQuery(c *DB, sqlStmt string, vals []any) (int64,error) {
         "CustomDeleteQuery only accepts 'DELETE' query's.",
     );
 }
+
+func CustomInsertQuery(c *DB, sqlStmt string, vals []any) (int64,error) {
+    if InsertStmt.isQueryType(sqlStmt) {
+        res,err:=c.db.Exec(sqlStmt,vals...);
+        if err==nil {
+            return res.RowsAffected();
+        }
+        return 0, err;
+    }
+    return 0, UnsupportedQueryType(
+        "CustomInsertQuery only accepts 'INSERT' query's.",
+    );
+}
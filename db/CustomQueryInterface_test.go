@@ -160,3 +160,47 @@ func TestCustomDeleteQueryTypes(t *testing.T){
         "Custom delete query created an error it was not supposed to.",t,
     );
 }
+
+func TestCustomInsertQueryWrongQueryType(t *testing.T){
+    setup();
+    cntr,err:=CustomInsertQuery(
+        &testDB,"DELETE FROM Exercise WHERE Id=$1;",[]any{0},
+    );
+    test.BasicTest(int64(0), cntr,
+        "Custom insert query inserted values it was not supposed to.",t,
+    );
+    if !IsUnsupportedQueryType(err) {
+        test.BasicTest(UnsupportedQueryType(""),err,
+            "Custom insert query did not return error on non-insert stmt.",t,
+        );
+    }
+}
+
+func TestCustomInsertQuery(t *testing.T){
+    setup();
+    cntr,err:=CustomInsertQuery(&testDB,
+        `INSERT INTO PotentialSurface(Id,T,Description) VALUES ($1,$2,$3)
+         ON CONFLICT (Id) DO UPDATE SET T=EXCLUDED.T;`,
+        []any{10,"TestSurface","Test description"},
+    );
+    test.BasicTest(int64(1),cntr,
+        "Custom insert query did not insert the value.",t,
+    );
+    test.BasicTest(nil,err,
+        "Custom insert query created an error it was not supposed to.",t,
+    );
+    cntr,err=CustomInsertQuery(&testDB,
+        `INSERT INTO PotentialSurface(Id,T,Description) VALUES ($1,$2,$3)
+         ON CONFLICT (Id) DO UPDATE SET T=EXCLUDED.T;`,
+        []any{10,"TestSurfaceUpdated","Test description"},
+    );
+    test.BasicTest(int64(1),cntr,
+        "Custom insert query did not upsert the value.",t,
+    );
+    test.BasicTest(nil,err,
+        "Custom insert query created an error it was not supposed to.",t,
+    );
+    s,err:=GetPotentialSurfaceByName(&testDB,"TestSurfaceUpdated");
+    test.BasicTest(nil,err,"The upserted value could not be found.",t);
+    test.BasicTest(10,s.Id,"The upserted value had the wrong id.",t);
+}
@@ -117,7 +117,7 @@ func (c *DB)ExecSQLScript(src string) error {
     if globalInit,err=os.Open(src); err==nil {
         defer globalInit.Close();
         scanner:=bufio.NewScanner(globalInit);
-        scanner.Split(customio.Splitter(";"));
+        scanner.Split(customio.QuotedSplitter(";","$$"));
         for err==nil && scanner.Scan() {
             _,err=c.db.Exec(strings.TrimSpace(scanner.Text())+";");
         }
@@ -26,3 +26,7 @@ var NoKnownDataConversion,IsNoKnownDataConversion=customerr.ErrorFactory(
 var FilterRemovedAllColumns,IsFilterRemovedAllColumns=customerr.ErrorFactory(
     "The filter passed resulted in no columns being selected.",
 );
+
+var InvalidUnit,IsInvalidUnit=customerr.ErrorFactory(
+    "The unit is not a recognized unit of weight.",
+);
@@ -0,0 +1,25 @@
+package db;
+
+//Where an exercise max came from. The values are saved in the Source column
+//of the ExerciseMax table so they must not be re-ordered.
+type MaxSource int;
+const (
+    TestedMax MaxSource = iota
+    EpleyMax
+    BrzyckiMax
+    LombardiMax
+    RpeTableMax
+    ModelStateMax
+)
+
+func (m MaxSource)String() string {
+    switch m {
+        case TestedMax: return "Tested";
+        case EpleyMax: return "Epley";
+        case BrzyckiMax: return "Brzycki";
+        case LombardiMax: return "Lombardi";
+        case RpeTableMax: return "RPE Table";
+        case ModelStateMax: return "Model State";
+        default: return "unknown";
+    }
+}
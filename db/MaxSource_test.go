@@ -0,0 +1,18 @@
+package db;
+
+import (
+    "testing"
+    "github.com/barbell-math/engine/util/test"
+)
+
+func TestMaxSourceString(t *testing.T){
+    test.BasicTest("Tested",TestedMax.String(),
+        "Max source string was not correct.",t,
+    );
+    test.BasicTest("Model State",ModelStateMax.String(),
+        "Max source string was not correct.",t,
+    );
+    test.BasicTest("unknown",MaxSource(-1).String(),
+        "Max source string was not correct.",t,
+    );
+}
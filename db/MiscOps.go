@@ -1,7 +1,9 @@
 package db;
 
 import (
+    "fmt"
     "time"
+    "strings"
     "database/sql"
     "github.com/barbell-math/engine/util/algo"
     customerr "github.com/barbell-math/engine/util/err"
@@ -92,6 +94,21 @@ func InitClient(
             b:=f(r[3].([]int)[0],r[4].([]int)[0],r[1].(Exercise).Id,bMax);
             d:=f(r[3].([]int)[0],r[4].([]int)[0],r[2].(Exercise).Id,dMax);
             return Create(db,s,d,b);
+        }, func(r ...any) (any,error) {
+            m:=func(eId int, w float64) ExerciseMax {
+                return ExerciseMax{
+                    ClientID: r[3].([]int)[0],
+                    ExerciseID: eId,
+                    Date: time.Now().AddDate(0, 0, -1),
+                    Weight: w,
+                    Source: int(TestedMax),
+                };
+            }
+            return Create(db,
+                m(r[0].(Exercise).Id,sMax),
+                m(r[1].(Exercise).Id,bMax),
+                m(r[2].(Exercise).Id,dMax),
+            );
         },
     );
 }
@@ -122,6 +139,41 @@ func RmClient(db *DB, c *Client) (int64,error) {
             return Delete(
                 db,BodyWeight{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
             );
+        }, func(r ...any) (any,error) {
+            return Delete(
+                db,PlannedWorkout{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
+            );
+        }, func(r ...any) (any,error) {
+            return Delete(
+                db,ProgramInstance{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
+            );
+        }, func(r ...any) (any,error) {
+            return Delete(
+                db,ExerciseMax{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
+            );
+        }, func(r ...any) (any,error) {
+            return Delete(
+                db,Bar{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
+            );
+        }, func(r ...any) (any,error) {
+            return Delete(
+                db,PlatePair{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
+            );
+        }, func(r ...any) (any,error) {
+            return Delete(
+                db,Collar{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
+            );
+        }, func(r ...any) (any,error) {
+            return CustomDeleteQuery(db,
+                `DELETE FROM ModelStateCovariance
+                 WHERE Id IN (
+                    SELECT ModelStateCovariance.Id
+                    FROM ModelStateCovariance
+                    JOIN ModelState
+                    ON ModelStateCovariance.ModelStateID=ModelState.Id
+                    WHERE ModelState.ClientID=$1
+                 );`,[]any{c.Id},
+            );
         }, func(r ...any) (any,error) {
             return Delete(
                 db,ModelState{ClientID: c.Id},algo.GenFilter(false,"ClientID"),
@@ -137,6 +189,94 @@ func RmClient(db *DB, c *Client) (int64,error) {
     return rv,err;
 }
 
+//Returns the most recent model state for the exercise that was created by the
+//state generator and surface before the given date. If no model state exists
+//sql.ErrNoRows is returned.
+func GetModelStateBeforeDate(
+        c *DB,
+        clientId int,
+        exerciseId int,
+        stateGeneratorId int,
+        potentialSurfaceId int,
+        date time.Time) (ModelState,error) {
+    rv,err,found:=CustomReadQuery[ModelState](c,
+        `SELECT * FROM ModelState
+        WHERE ClientID=$1 AND ExerciseID=$2
+            AND StateGeneratorID=$3 AND PotentialSurfaceID=$4
+            AND Date<$5
+        ORDER BY Date DESC
+        LIMIT 1;`,
+        []any{clientId,exerciseId,stateGeneratorId,potentialSurfaceId,date},
+    ).Nth(0);
+    if rv!=nil && found {
+        return *rv,err;
+    } else if err==nil {
+        return ModelState{},sql.ErrNoRows;
+    }
+    return ModelState{},err;
+}
+
+//Returns the max that was in effect for the exercise on the given date, which
+//is the most recent max recorded on or before that date. When several maxes
+//share the same date tested maxes are preferred over estimated ones. If no
+//max exists sql.ErrNoRows is returned.
+func GetMaxOnDate(
+        c *DB,
+        clientId int,
+        exerciseId int,
+        date time.Time) (ExerciseMax,error) {
+    rv,err,found:=CustomReadQuery[ExerciseMax](c,
+        `SELECT * FROM ExerciseMax
+        WHERE ClientID=$1 AND ExerciseID=$2 AND Date<=$3
+        ORDER BY Date DESC, Source ASC, Id DESC
+        LIMIT 1;`,
+        []any{clientId,exerciseId,date},
+    ).Nth(0);
+    if rv!=nil && found {
+        return *rv,err;
+    } else if err==nil {
+        return ExerciseMax{},sql.ErrNoRows;
+    }
+    return ExerciseMax{},err;
+}
+
+//Returns the body weight entry that is closest to the given date, before or
+//after. If two entries are equally close the earlier one is returned. If the
+//client has no body weight entries sql.ErrNoRows is returned.
+func GetBodyWeightNearDate(
+        c *DB,
+        clientId int,
+        date time.Time) (BodyWeight,error) {
+    rv,err,found:=CustomReadQuery[BodyWeight](c,
+        `SELECT * FROM BodyWeight
+        WHERE ClientID=$1
+        ORDER BY ABS(Date-$2::date) ASC, Date ASC, Id DESC
+        LIMIT 1;`,
+        []any{clientId,date},
+    ).Nth(0);
+    if rv!=nil && found {
+        return *rv,err;
+    } else if err==nil {
+        return BodyWeight{},sql.ErrNoRows;
+    }
+    return BodyWeight{},err;
+}
+
+//Sets the id sequence of the table to the largest id in the table. This needs
+//to be called after inserting rows with explicit ids, otherwise the next call
+//to Create will try to reuse an id that is already taken.
+func ResetIdSequence[R DBTable](c *DB) error {
+    var tmp R;
+    tName:=getTableName(&tmp);
+    _,err:=c.db.Exec(fmt.Sprintf(
+        `SELECT setval(
+            pg_get_serial_sequence('%s','id'),
+            COALESCE((SELECT MAX(Id) FROM %s),1)
+        );`,strings.ToLower(tName),tName,
+    ));
+    return err;
+}
+
 //func UpdateTrainingLogUsingCurRot(c *Client, e *Exercise, t *TrainingLog) (int,error) {
 //
 //}
@@ -234,12 +234,96 @@ func TestRmClient(t *testing.T){
             });
         }, func (r ...any) (any,error) {
             return Create(&testDB,Prediction{
-                StateGeneratorID: 1, TrainingLogID: 1, IntensityPred: 0,
+                StateGeneratorID: 1, TrainingLogID: 1, Val: 0,
             });
         },
     );
     test.BasicTest(nil,err,"Database was not setup correctly to run test.",t);
     val,err:=RmClient(&testDB,&c);
     test.BasicTest(nil,err,"RmClient created an error when it shouldn't have.",t);
-    test.BasicTest(int64(8),val,"RmClient did not delete all client data.",t);
+    test.BasicTest(int64(11),val,"RmClient did not delete all client data.",t);
+}
+
+func TestResetIdSequence(t *testing.T){
+    setup();
+    _,err:=CustomInsertQuery(&testDB,
+        "INSERT INTO PotentialSurface(Id,T,Description) VALUES ($1,$2,$3);",
+        []any{5,"PotSurf","PotSurf"},
+    );
+    test.BasicTest(nil,err,"Database was not setup correctly to run test.",t);
+    err=ResetIdSequence[PotentialSurface](&testDB);
+    test.BasicTest(nil,err,
+        "Resetting the id sequence returned an error when it shouldn't have.",t,
+    );
+    ids,err:=Create(&testDB,PotentialSurface{
+        T: "PotSurf2", Description: "PotSurf2",
+    });
+    test.BasicTest(nil,err,
+        "Creating a row after resetting the sequence returned an error.",t,
+    );
+    test.BasicTest(6,ids[0],
+        "The id sequence was not reset to the max id in the table.",t,
+    );
+}
+
+func TestGetMaxOnDate(t *testing.T){
+    setup();
+    createExerciseTestData();
+    Create(&testDB,Client{
+        FirstName: "test", LastName: "testl", Email: "test@test.com",
+    });
+    day:=time.Date(2023,time.January,10,0,0,0,0,time.UTC);
+    _,err:=Create(&testDB,
+        ExerciseMax{
+            ClientID: 1, ExerciseID: 1, Date: day,
+            Weight: 400, Source: int(TestedMax),
+        }, ExerciseMax{
+            ClientID: 1, ExerciseID: 1, Date: day.AddDate(0,0,5),
+            Weight: 420, Source: int(EpleyMax),
+        }, ExerciseMax{
+            ClientID: 1, ExerciseID: 1, Date: day.AddDate(0,0,5),
+            Weight: 410, Source: int(TestedMax),
+        },
+    );
+    test.BasicTest(nil,err,"Database was not setup correctly to run test.",t);
+    m,err:=GetMaxOnDate(&testDB,1,1,day.AddDate(0,0,2));
+    test.BasicTest(nil,err,"Max was not found when it should have been.",t);
+    test.BasicTest(float64(400),m.Weight,"The wrong max was returned.",t);
+    m,err=GetMaxOnDate(&testDB,1,1,day.AddDate(0,0,5));
+    test.BasicTest(nil,err,"Max was not found when it should have been.",t);
+    test.BasicTest(float64(410),m.Weight,
+        "Tested maxes were not preferred over estimated ones.",t,
+    );
+    _,err=GetMaxOnDate(&testDB,1,1,day.AddDate(0,0,-1));
+    test.BasicTest(sql.ErrNoRows,err,
+        "No error was generated when getting a non-existent max.",t,
+    );
+}
+
+func TestGetBodyWeightNearDate(t *testing.T){
+    setup();
+    Create(&testDB,Client{
+        FirstName: "test", LastName: "testl", Email: "test@test.com",
+    });
+    day:=time.Date(2023,time.January,10,0,0,0,0,time.UTC);
+    _,err:=Create(&testDB,
+        BodyWeight{ClientID: 1, Weight: 80, Date: day},
+        BodyWeight{ClientID: 1, Weight: 82, Date: day.AddDate(0,0,10)},
+    );
+    test.BasicTest(nil,err,"Database was not setup correctly to run test.",t);
+    bw,err:=GetBodyWeightNearDate(&testDB,1,day.AddDate(0,0,-3));
+    test.BasicTest(nil,err,"Body weight was not found when it should have been.",t);
+    test.BasicTest(float32(80),bw.Weight,"The wrong body weight was returned.",t);
+    bw,err=GetBodyWeightNearDate(&testDB,1,day.AddDate(0,0,7));
+    test.BasicTest(nil,err,"Body weight was not found when it should have been.",t);
+    test.BasicTest(float32(82),bw.Weight,"The wrong body weight was returned.",t);
+    bw,err=GetBodyWeightNearDate(&testDB,1,day.AddDate(0,0,5));
+    test.BasicTest(nil,err,"Body weight was not found when it should have been.",t);
+    test.BasicTest(float32(80),bw.Weight,
+        "Ties were not broken with the earlier body weight.",t,
+    );
+    _,err=GetBodyWeightNearDate(&testDB,2,day);
+    test.BasicTest(sql.ErrNoRows,err,
+        "No error was generated when getting a non-existent body weight.",t,
+    );
 }
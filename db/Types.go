@@ -18,9 +18,19 @@ type DBTable interface {
     TrainingLog |
     Client |
     ModelState |
+    ModelStateCovariance |
     PotentialSurface |
     StateGenerator |
-    Prediction
+    Prediction |
+    PlannedWorkout |
+    ExerciseMax |
+    OutlierFlag |
+    ProgramTemplate |
+    ProgramInstance |
+    ProgramInstanceWorkout |
+    Bar |
+    PlatePair |
+    Collar
 };
 
 type ExerciseType struct {
@@ -54,6 +64,8 @@ type BodyWeight struct {
     ClientID int;
     Weight float32;
     Date time.Time;
+    //The unit the weight was entered in, see Unit.
+    Unit int;
 };
 
 type TrainingLog struct {
@@ -70,6 +82,8 @@ type TrainingLog struct {
     Volume float64;
     InterExerciseFatigue int;
     InterWorkoutFatigue int;
+    //The unit the weight and volume were entered in, see Unit.
+    Unit int;
 };
 
 type Client struct {
@@ -77,6 +91,8 @@ type Client struct {
     FirstName string;
     LastName string;
     Email string;
+    //The unit the client prefers weights to be displayed in, see Unit.
+    Unit int;
 };
 
 type StateGenerator struct {
@@ -89,6 +105,10 @@ type PotentialSurface struct {
     Id int;
     T string;
     Description string;
+    //The number of days of training history before a data point that the
+    //surface uses. Changes that far before a model states time frame mark the
+    //model state as stale.
+    HistoryDays int;
 };
 
 type ModelState struct {
@@ -104,6 +124,27 @@ type ModelState struct {
     Win int;
     Rcond float64;
     Mse float64;
+    //The number of samples the model state was fit with and the standard
+    //deviation of the residuals. Both are used to create prediction intervals.
+    SampleCount int;
+    Sigma float64;
+    //The criteria that was used to select the model state, zero if the state
+    //generator does not select between candidates.
+    SelectionCriteria int;
+    //Set when a training log inside the time frame the model state was fit
+    //with is created, changed, or deleted.
+    Stale bool;
+};
+
+//Holds one element of the state covariance matrix that was used to create a
+//model state. Only state generators that incrementally update model states
+//(ex. the kalman filter) save these values.
+type ModelStateCovariance struct {
+    Id int;
+    ModelStateID int;
+    RowIdx int;
+    ColIdx int;
+    Val float64;
 };
 
 type Prediction struct {
@@ -111,5 +152,124 @@ type Prediction struct {
     PotentialSurfaceID int;
     StateGeneratorID int;
     TrainingLogID int;
-    IntensityPred float64;
+    PredictedVar int;
+    Val float64;
+    //The bounds of the prediction interval. NaN if the interval could not be
+    //found.
+    Lower float64;
+    Upper float64;
+    //Set when the model state the prediction was made from or the training
+    //log it was made for is changed.
+    Stale bool;
+};
+
+//A workout that has been prescribed but not performed yet. The values mirror
+//the training log so that planned and performed workouts can be compared. The
+//state generator and surface are the ones that were used to create the plan.
+type PlannedWorkout struct {
+    Id int;
+    ClientID int;
+    ExerciseID int;
+    StateGeneratorID int;
+    PotentialSurfaceID int;
+    DatePlanned time.Time;
+    Weight float64;
+    Sets float64;
+    Reps float64;
+    Intensity float64;
+    Effort float64;
+    InterExerciseFatigue int;
+    InterWorkoutFatigue int;
+    //The unit the weight was entered in, see Unit.
+    Unit int;
+};
+
+//A max for a single exercise on a given date. Tested maxes and estimated maxes
+//are both saved, the source records which one it is. See MaxSource for the
+//possible values.
+type ExerciseMax struct {
+    Id int;
+    ClientID int;
+    ExerciseID int;
+    Date time.Time;
+    Weight float64;
+    Source int;
+    //The unit the weight was entered in, see Unit.
+    Unit int;
+};
+
+//A training log that did not agree with the model state on the day it was
+//performed. The residual is the difference between the logged and predicted
+//intensity and the z-score is the residual divided by the standard error of
+//the model state. Excluded flags are ignored by the state generators, the
+//training log itself is never changed.
+type OutlierFlag struct {
+    Id int;
+    TrainingLogID int;
+    StateGeneratorID int;
+    PotentialSurfaceID int;
+    Residual float64;
+    ZScore float64;
+    Excluded bool;
+};
+
+//A periodization template. The template is saved as the JSON that describes
+//it, see the prescription package for the format.
+type ProgramTemplate struct {
+    Id int;
+    Name string;
+    Kind string;
+    Template string;
+};
+
+//A template that was applied to a client starting on the given date. The
+//state generator and surface are the ones that were used to create the loads.
+type ProgramInstance struct {
+    Id int;
+    ClientID int;
+    ProgramTemplateID int;
+    StateGeneratorID int;
+    PotentialSurfaceID int;
+    StartDate time.Time;
+};
+
+//Links a planned workout to the slot of the template it was created from. The
+//mesocycle, week, session, and slot are indexes into the template.
+type ProgramInstanceWorkout struct {
+    Id int;
+    ProgramInstanceID int;
+    PlannedWorkoutID int;
+    Mesocycle int;
+    Week int;
+    Session int;
+    Slot int;
+};
+
+//A bar a client has access to. The weight is in the unit of the row, see Unit.
+type Bar struct {
+    Id int;
+    ClientID int;
+    Name string;
+    Weight float64;
+    Unit int;
+};
+
+//The number of pairs of plates of a single weight a client has access to. The
+//weight is the weight of one plate in the unit of the row, see Unit.
+type PlatePair struct {
+    Id int;
+    ClientID int;
+    Weight float64;
+    Pairs int;
+    Unit int;
+};
+
+//A pair of collars a client has access to. The weight is the weight of both
+//collars in the unit of the row, see Unit.
+type Collar struct {
+    Id int;
+    ClientID int;
+    Name string;
+    Weight float64;
+    Unit int;
 };
@@ -0,0 +1,219 @@
+package db;
+
+import (
+    "fmt"
+    "github.com/barbell-math/engine/util/algo/iter"
+    "github.com/barbell-math/engine/util/io/csv"
+)
+
+//The units a weight can be given in. The values are saved in the Unit columns
+//of the tables so they must not be re-ordered.
+//Weights are always stored in kilograms. The unit of a row is the unit the row
+//was entered in, and it is the unit the weights of the row are given in when
+//the row is passed to or returned from the CRUD interface (Create, Upsert,
+//Read, ReadAll, Update, Delete). Custom queries do not convert weights, so
+//the weights they return are always in kilograms.
+type Unit int;
+const (
+    Kilograms Unit = iota
+    Pounds
+)
+
+//The number of kilograms in a pound.
+const KgPerLb float64=0.45359237;
+
+func (u Unit)String() string {
+    switch u {
+        case Kilograms: return "kg";
+        case Pounds: return "lb";
+        default: return "unknown";
+    }
+}
+
+//Parses the short name of a unit, either 'kg' or 'lb'.
+func ParseUnit(s string) (Unit,error) {
+    switch s {
+        case "kg": return Kilograms,nil;
+        case "lb": return Pounds,nil;
+        default: return Kilograms,InvalidUnit(fmt.Sprintf("Unit: '%s'",s));
+    }
+}
+
+func (u Unit)valid() error {
+    if u!=Kilograms && u!=Pounds {
+        return InvalidUnit(fmt.Sprintf("Unit: %d",u));
+    }
+    return nil;
+}
+
+//Converts a weight in the given unit to kilograms.
+func (u Unit)ToKg(w float64) float64 {
+    if u==Pounds {
+        return w*KgPerLb;
+    }
+    return w;
+}
+
+//Converts a weight in kilograms to the given unit.
+func (u Unit)FromKg(w float64) float64 {
+    if u==Pounds {
+        return w/KgPerLb;
+    }
+    return w;
+}
+
+//Converts a weight between units.
+func ConvertWeight(w float64, from Unit, to Unit) float64 {
+    return to.FromKg(from.ToKg(w));
+}
+
+//Implemented by the tables that have weights and a unit column.
+type weightedRow interface {
+    unit() Unit;
+    setUnit(u Unit);
+    convertWeights(from Unit, to Unit);
+};
+
+func (t *TrainingLog)unit() Unit { return Unit(t.Unit); }
+func (t *TrainingLog)setUnit(u Unit) { t.Unit=int(u); }
+func (t *TrainingLog)convertWeights(from Unit, to Unit) {
+    t.Weight=ConvertWeight(t.Weight,from,to);
+    t.Volume=ConvertWeight(t.Volume,from,to);
+}
+
+func (b *BodyWeight)unit() Unit { return Unit(b.Unit); }
+func (b *BodyWeight)setUnit(u Unit) { b.Unit=int(u); }
+func (b *BodyWeight)convertWeights(from Unit, to Unit) {
+    b.Weight=float32(ConvertWeight(float64(b.Weight),from,to));
+}
+
+func (b *Bar)unit() Unit { return Unit(b.Unit); }
+func (b *Bar)setUnit(u Unit) { b.Unit=int(u); }
+func (b *Bar)convertWeights(from Unit, to Unit) {
+    b.Weight=ConvertWeight(b.Weight,from,to);
+}
+
+func (p *PlatePair)unit() Unit { return Unit(p.Unit); }
+func (p *PlatePair)setUnit(u Unit) { p.Unit=int(u); }
+func (p *PlatePair)convertWeights(from Unit, to Unit) {
+    p.Weight=ConvertWeight(p.Weight,from,to);
+}
+
+func (c *Collar)unit() Unit { return Unit(c.Unit); }
+func (c *Collar)setUnit(u Unit) { c.Unit=int(u); }
+func (c *Collar)convertWeights(from Unit, to Unit) {
+    c.Weight=ConvertWeight(c.Weight,from,to);
+}
+
+func (p *PlannedWorkout)unit() Unit { return Unit(p.Unit); }
+func (p *PlannedWorkout)setUnit(u Unit) { p.Unit=int(u); }
+func (p *PlannedWorkout)convertWeights(from Unit, to Unit) {
+    p.Weight=ConvertWeight(p.Weight,from,to);
+}
+
+func (e *ExerciseMax)unit() Unit { return Unit(e.Unit); }
+func (e *ExerciseMax)setUnit(u Unit) { e.Unit=int(u); }
+func (e *ExerciseMax)convertWeights(from Unit, to Unit) {
+    e.Weight=ConvertWeight(e.Weight,from,to);
+}
+
+//Converts the weights of the row from the rows unit to kilograms. The unit of
+//the row is left unchanged so that it is saved with the row.
+func toCanonical(row any) error {
+    if w,ok:=row.(weightedRow); ok {
+        if err:=w.unit().valid(); err!=nil {
+            return err;
+        }
+        w.convertWeights(w.unit(),Kilograms);
+    }
+    return nil;
+}
+
+//Converts the weights of the row from kilograms to the rows unit.
+func fromCanonical(row any) {
+    if w,ok:=row.(weightedRow); ok {
+        w.convertWeights(Kilograms,w.unit());
+    }
+}
+
+//Returns a copy of the row with its weights converted to the given unit. Rows
+//without weights are returned unchanged. The row is expected to be in its own
+//unit, as returned by Read.
+func InUnit[R DBTable](row R, u Unit) (R,error) {
+    if err:=u.valid(); err!=nil {
+        return row,err;
+    }
+    if w,ok:=any(&row).(weightedRow); ok {
+        if err:=w.unit().valid(); err!=nil {
+            return row,err;
+        }
+        w.convertWeights(w.unit(),u);
+        w.setUnit(u);
+    }
+    return row,nil;
+}
+
+//Reads rows from a CSV file and creates them. The weights in the file are in
+//the unit of each row and are converted to kilograms when they are saved. If
+//the file does not have a Unit column every row is given the default unit.
+func ImportCSV[R DBTable](
+        c *DB,
+        file string,
+        timeDateFormat string,
+        defaultUnit Unit) ([]int,error) {
+    rows,err:=ReadCSV[R](file,timeDateFormat,defaultUnit);
+    if err!=nil {
+        return []int{},err;
+    }
+    return Create(c,rows...);
+}
+
+//Reads rows from a CSV file without creating them, see ImportCSV. The rows are
+//in their own unit so they can be passed directly to Create.
+func ReadCSV[R DBTable](
+        file string,
+        timeDateFormat string,
+        defaultUnit Unit) ([]R,error) {
+    if err:=defaultUnit.valid(); err!=nil {
+        return []R{},err;
+    }
+    headers,err,_:=csv.CSVFileSplitter(file,',','#').Nth(0);
+    if err!=nil {
+        return []R{},err;
+    }
+    hasUnit:=false;
+    for _,h:=range(headers) {
+        hasUnit=hasUnit || h=="Unit";
+    }
+    rows,err:=csv.CSVToStruct[R](
+        csv.CSVFileSplitter(file,',','#'),timeDateFormat,
+    ).Collect();
+    if err!=nil {
+        return []R{},err;
+    }
+    for i,_:=range(rows) {
+        if w,ok:=any(&rows[i]).(weightedRow); ok && !hasUnit {
+            w.setUnit(defaultUnit);
+        }
+    }
+    return rows,nil;
+}
+
+//Writes the rows to a CSV file with every weight converted to the given unit.
+//The rows are expected to be in their own unit, as returned by Read.
+func ExportCSV[R DBTable](
+        file string,
+        rows []R,
+        u Unit,
+        timeDateFormat string) error {
+    converted:=make([]R,len(rows));
+    for i,r:=range(rows) {
+        var err error;
+        if converted[i],err=InUnit(r,u); err!=nil {
+            return err;
+        }
+    }
+    return csv.Flatten(csv.StructToCSV(
+        iter.SliceElems(converted),true,timeDateFormat,
+    ),",").ToFile(file,true);
+}
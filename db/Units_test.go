@@ -0,0 +1,129 @@
+package db;
+
+import (
+    "time"
+    stdMath "math"
+    "testing"
+    "github.com/barbell-math/engine/util/test"
+    "github.com/barbell-math/engine/util/algo"
+)
+
+func TestParseUnit(t *testing.T){
+    for s,exp:=range(map[string]Unit{"kg": Kilograms, "lb": Pounds}) {
+        u,err:=ParseUnit(s);
+        test.BasicTest(nil,err,"Parsing a valid unit returned an error.",t);
+        test.BasicTest(exp,u,"The unit was not parsed correctly.",t);
+        test.BasicTest(s,u.String(),"The unit did not round trip.",t);
+    }
+    if _,err:=ParseUnit("stone"); !IsInvalidUnit(err) {
+        test.FormatError(InvalidUnit(""),err,
+            "Parsing an invalid unit did not return an error.",t,
+        );
+    }
+}
+
+func TestConvertWeight(t *testing.T){
+    test.BasicTest(100.0,ConvertWeight(100,Kilograms,Kilograms),
+        "Converting to the same unit changed the weight.",t,
+    );
+    test.BasicTest(KgPerLb,ConvertWeight(1,Pounds,Kilograms),
+        "Pounds were not converted to kilograms.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(ConvertWeight(100,Kilograms,Pounds)-220.462)<1e-3,
+        "Kilograms were not converted to pounds.",t,
+    );
+    test.BasicTest(true,
+        stdMath.Abs(ConvertWeight(ConvertWeight(315,Pounds,Kilograms),Kilograms,Pounds)-315)<1e-9,
+        "Converting a weight there and back changed the weight.",t,
+    );
+}
+
+func TestCanonicalRows(t *testing.T){
+    tl:=TrainingLog{Weight: 225, Volume: 3375, Unit: int(Pounds)};
+    test.BasicTest(nil,toCanonical(&tl),"Converting a valid row errored.",t);
+    test.BasicTest(225*KgPerLb,tl.Weight,"The weight was not converted.",t);
+    test.BasicTest(3375*KgPerLb,tl.Volume,"The volume was not converted.",t);
+    test.BasicTest(int(Pounds),tl.Unit,"The unit of the row was changed.",t);
+    fromCanonical(&tl);
+    test.BasicTest(true,stdMath.Abs(tl.Weight-225)<1e-9,
+        "The weight was not converted back.",t,
+    );
+    pw:=PlannedWorkout{Weight: 315, Unit: int(Pounds)};
+    test.BasicTest(nil,toCanonical(&pw),"Converting a valid row errored.",t);
+    test.BasicTest(315*KgPerLb,pw.Weight,"The weight was not converted.",t);
+    em:=ExerciseMax{Weight: 405, Unit: int(Pounds)};
+    test.BasicTest(nil,toCanonical(&em),"Converting a valid row errored.",t);
+    test.BasicTest(405*KgPerLb,em.Weight,"The weight was not converted.",t);
+    et:=ExerciseType{T: "T"};
+    test.BasicTest(nil,toCanonical(&et),"A row without weights errored.",t);
+    if err:=toCanonical(&BodyWeight{Unit: 5}); !IsInvalidUnit(err) {
+        test.FormatError(InvalidUnit(""),err,
+            "A row with an invalid unit did not return an error.",t,
+        );
+    }
+}
+
+func TestInUnit(t *testing.T){
+    bw,err:=InUnit(BodyWeight{Weight: 100, Unit: int(Kilograms)},Pounds);
+    test.BasicTest(nil,err,"Converting a row returned an error.",t);
+    test.BasicTest(int(Pounds),bw.Unit,"The unit of the row was not set.",t);
+    test.BasicTest(true,stdMath.Abs(float64(bw.Weight)-220.462)<1e-3,
+        "The weight of the row was not converted.",t,
+    );
+    if _,err:=InUnit(bw,Unit(5)); !IsInvalidUnit(err) {
+        test.FormatError(InvalidUnit(""),err,
+            "Converting to an invalid unit did not return an error.",t,
+        );
+    }
+}
+
+func TestUnitRoundTrip(t *testing.T){
+    setup();
+    Create(&testDB,Client{FirstName: "a", LastName: "b", Email: "a@b.com"});
+    ids,err:=Create(&testDB,BodyWeight{
+        ClientID: 1, Weight: 220, Date: time.Now(), Unit: int(Pounds),
+    });
+    test.BasicTest(nil,err,"Creating a row in pounds returned an error.",t);
+    kg,err,_:=CustomReadQuery[float64](&testDB,
+        "SELECT Weight FROM BodyWeight WHERE Id=$1;",[]any{ids[0]},
+    ).Nth(0);
+    test.BasicTest(nil,err,"Reading the stored weight returned an error.",t);
+    test.BasicTest(true,stdMath.Abs(*kg-220*KgPerLb)<1e-3,
+        "The weight was not stored in kilograms.",t,
+    );
+    bw,err,found:=Read(&testDB,BodyWeight{Id: ids[0]},OnlyIDFilter).Nth(0);
+    test.BasicTest(nil,err,"Reading a row in pounds returned an error.",t);
+    test.BasicTest(true,found,"The row was not found.",t);
+    test.BasicTest(int(Pounds),bw.Unit,"The unit of the row was not saved.",t);
+    test.BasicTest(true,stdMath.Abs(float64(bw.Weight)-220)<1e-3,
+        "The weight was not returned in the rows unit.",t,
+    );
+    cnt,err:=Update(&testDB,BodyWeight{Id: ids[0]},OnlyIDFilter,
+        BodyWeight{Weight: 100, Unit: int(Kilograms)},
+        algo.GenFilter(false,"Weight","Unit"),
+    );
+    test.BasicTest(nil,err,"Updating a rows unit returned an error.",t);
+    test.BasicTest(int64(1),cnt,"The row was not updated.",t);
+    bw,_,_=Read(&testDB,BodyWeight{Id: ids[0]},OnlyIDFilter).Nth(0);
+    test.BasicTest(float32(100),bw.Weight,"The weight was not updated.",t);
+}
+
+func TestUnitCSV(t *testing.T){
+    setup();
+    Create(&testDB,Client{FirstName: "a", LastName: "b", Email: "a@b.com"});
+    now:=time.Date(2023,time.Month(1),1,0,0,0,0,time.UTC);
+    ids,_:=Create(&testDB,BodyWeight{ClientID: 1, Weight: 100, Date: now});
+    row,_,_:=Read(&testDB,BodyWeight{Id: ids[0]},OnlyIDFilter).Nth(0);
+    err:=ExportCSV("testData/unitExport.csv",[]BodyWeight{*row},Pounds,"01/02/2006");
+    test.BasicTest(nil,err,"Exporting rows returned an error.",t);
+    imported,err:=ImportCSV[BodyWeight](&testDB,"testData/unitExport.csv",
+        "01/02/2006",Kilograms,
+    );
+    test.BasicTest(nil,err,"Importing rows returned an error.",t);
+    test.BasicTest(1,len(imported),"The rows were not imported.",t);
+    bw,_,_:=Read(&testDB,BodyWeight{Id: imported[0]},OnlyIDFilter).Nth(0);
+    test.BasicTest(int(Pounds),bw.Unit,"The unit was not imported.",t);
+    test.BasicTest(true,stdMath.Abs(ConvertWeight(float64(bw.Weight),Pounds,Kilograms)-100)<1e-3,
+        "The weight did not survive a round trip through CSV.",t,
+    );
+}
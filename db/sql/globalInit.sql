@@ -7,9 +7,22 @@ DROP TABLE IF EXISTS Client CASCADE;
 DROP TABLE IF EXISTS ExerciseType CASCADE;
 DROP TABLE IF EXISTS ExerciseFocus CASCADE;
 DROP TABLE IF EXISTS ModelState CASCADE;
+DROP TABLE IF EXISTS ModelStateCovariance CASCADE;
 DROP TABLE IF EXISTS Prediction CASCADE;
 DROP TABLE IF EXISTS StateGenerator CASCADE;
 DROP TABLE IF EXISTS PotentialSurface CASCADE;
+DROP TABLE IF EXISTS PlannedWorkout CASCADE;
+DROP TABLE IF EXISTS ExerciseMax CASCADE;
+DROP TABLE IF EXISTS OutlierFlag CASCADE;
+DROP TABLE IF EXISTS ProgramTemplate CASCADE;
+DROP TABLE IF EXISTS ProgramInstance CASCADE;
+DROP TABLE IF EXISTS ProgramInstanceWorkout CASCADE;
+DROP TABLE IF EXISTS Bar CASCADE;
+DROP TABLE IF EXISTS PlatePair CASCADE;
+DROP TABLE IF EXISTS Collar CASCADE;
+DROP FUNCTION IF EXISTS markStale CASCADE;
+DROP FUNCTION IF EXISTS markTrainingLogStale CASCADE;
+DROP FUNCTION IF EXISTS markOutlierFlagStale CASCADE;
 
 CREATE TABLE IF NOT EXISTS Version (
     Num INT NOT NULL
@@ -19,7 +32,8 @@ CREATE TABLE Client (
 	Id SERIAL PRIMARY KEY,
 	FirstName TEXT NOT NULL,
 	LastName TEXT NOT NULL,
-	Email TEXT NOT NULL UNIQUE
+	Email TEXT NOT NULL UNIQUE,
+    Unit INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE TABLE ExerciseType (
@@ -55,6 +69,7 @@ CREATE TABLE BodyWeight (
 	ClientID INTEGER NOT NULL,
 	Weight FLOAT NOT NULL,
     Date DATE NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
 	FOREIGN KEY (ClientID) REFERENCES Client(Id)
 );
 
@@ -72,6 +87,7 @@ CREATE TABLE TrainingLog (
     Volume FLOAT NOT NULL,
     InterExerciseFatigue INT NOT NULL,
     InterWorkoutFatigue INT NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
 	FOREIGN KEY (ClientID) REFERENCES Client(ID),
 	FOREIGN KEY (ExerciseID) REFERENCES Exercise(ID),
 	FOREIGN KEY (RotationID) REFERENCES Rotation(ID)
@@ -80,7 +96,8 @@ CREATE TABLE TrainingLog (
 CREATE TABLE PotentialSurface (
     Id SERIAL PRIMARY KEY,
 	T TEXT NOT NULL UNIQUE,
-	Description TEXT NOT NULL
+	Description TEXT NOT NULL,
+    HistoryDays INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE TABLE StateGenerator (
@@ -108,28 +125,256 @@ CREATE TABLE ModelState (
     Win INTEGER NOT NULL,
     Rcond FLOAT NOT NULL,
     Mse FLOAT NOT NULL,
+    SampleCount INTEGER NOT NULL DEFAULT 0,
+    Sigma FLOAT NOT NULL DEFAULT 0,
+    SelectionCriteria INTEGER NOT NULL DEFAULT 0,
+    Stale BOOLEAN NOT NULL DEFAULT FALSE,
     FOREIGN KEY (ClientID) REFERENCES Client(Id),
     FOREIGN KEY (ExerciseID) REFERENCES Exercise(Id),
     FOREIGN KEY (StateGeneratorID) REFERENCES StateGenerator(Id),
     FOREIGN KEY (PotentialSurfaceID) REFERENCES PotentialSurface(Id)
 );
 
+CREATE TABLE ModelStateCovariance (
+    Id SERIAL PRIMARY KEY,
+    ModelStateID INTEGER NOT NULL,
+    RowIdx INTEGER NOT NULL,
+    ColIdx INTEGER NOT NULL,
+    Val FLOAT NOT NULL,
+    FOREIGN KEY (ModelStateID) REFERENCES ModelState(Id)
+);
+
 CREATE TABLE Prediction (
     Id SERIAL PRIMARY KEY,
     PotentialSurfaceID INTEGER NOT NULL,
     StateGeneratorID INTEGER NOT NULL,
     TrainingLogID INTEGER NOT NULL,
-    IntensityPred FLOAT NOT NULL,
+    PredictedVar INTEGER NOT NULL DEFAULT 0,
+    Val FLOAT NOT NULL,
+    Lower FLOAT NOT NULL DEFAULT 'NaN',
+    Upper FLOAT NOT NULL DEFAULT 'NaN',
+    Stale BOOLEAN NOT NULL DEFAULT FALSE,
     FOREIGN KEY (TrainingLogID) REFERENCES TrainingLog(Id),
     FOREIGN KEY (StateGeneratorID) REFERENCES StateGenerator(Id)
 );
 
+CREATE TABLE PlannedWorkout (
+    Id SERIAL PRIMARY KEY,
+    ClientID INTEGER NOT NULL,
+    ExerciseID INTEGER NOT NULL,
+    StateGeneratorID INTEGER NOT NULL,
+    PotentialSurfaceID INTEGER NOT NULL,
+    DatePlanned DATE NOT NULL,
+    Weight FLOAT NOT NULL,
+    Sets FLOAT NOT NULL,
+    Reps FLOAT NOT NULL,
+    Intensity FLOAT NOT NULL,
+    Effort FLOAT NOT NULL,
+    InterExerciseFatigue INT NOT NULL,
+    InterWorkoutFatigue INT NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (ClientID) REFERENCES Client(Id),
+    FOREIGN KEY (ExerciseID) REFERENCES Exercise(Id),
+    FOREIGN KEY (StateGeneratorID) REFERENCES StateGenerator(Id),
+    FOREIGN KEY (PotentialSurfaceID) REFERENCES PotentialSurface(Id)
+);
+
+CREATE TABLE ExerciseMax (
+    Id SERIAL PRIMARY KEY,
+    ClientID INTEGER NOT NULL,
+    ExerciseID INTEGER NOT NULL,
+    Date DATE NOT NULL,
+    Weight FLOAT NOT NULL,
+    Source INTEGER NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (ClientID) REFERENCES Client(Id),
+    FOREIGN KEY (ExerciseID) REFERENCES Exercise(Id)
+);
+
+CREATE TABLE OutlierFlag (
+    Id SERIAL PRIMARY KEY,
+    TrainingLogID INTEGER NOT NULL,
+    StateGeneratorID INTEGER NOT NULL,
+    PotentialSurfaceID INTEGER NOT NULL,
+    Residual FLOAT NOT NULL,
+    ZScore FLOAT NOT NULL,
+    Excluded BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (TrainingLogID) REFERENCES TrainingLog(Id) ON DELETE CASCADE,
+    FOREIGN KEY (StateGeneratorID) REFERENCES StateGenerator(Id),
+    FOREIGN KEY (PotentialSurfaceID) REFERENCES PotentialSurface(Id)
+);
+
+CREATE TABLE ProgramTemplate (
+    Id SERIAL PRIMARY KEY,
+    Name TEXT NOT NULL UNIQUE,
+    Kind TEXT NOT NULL,
+    Template TEXT NOT NULL
+);
+
+CREATE TABLE ProgramInstance (
+    Id SERIAL PRIMARY KEY,
+    ClientID INTEGER NOT NULL,
+    ProgramTemplateID INTEGER NOT NULL,
+    StateGeneratorID INTEGER NOT NULL,
+    PotentialSurfaceID INTEGER NOT NULL,
+    StartDate DATE NOT NULL,
+    FOREIGN KEY (ClientID) REFERENCES Client(Id),
+    FOREIGN KEY (ProgramTemplateID) REFERENCES ProgramTemplate(Id),
+    FOREIGN KEY (StateGeneratorID) REFERENCES StateGenerator(Id),
+    FOREIGN KEY (PotentialSurfaceID) REFERENCES PotentialSurface(Id)
+);
+
+CREATE TABLE ProgramInstanceWorkout (
+    Id SERIAL PRIMARY KEY,
+    ProgramInstanceID INTEGER NOT NULL,
+    PlannedWorkoutID INTEGER NOT NULL,
+    Mesocycle INTEGER NOT NULL,
+    Week INTEGER NOT NULL,
+    Session INTEGER NOT NULL,
+    Slot INTEGER NOT NULL,
+    FOREIGN KEY (ProgramInstanceID) REFERENCES ProgramInstance(Id) ON DELETE CASCADE,
+    FOREIGN KEY (PlannedWorkoutID) REFERENCES PlannedWorkout(Id) ON DELETE CASCADE
+);
+
+CREATE TABLE Bar (
+    Id SERIAL PRIMARY KEY,
+    ClientID INTEGER NOT NULL,
+    Name TEXT NOT NULL,
+    Weight FLOAT NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (ClientID) REFERENCES Client(Id) ON DELETE CASCADE
+);
+
+CREATE TABLE PlatePair (
+    Id SERIAL PRIMARY KEY,
+    ClientID INTEGER NOT NULL,
+    Weight FLOAT NOT NULL,
+    Pairs INTEGER NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (ClientID) REFERENCES Client(Id) ON DELETE CASCADE
+);
+
+CREATE TABLE Collar (
+    Id SERIAL PRIMARY KEY,
+    ClientID INTEGER NOT NULL,
+    Name TEXT NOT NULL,
+    Weight FLOAT NOT NULL,
+    Unit INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (ClientID) REFERENCES Client(Id) ON DELETE CASCADE
+);
+
 ALTER TABLE ModelState
 ADD CONSTRAINT uniqueDayExerciseClientState
 UNIQUE(ClientID,ExerciseID,StateGeneratorID,PotentialSurfaceID,Date);
 
+ALTER TABLE Bar
+ADD CONSTRAINT uniqueBarClientName
+UNIQUE(ClientID,Name);
+
+ALTER TABLE Collar
+ADD CONSTRAINT uniqueCollarClientName
+UNIQUE(ClientID,Name);
+
+ALTER TABLE ModelStateCovariance
+ADD CONSTRAINT uniqueModelStateCovarianceElem
+UNIQUE(ModelStateID,RowIdx,ColIdx);
+
 ALTER TABLE Prediction
 ADD CONSTRAINT uniqueGeneratorTrainingLogID
-UNIQUE(StateGeneratorID,PotentialSurfaceID,TrainingLogID);
+UNIQUE(StateGeneratorID,PotentialSurfaceID,TrainingLogID,PredictedVar);
+
+ALTER TABLE ExerciseMax
+ADD CONSTRAINT uniqueExerciseMaxSourceDate
+UNIQUE(ClientID,ExerciseID,Date,Source);
+
+ALTER TABLE OutlierFlag
+ADD CONSTRAINT uniqueOutlierFlagTrainingLogID
+UNIQUE(TrainingLogID);
+
+ALTER TABLE ProgramInstanceWorkout
+ADD CONSTRAINT uniqueProgramInstanceWorkout
+UNIQUE(PlannedWorkoutID);
+
+CREATE FUNCTION markStale(
+    cID INTEGER,
+    eID INTEGER,
+    changed DATE
+) RETURNS VOID AS $$
+BEGIN
+    -- Surfaces that use the training history (ex. the fitness and fatigue
+    -- impulses of the banister surface) depend on the training logs up to
+    -- HistoryDays before each data point, so a change can affect their model
+    -- states past the start of their time frame. HistoryDays is set from the
+    -- surface registry when the PotentialSurface table is synced.
+    UPDATE ModelState
+    SET Stale=TRUE
+    FROM PotentialSurface
+    WHERE PotentialSurface.Id=ModelState.PotentialSurfaceID
+        AND ModelState.ClientID=cID
+        AND ModelState.ExerciseID=eID
+        AND ModelState.Date>=changed
+        AND ModelState.Date-ModelState.TimeFrame-PotentialSurface.HistoryDays<=changed;
+    -- A prediction is made with the closest model state before its training
+    -- log. It is stale if that model state is stale, or if that model state is
+    -- before the change because a model state may now be created on the date
+    -- of the change.
+    UPDATE Prediction
+    SET Stale=TRUE
+    FROM TrainingLog
+    WHERE Prediction.TrainingLogID=TrainingLog.Id
+        AND TrainingLog.ClientID=cID
+        AND TrainingLog.ExerciseID=eID
+        AND TrainingLog.DatePerformed>changed
+        AND COALESCE((SELECT ModelState.Stale OR ModelState.Date<changed
+            FROM ModelState
+            WHERE ModelState.ClientID=cID
+                AND ModelState.ExerciseID=eID
+                AND ModelState.StateGeneratorID=Prediction.StateGeneratorID
+                AND ModelState.PotentialSurfaceID=Prediction.PotentialSurfaceID
+                AND ModelState.Date<TrainingLog.DatePerformed
+            ORDER BY ModelState.Date DESC
+            LIMIT 1
+        ),TRUE);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION markTrainingLogStale() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP='UPDATE' OR TG_OP='DELETE' THEN
+        PERFORM markStale(OLD.ClientID,OLD.ExerciseID,OLD.DatePerformed);
+    END IF;
+    IF TG_OP='UPDATE' OR TG_OP='INSERT' THEN
+        PERFORM markStale(NEW.ClientID,NEW.ExerciseID,NEW.DatePerformed);
+    END IF;
+    IF TG_OP='UPDATE' THEN
+        UPDATE Prediction SET Stale=TRUE WHERE Prediction.TrainingLogID=NEW.Id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trainingLogChanged
+AFTER INSERT OR UPDATE OR DELETE ON TrainingLog
+FOR EACH ROW EXECUTE FUNCTION markTrainingLogStale();
+
+CREATE FUNCTION markOutlierFlagStale() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP='UPDATE' OR TG_OP='DELETE' THEN
+        PERFORM markStale(TrainingLog.ClientID,TrainingLog.ExerciseID,TrainingLog.DatePerformed)
+        FROM TrainingLog
+        WHERE TrainingLog.Id=OLD.TrainingLogID AND OLD.Excluded;
+    END IF;
+    IF TG_OP='UPDATE' OR TG_OP='INSERT' THEN
+        PERFORM markStale(TrainingLog.ClientID,TrainingLog.ExerciseID,TrainingLog.DatePerformed)
+        FROM TrainingLog
+        WHERE TrainingLog.Id=NEW.TrainingLogID AND NEW.Excluded;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outlierFlagChanged
+AFTER INSERT OR UPDATE OR DELETE ON OutlierFlag
+FOR EACH ROW EXECUTE FUNCTION markOutlierFlagStale();
 
 INSERT INTO Version(num) VALUES (0);
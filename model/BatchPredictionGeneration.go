@@ -0,0 +1,176 @@
+package model;
+
+import (
+    "sort"
+    "time"
+    "database/sql"
+    stdMath "math"
+    "github.com/barbell-math/engine/db"
+    "github.com/barbell-math/engine/util/algo/iter"
+    potSurf "github.com/barbell-math/engine/model/potentialSurface"
+    stateGen "github.com/barbell-math/engine/model/stateGenerator"
+)
+
+//The number of predictions that are buffered before they are written to the
+//database.
+const PredictionBufSize int=100;
+
+//The result of generating predictions for many training logs at once.
+//  - Generated: predictions that were created and saved
+//  - Skipped: training logs that did not have a model state before them
+//  - Failed: predictions that could not be created or saved
+type PredictionCounts struct {
+    Generated int;
+    Skipped int;
+    Failed int;
+};
+
+type predictionResult struct {
+    pred db.Prediction;
+    skipped bool;
+};
+
+//Generates an intensity prediction for every training log of the client. See
+//GeneratePredictionsForRange.
+func GeneratePredictionsForClient(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        allotedThreads int) (PredictionCounts,error) {
+    return GeneratePredictionsForRange(
+        d,c,sg,surf,time.Time{},time.Date(9999,12,31,0,0,0,0,time.UTC),
+        allotedThreads,
+    );
+}
+
+//Generates an intensity prediction for every training log of the client that
+//was performed between the start and end dates (inclusive). The predictions
+//are the same as the ones GeneratePrediction would create, but all of the
+//model states are read with one query and matched to the training logs in
+//memory. The predictions are generated in parallel and are upserted, so
+//running this again replaces any existing predictions.
+func GeneratePredictionsForRange(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        start time.Time,
+        end time.Time,
+        allotedThreads int) (PredictionCounts,error) {
+    logs,err:=db.CustomReadQuery[db.TrainingLog](d,
+        trainingLogsBetweenDatesQuery(),[]any{c.Id,start,end},
+    ).Collect();
+    if err==sql.ErrNoRows {
+        return PredictionCounts{},nil;
+    } else if err!=nil {
+        return PredictionCounts{},err;
+    }
+    return generatePredictions(d,c,sg,surf,logs,end,allotedThreads);
+}
+
+func generatePredictions(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        logs []*db.TrainingLog,
+        end time.Time,
+        allotedThreads int) (PredictionCounts,error) {
+    rv:=PredictionCounts{};
+    calc,err:=potSurf.CalculationsFromSurfaceId(surf);
+    if err!=nil {
+        return rv,err;
+    }
+    states,err:=modelStatesByExercise(d,c,sg,surf,end);
+    if err!=nil {
+        return rv,err;
+    }
+    bufUpserter,err:=db.NewBufferedUpsert[db.Prediction](PredictionBufSize,
+        "StateGeneratorID","PotentialSurfaceID","TrainingLogID","PredictedVar",
+    );
+    if err!=nil {
+        return rv,err;
+    }
+    err=iter.Parallel[*db.TrainingLog,predictionResult](
+        iter.SliceElems(logs),
+        func(val *db.TrainingLog) (predictionResult,error) {
+            return batchPrediction(d,calc,nearestModelState(states,val),val);
+        }, func(val *db.TrainingLog, res predictionResult, err error) {
+            if res.skipped {
+                rv.Skipped++;
+            } else if err!=nil {
+                rv.Failed++;
+            } else {
+                bufUpserter.Write(d,res.pred);
+            }
+        },allotedThreads,
+    );
+    bufUpserter.Flush(d);
+    rv.Generated=bufUpserter.Succeeded();
+    rv.Failed+=bufUpserter.Failed();
+    return rv,err;
+}
+
+func modelStatesByExercise(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        end time.Time) (map[int][]db.ModelState,error) {
+    rv:=map[int][]db.ModelState{};
+    err:=db.CustomReadQuery[db.ModelState](d,
+        modelStatesBeforeDateQuery(),[]any{c.Id,end,sg,surf},
+    ).ForEach(func(index int, val *db.ModelState) (iter.IteratorFeedback,error) {
+        rv[val.ExerciseID]=append(rv[val.ExerciseID],*val);
+        return iter.Continue,nil;
+    });
+    if err==sql.ErrNoRows {
+        err=nil;
+    }
+    return rv,err;
+}
+
+//Returns the latest model state that is strictly before the training log, or
+//nil if there is none. The model states need to be sorted by date.
+func nearestModelState(
+        states map[int][]db.ModelState,
+        tl *db.TrainingLog) *db.ModelState {
+    s:=states[tl.ExerciseID];
+    i:=sort.Search(len(s),func(i int) bool {
+        return !s[i].Date.Before(tl.DatePerformed);
+    });
+    if i==0 {
+        return nil;
+    }
+    return &s[i-1];
+}
+
+func batchPrediction(
+        d *db.DB,
+        calc potSurf.Calculations,
+        ms *db.ModelState,
+        tl *db.TrainingLog) (predictionResult,error) {
+    if ms==nil {
+        return predictionResult{skipped: true},sql.ErrNoRows;
+    }
+    rv:=predictionResult{pred: db.Prediction{
+        StateGeneratorID: ms.StateGeneratorID,
+        PotentialSurfaceID: ms.PotentialSurfaceID,
+        TrainingLogID: tl.Id,
+        PredictedVar: int(potSurf.IntensityVar),
+        Lower: stdMath.NaN(),
+        Upper: stdMath.NaN(),
+    }};
+    calc,err:=potSurf.CalculationsWithHistory(d,calc,tl);
+    if err!=nil {
+        return rv,err;
+    }
+    rv.pred.Val,err=potSurf.IntensityVar.Solve(calc,ms,tl);
+    if err!=nil {
+        return rv,err;
+    }
+    h,err:=stateGen.IntensityHalfWidth(ms,potSurf.DefaultPredictionConfidence);
+    rv.pred.Lower,rv.pred.Upper=potSurf.IntensityVar.Interval(calc,ms,tl,h);
+    return rv,err;
+}
@@ -4,6 +4,8 @@ import (
     "github.com/barbell-math/engine/db"
 )
 
+//Stale model states are skipped, they are fit with training logs that have
+//since changed and will be removed when the client is regenerated.
 func nearestModelStateToExerciseQuery(tl *db.TrainingLog) string {
     return `SELECT ModelState.*
         FROM TrainingLog
@@ -16,6 +18,82 @@ func nearestModelStateToExerciseQuery(tl *db.TrainingLog) string {
             AND ModelState.StateGeneratorID=$3
             AND ModelState.PotentialSurfaceID=$4
             AND TrainingLog.ClientID=$5
+            AND NOT ModelState.Stale
         ORDER BY TrainingLog.DatePerformed DESC
         LIMIT 1;`;
 }
+
+func trainingLogsBetweenDatesQuery() string {
+    return `SELECT *
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.DatePerformed>=$2
+            AND TrainingLog.DatePerformed<=$3
+        ORDER BY TrainingLog.DatePerformed ASC, TrainingLog.Id ASC;`;
+}
+
+//Selects the same model states that nearestModelStateToExerciseQuery would
+//select from, but for every exercise of the client at once.
+func modelStatesBeforeDateQuery() string {
+    return `SELECT DISTINCT ModelState.*
+        FROM TrainingLog
+        JOIN ModelState
+        ON TrainingLog.ExerciseID=ModelState.ExerciseID
+            AND TrainingLog.ClientID=ModelState.ClientID
+            AND TrainingLog.DatePerformed=ModelState.Date
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.DatePerformed<$2
+            AND ModelState.StateGeneratorID=$3
+            AND ModelState.PotentialSurfaceID=$4
+            AND NOT ModelState.Stale
+        ORDER BY ModelState.ExerciseID ASC, ModelState.Date ASC;`;
+}
+
+func earliestStaleModelStateQuery() string {
+    return `SELECT ModelState.Date
+        FROM ModelState
+        WHERE ModelState.ClientID=$1
+            AND ModelState.StateGeneratorID=$2
+            AND ModelState.Stale
+        ORDER BY ModelState.Date ASC
+        LIMIT 1;`;
+}
+
+//Every surface of a stale model state is removed so that the missing model
+//state query will find the date again.
+func staleModelStatesSubQuery() string {
+    return `SELECT ms.Id
+        FROM ModelState ms
+        JOIN ModelState stale
+        ON ms.ClientID=stale.ClientID
+            AND ms.ExerciseID=stale.ExerciseID
+            AND ms.StateGeneratorID=stale.StateGeneratorID
+            AND ms.Date=stale.Date
+        WHERE stale.ClientID=$1
+            AND stale.StateGeneratorID=$2
+            AND stale.Stale`;
+}
+
+func deleteStaleModelStateCovarianceQuery() string {
+    return `DELETE FROM ModelStateCovariance
+        WHERE ModelStateCovariance.ModelStateID IN (`+
+        staleModelStatesSubQuery()+`);`;
+}
+
+func deleteStaleModelStatesQuery() string {
+    return `DELETE FROM ModelState
+        WHERE ModelState.Id IN (`+staleModelStatesSubQuery()+`);`;
+}
+
+func staleTrainingLogsQuery() string {
+    return `SELECT DISTINCT TrainingLog.*
+        FROM TrainingLog
+        JOIN Prediction
+        ON Prediction.TrainingLogID=TrainingLog.Id
+        WHERE TrainingLog.ClientID=$1
+            AND Prediction.StateGeneratorID=$2
+            AND Prediction.PotentialSurfaceID=$3
+            AND Prediction.PredictedVar=$4
+            AND Prediction.Stale
+        ORDER BY TrainingLog.DatePerformed ASC, TrainingLog.Id ASC;`;
+}
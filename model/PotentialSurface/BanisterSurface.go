@@ -0,0 +1,325 @@
+package potentialSurface
+
+import (
+	"fmt"
+	stdMath "math"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/dataStruct"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+)
+
+//The time constants (in days) that control how quickly the fitness and fatigue
+//impulses decay. These are the values commonly used with the Banister model.
+const (
+    BanisterFitnessTimeConstant float64=42
+    BanisterFatigueTimeConstant float64=7
+    //Training logs older than this many days are not included in the impulses.
+    //At five fitness time constants a sessions contribution is <1% of its
+    //original value. The surfaces registration saves this to the PotentialSurface
+    //table so the markStale function in globalInit.sql uses the same number of
+    //days.
+    BanisterHistoryDays int=5*42
+);
+
+//The banister surface follows the following equation:
+//  I=eps+eps_1*E+eps_2*F_fit-( eps_3*F_fat+eps_4*(s-1)^2(r-1)^2+eps_5*(s-1)^2+eps_6*(r-1)^2 )
+// Where:
+//  F_fit=sum(s_i*r_i*I_i*exp(-(t-t_i)/tau_fit))
+//  F_fat=sum(s_i*r_i*I_i*exp(-(t-t_i)/tau_fat))
+//The sums are taken over all previous training logs for the same client and
+//exercise. Unlike the basic and volume base surfaces this equation accounts for
+//latent fatigue by accumulating decaying fitness and fatigue impulses from the
+//training history. The time constants are fixed so that the remaining
+//constants can be found using linear regression.
+var BanisterSurfaceCalculation banisterSurfaceCalculation;
+type banisterSurfaceCalculation struct {
+    fitness float64;
+    fatigue float64;
+};
+
+//Calculations that depend on the training history rather than a single
+//training log. The impulses at the time of the training log must be given
+//before any of the calculations are made.
+type ImpulseCalculations interface {
+    Calculations;
+    WithImpulses(fitness float64, fatigue float64) Calculations;
+};
+
+//Returns calculations that are ready to be used with the given training log.
+//If the calculations depend on the training history then the impulses at the
+//training logs date are queried from the database, otherwise the calculations
+//are returned unchanged.
+func CalculationsWithHistory(
+        d *db.DB,
+        c Calculations,
+        tl *db.TrainingLog) (Calculations,error) {
+    ic,ok:=c.(ImpulseCalculations);
+    if !ok {
+        return c,nil;
+    }
+    imp,err,found:=db.CustomReadQuery[banisterImpulses](d,
+        banisterImpulseQuery(),[]any{
+            tl.DatePerformed,tl.ClientID,tl.ExerciseID,
+    }).Nth(0);
+    if err!=nil {
+        return c,err;
+    } else if !found {
+        return ic.WithImpulses(0,0),nil;
+    }
+    return ic.WithImpulses(imp.Fitness,imp.Fatigue),nil;
+}
+
+//Note - THE ORDER OF THE STRUCT FIELDS MUST MATCH THE ORDER OF THE VALUES
+//IN THE QUERY.
+type banisterImpulses struct {
+    Fitness float64;
+    Fatigue float64;
+};
+
+func (b banisterSurfaceCalculation)WithImpulses(
+        fitness float64,
+        fatigue float64) Calculations {
+    b.fitness=fitness;
+    b.fatigue=fatigue;
+    return b;
+}
+
+//The part of the equation that does not depend on sets or reps.
+func (b banisterSurfaceCalculation)base(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return ms.Eps+ms.Eps1*tl.Effort+ms.Eps2*b.fitness-ms.Eps3*b.fatigue;
+}
+
+func (b banisterSurfaceCalculation)Intensity(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return (b.base(ms,tl)-
+        ms.Eps4*stdMath.Pow(tl.Sets-1,2)*stdMath.Pow(tl.Reps-1,2)-
+        ms.Eps5*stdMath.Pow(tl.Sets-1,2)-
+        ms.Eps6*stdMath.Pow(tl.Reps-1,2));
+}
+
+func (b banisterSurfaceCalculation)Effort(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return (tl.Intensity-ms.Eps-
+        ms.Eps2*b.fitness+
+        ms.Eps3*b.fatigue+
+        ms.Eps4*stdMath.Pow(tl.Sets-1,2)*stdMath.Pow(tl.Reps-1,2)+
+        ms.Eps5*stdMath.Pow(tl.Sets-1,2)+
+        ms.Eps6*stdMath.Pow(tl.Reps-1,2))/ms.Eps1;
+}
+
+func (b banisterSurfaceCalculation)Sets(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return stdMath.Pow((
+        b.base(ms,tl)-
+        ms.Eps6*stdMath.Pow(tl.Reps-1,2)-
+        tl.Intensity)/(
+        ms.Eps4*stdMath.Pow(tl.Reps-1,2)+
+        ms.Eps5),0.5)+1.0;
+}
+
+func (b banisterSurfaceCalculation)Reps(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return stdMath.Pow((
+        b.base(ms,tl)-
+        ms.Eps5*stdMath.Pow(tl.Sets-1,2)-
+        tl.Intensity)/(
+        ms.Eps4*stdMath.Pow(tl.Sets-1,2)+
+        ms.Eps6),0.5)+1.0;
+}
+
+//The banister surface replaces inter workout and inter exercise fatigue with
+//the fatigue impulse, so neither value can be solved for.
+func (b banisterSurfaceCalculation)InterWorkoutFatigue(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return stdMath.NaN();
+}
+
+func (b banisterSurfaceCalculation)InterExerciseFatigue(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return stdMath.NaN();
+}
+
+//The banister surface has the same shape in sets and reps as the basic
+//surface, only the part of the equation that does not depend on sets or reps
+//is different. The volume skew is found from the basic surface with that part
+//folded into eps.
+func (b banisterSurfaceCalculation)asBasic(
+        ms *db.ModelState,
+        tl *db.TrainingLog) *db.ModelState {
+    return &db.ModelState{
+        Eps: b.base(ms,tl), Eps4: ms.Eps4, Eps5: ms.Eps5, Eps6: ms.Eps6,
+    };
+}
+
+func (b banisterSurfaceCalculation)VolumeSkew(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return BasicSurfaceCalculation.VolumeSkew(b.asBasic(ms,tl),tl);
+}
+
+func (b banisterSurfaceCalculation)VolumeSkewApprox(
+        ms *db.ModelState,
+        tl *db.TrainingLog) float64 {
+    return BasicSurfaceCalculation.VolumeSkewApprox(b.asBasic(ms,tl),tl);
+}
+
+func (b banisterSurfaceCalculation)Stability(ms *db.ModelState) int {
+    rv:=0;
+    for _,v:=range([]float64{
+        ms.Eps,ms.Eps1,ms.Eps2,ms.Eps3,ms.Eps4,ms.Eps5,ms.Eps6,
+    }) {
+        if v>0 {
+            rv++;
+        }
+    }
+    return rv;
+}
+
+type BanisterSurface struct {
+    mathUtil.LinearReg[float64];
+    mathUtil.LinRegResult[float64];
+};
+
+//The ordering of the functions makes for this ordering of constants:
+//  Eps,Eps1,Eps2,Eps3,Eps4,Eps5,Eps6
+//The surface expects the fitness and fatigue impulses to be supplied in the
+//F_fit and F_fat variables.
+func NewBanisterSurface() BanisterSurface {
+    rv:=BanisterSurface{
+        LinearReg: mathUtil.NewLinearReg([]mathUtil.SummationOp[float64]{
+            mathUtil.ConstSummationOp[float64](1),
+            mathUtil.LinearSummationOp[float64]("E"),
+            mathUtil.LinearSummationOp[float64]("F_fit"),
+            mathUtil.NegatedLinearSummationOp[float64]("F_fat"),
+            func(vals mathUtil.Vars[float64]) (float64,error) {
+                s,err:=vals.Access("S");
+                if err!=nil {
+                    return 0, err;
+                }
+                r,err:=vals.Access("R");
+                if err!=nil {
+                    return 0, err;
+                }
+                return -(stdMath.Pow(s-1,2)*stdMath.Pow(r-1,2)),nil;
+            }, func(vals mathUtil.Vars[float64]) (float64,error) {
+                s,err:=vals.Access("S");
+                if err!=nil {
+                    return 0, err;
+                }
+                return -stdMath.Pow(s-1,2),nil;
+            }, func(vals mathUtil.Vars[float64]) (float64,error) {
+                r,err:=vals.Access("R");
+                if err!=nil {
+                    return 0, err;
+                }
+                return -stdMath.Pow(r-1,2),nil;
+            }},mathUtil.LinearSummationOp[float64]("I"),
+        ),
+    };
+    rv.SetBounds(banisterSurfaceBounds());
+    return rv;
+}
+
+func (b BanisterSurface)ToGenericSurf() Surface { return &b; }
+
+func (b *BanisterSurface)Id() PotentialSurfaceId { return BanisterSurfaceId; }
+func (b *BanisterSurface)Calculations() Calculations { return BanisterSurfaceCalculation; }
+
+func (b *BanisterSurface)Update(vals mathUtil.Vars[float64]) error {
+    return b.UpdateSummationsWeighted(vals,sampleWeight(vals));
+}
+
+func (b *BanisterSurface)Remove(vals mathUtil.Vars[float64]) error {
+    return b.RemoveSummationsWeighted(vals,sampleWeight(vals));
+}
+
+//Fits the surface using linear regression. The constants are fit under
+//non-negative bounds so that fitness always increases intensity and fatigue
+//and volume always decrease it.
+func (b *BanisterSurface)Run() (float64,error) {
+    res,rcond,err:=b.LinearReg.Run();
+    b.LinRegResult=res;
+    return rcond,err;
+}
+
+//The bounds that the constants are fit under.
+func banisterSurfaceBounds() []dataStruct.Pair[float64,float64] {
+    return []dataStruct.Pair[float64,float64]{
+        mathUtil.PositiveConstraint[float64](), //Eps: Error
+        mathUtil.PositiveConstraint[float64](), //Eps1: Effort
+        mathUtil.PositiveConstraint[float64](), //Eps2: F_fit
+        mathUtil.PositiveConstraint[float64](), //Eps3: F_fat
+        mathUtil.PositiveConstraint[float64](), //Eps4: s*r
+        mathUtil.PositiveConstraint[float64](), //Eps5: s
+        mathUtil.PositiveConstraint[float64](), //Eps6: r
+    };
+}
+
+func (b *BanisterSurface)PredictIntensity(vals mathUtil.Vars[float64]) (float64,error) {
+    return b.LinRegResult.Predict(vals);
+}
+
+func (b *BanisterSurface)Stability() int {
+    rv:=0;
+    for _,v:=range(b.LinRegResult.V) {
+        if v[0]>0 {
+            rv++;
+        }
+    }
+    return rv;
+}
+
+//Returns the SQL condition that removes the training logs from the table (or
+//alias) that were flagged as outliers and excluded. Excluded training logs are
+//never used to generate model states, including through the impulses of the
+//training logs that follow them. The flag is kept in a separate table so the
+//training log is left as it was entered.
+func NotExcludedOutlierSql(table string) string {
+    return fmt.Sprintf(`NOT EXISTS (SELECT 1
+                FROM OutlierFlag
+                WHERE OutlierFlag.TrainingLogID=%s.Id
+                    AND OutlierFlag.Excluded
+            )`,table);
+}
+
+//Returns the SQL expression for an impulse with the given time constant. The
+//expression is a correlated sub-query against the TrainingLog table in the
+//outer query, so the outer query must select from TrainingLog without an alias.
+//The impulse is zero if there is no training history.
+func BanisterImpulseSql(timeConstant float64) string {
+    return fmt.Sprintf(`(SELECT COALESCE(SUM(
+                hist.Sets*hist.Reps*hist.Intensity*
+                EXP(-(TrainingLog.DatePerformed-hist.DatePerformed)/%f)
+            ),0)
+            FROM TrainingLog hist
+            WHERE hist.ClientID=TrainingLog.ClientID
+                AND hist.ExerciseID=TrainingLog.ExerciseID
+                AND hist.DatePerformed<TrainingLog.DatePerformed
+                AND hist.DatePerformed>=TrainingLog.DatePerformed-%d
+                AND %s
+        )`,timeConstant,BanisterHistoryDays,NotExcludedOutlierSql("hist"));
+}
+
+func banisterImpulseQuery() string {
+    return fmt.Sprintf(`SELECT
+            COALESCE(SUM(Sets*Reps*Intensity*EXP(-($1::DATE-DatePerformed)/%f)),0),
+            COALESCE(SUM(Sets*Reps*Intensity*EXP(-($1::DATE-DatePerformed)/%f)),0)
+        FROM TrainingLog
+        WHERE TrainingLog.DatePerformed<$1
+            AND TrainingLog.DatePerformed>=$1::DATE-%d
+            AND TrainingLog.ClientID=$2
+            AND TrainingLog.ExerciseID=$3
+            AND %s;`,
+        BanisterFitnessTimeConstant,BanisterFatigueTimeConstant,
+        BanisterHistoryDays,NotExcludedOutlierSql("TrainingLog"),
+    );
+}
@@ -0,0 +1,171 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+	"strings"
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func banisterTestValues() (db.ModelState,db.TrainingLog,Calculations) {
+    ms:=db.ModelState{
+        Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+        Eps4: 2, Eps5: 1, Eps6: 0.5,
+    };
+    tl:=db.TrainingLog{
+        Weight: 0, Sets: 2, Reps: 2, Intensity: 0, Effort: 10,
+    };
+    return ms,tl,BanisterSurfaceCalculation.WithImpulses(4,2);
+}
+
+func TestBanisterSurfaceCreation(t *testing.T){
+    cntr:=0;
+    m:=NewBanisterSurface();
+    m.IterLHS(func(r int, c int, v float64){
+        cntr++;
+    });
+    test.BasicTest(49,cntr,"LHS Lin reg wrong size for model.",t);
+    cntr=0;
+    m.IterRHS(func(r int, c int, v float64){
+        cntr++;
+    });
+    test.BasicTest(7,cntr,"RHS Lin reg wrong size for model.",t);
+}
+
+func TestBanisterSurfaceIntensityPrediction(t *testing.T){
+    ms,tl,c:=banisterTestValues();
+    test.BasicTest(float64(53.5),c.Intensity(&ms,&tl),
+        "Intensity prediction produced incorrect value.",t,
+    );
+    test.BasicTest(float64(51.5),
+        BanisterSurfaceCalculation.Intensity(&ms,&tl),
+        "Intensity prediction without impulses produced incorrect value.",t,
+    );
+}
+
+func TestBanisterSurfaceEffortPrediction(t *testing.T){
+    ms,tl,c:=banisterTestValues();
+    test.BasicTest(true,stdMath.Abs(-0.7-c.Effort(&ms,&tl))<1e-6,
+        "Effort prediction produced incorrect value.",t,
+    );
+    tl.Intensity=c.Intensity(&ms,&tl);
+    test.BasicTest(true,stdMath.Abs(10-c.Effort(&ms,&tl))<1e-6,
+        "Effort prediction was not the inverse of intensity.",t,
+    );
+}
+
+func TestBanisterSurfaceSetsPrediction(t *testing.T){
+    ms,tl,c:=banisterTestValues();
+    test.BasicTest(true,stdMath.Abs(
+        stdMath.Pow(56.5/3,0.5)+1-c.Sets(&ms,&tl))<1e-6,
+        "Sets prediction produced incorrect value.",t,
+    );
+    tl.Intensity=c.Intensity(&ms,&tl);
+    test.BasicTest(true,stdMath.Abs(2-c.Sets(&ms,&tl))<1e-6,
+        "Sets prediction was not the inverse of intensity.",t,
+    );
+}
+
+func TestBanisterSurfaceRepsPrediction(t *testing.T){
+    ms,tl,c:=banisterTestValues();
+    test.BasicTest(true,stdMath.Abs(
+        stdMath.Pow(56.0/2.5,0.5)+1-c.Reps(&ms,&tl))<1e-6,
+        "Reps prediction produced incorrect value.",t,
+    );
+    tl.Intensity=c.Intensity(&ms,&tl);
+    test.BasicTest(true,stdMath.Abs(2-c.Reps(&ms,&tl))<1e-6,
+        "Reps prediction was not the inverse of intensity.",t,
+    );
+}
+
+func TestBanisterSurfaceFatiguePrediction(t *testing.T){
+    ms,tl,c:=banisterTestValues();
+    test.BasicTest(true,stdMath.IsNaN(c.InterWorkoutFatigue(&ms,&tl)),
+        "Inter workout fatigue should not be solvable for the banister surface.",t,
+    );
+    test.BasicTest(true,stdMath.IsNaN(c.InterExerciseFatigue(&ms,&tl)),
+        "Inter exercise fatigue should not be solvable for the banister surface.",t,
+    );
+}
+
+func TestBanisterSurfaceVolumeSkew(t *testing.T){
+    ms,tl,c:=banisterTestValues();
+    //eps+eps_1*E+eps_2*F_fit-eps_3*F_fat=5+5*10+4-2
+    basic:=db.ModelState{Eps: 57, Eps4: 2, Eps5: 1, Eps6: 0.5};
+    test.BasicTest(BasicSurfaceCalculation.VolumeSkew(&basic,&tl),
+        c.VolumeSkew(&ms,&tl),
+        "The volume skew did not match the equivalent basic surface.",t,
+    );
+    test.BasicTest(BasicSurfaceCalculation.VolumeSkewApprox(&basic,&tl),
+        c.VolumeSkewApprox(&ms,&tl),
+        "The volume skew approx did not match the equivalent basic surface.",t,
+    );
+    test.BasicTest(false,stdMath.IsNaN(c.VolumeSkew(&ms,&tl)),
+        "The volume skew was NaN.",t,
+    );
+    test.BasicTest(true,c.VolumeSkew(&ms,&tl)>0,
+        "The volume skew was not positive.",t,
+    );
+}
+
+func TestBanisterSurfaceStability(t *testing.T){
+    ms,_,c:=banisterTestValues();
+    test.BasicTest(7,c.Stability(&ms),"Stability was not calculated correctly.",t);
+    ms.Eps3=0;
+    test.BasicTest(6,c.Stability(&ms),"Stability was not calculated correctly.",t);
+}
+
+func TestBanisterSurfaceFit(t *testing.T){
+    ms,_,_:=banisterTestValues();
+    s:=NewBanisterSurface();
+    for i:=0; i<200; i++ {
+        tl:=db.TrainingLog{
+            Sets: float64(1+i%5),
+            Reps: float64(1+(i/5)%8),
+            Effort: float64(6+i%7)/2,
+        };
+        fit,fat:=float64(i%11)*3,float64(i%13);
+        tl.Intensity=BanisterSurfaceCalculation.WithImpulses(fit,fat).Intensity(
+            &ms,&tl,
+        );
+        err:=s.Update(map[string]float64{
+            "I": tl.Intensity, "E": tl.Effort, "S": tl.Sets, "R": tl.Reps,
+            "F_fit": fit, "F_fat": fat,
+        });
+        test.BasicTest(nil,err,"Updating the surface returned an error.",t);
+    }
+    _,err:=s.Run();
+    test.BasicTest(nil,err,"Fitting the surface returned an error.",t);
+    for i,v:=range([]float64{
+        ms.Eps,ms.Eps1,ms.Eps2,ms.Eps3,ms.Eps4,ms.Eps5,ms.Eps6,
+    }) {
+        test.BasicTest(true,stdMath.Abs(v-s.GetConstant(i))<1e-6,
+            "Fitting the surface did not recover the constants.",t,
+        );
+    }
+    test.BasicTest(7,s.Stability(),"The fitted surface was not stable.",t);
+}
+
+func TestBanisterSurfaceRegistered(t *testing.T){
+    c,err:=CalculationsFromSurfaceId(BanisterSurfaceId);
+    test.BasicTest(nil,err,"The banister surface was not registered.",t);
+    if _,ok:=c.(ImpulseCalculations); !ok {
+        test.FormatError("ImpulseCalculations",c,
+            "The banister calculations do not accept impulses.",t,
+        );
+    }
+    _,ok:=NewBanisterSurface().ToGenericSurf().(LinearSurface);
+    test.BasicTest(true,ok,"The banister surface is not a linear surface.",t);
+}
+
+func TestBanisterImpulseSqlExcludesOutliers(t *testing.T){
+    test.BasicTest(true,strings.Contains(
+        BanisterImpulseSql(BanisterFitnessTimeConstant),
+        "OutlierFlag.TrainingLogID=hist.Id",
+    ),"The impulse history did not exclude outliers.",t);
+    test.BasicTest(true,strings.Contains(
+        banisterImpulseQuery(),"OutlierFlag.TrainingLogID=TrainingLog.Id",
+    ),"The impulse query did not exclude outliers.",t);
+}
@@ -102,6 +102,13 @@ func (b basicSurfaceCalculation)VolumeSkew(
 func (b basicSurfaceCalculation)volumeSkewDiagonal(
     ms *db.ModelState,
     tl *db.TrainingLog) float64 {
+    if ms.Eps4==0 {
+        return stdMath.Pow(stdMath.Max((
+            ms.Eps+
+            ms.Eps1*tl.Effort-
+            ms.Eps2*float64(tl.InterWorkoutFatigue)-
+            ms.Eps3*float64(tl.InterExerciseFatigue))/(ms.Eps5+ms.Eps6),0),0.5)+1;
+    }
     return stdMath.Pow(stdMath.Max((-ms.Eps5-ms.Eps6+
         stdMath.Pow(stdMath.Max(stdMath.Pow(ms.Eps5+ms.Eps6,2)+
             4*ms.Eps4*(
@@ -229,24 +236,26 @@ func (b basicSurfaceCalculation)volumeSkewIntegral4(
     return rv;
 }
 
+//Approximates the volume skew by ignoring the eps_4 term, which turns the
+//feasible region into a quarter ellipse. In elliptical coordinates the
+//intensity is A*(1-p^2), where A is the value of the surface at s=r=1, so the
+//radial integrals are constant. See ellipseVolumeSkewApprox for details.
+//The approximation is exact when eps_4 is 0.
 func (b basicSurfaceCalculation)VolumeSkewApprox(
     ms *db.ModelState,
     tl *db.TrainingLog) float64 {
-    return (stdMath.Pow(ms.Eps6,0.5)*(
-        stdMath.Pow(
-            ms.Eps+
-            ms.Eps1*tl.Effort-
-            ms.Eps2*float64(tl.InterWorkoutFatigue)-
-            ms.Eps3*float64(tl.InterExerciseFatigue),
-            0.5,
-        )+stdMath.Pow(ms.Eps5,0.5)))/(stdMath.Pow(ms.Eps5,0.5)*(
-        stdMath.Pow(
-            ms.Eps+
-            ms.Eps1*tl.Effort-
-            ms.Eps2*float64(tl.InterWorkoutFatigue)-
-            ms.Eps3*float64(tl.InterExerciseFatigue),
-            0.5,
-        )+stdMath.Pow(ms.Eps6,0.5)));
+    a:=(ms.Eps+
+        ms.Eps1*tl.Effort-
+        ms.Eps2*float64(tl.InterWorkoutFatigue)-
+        ms.Eps3*float64(tl.InterExerciseFatigue));
+    if !(a>0) {
+        return stdMath.NaN();
+    }
+    return ellipseVolumeSkewApprox(
+        stdMath.Sqrt(a/ms.Eps5),
+        stdMath.Sqrt(a/ms.Eps6),
+        [3]float64{1.0/4.0,2.0/15.0,1.0/12.0},
+    );
 }
 
 func (b basicSurfaceCalculation)Stability(ms *db.ModelState) int {
@@ -287,7 +296,7 @@ type BasicSurface struct {
 //The ordering of the functions makes for this ordering of constants:
 //  Eps,Eps1,Eps2,Eps3,Eps4,Eps5,Eps6
 func NewBasicSurface() BasicSurface {
-    return BasicSurface{
+    rv:=BasicSurface{
         LinearReg: mathUtil.NewLinearReg([]mathUtil.SummationOp[float64]{
             mathUtil.ConstSummationOp[float64](1),
             mathUtil.LinearSummationOp[float64]("E"),
@@ -318,6 +327,17 @@ func NewBasicSurface() BasicSurface {
             }},mathUtil.LinearSummationOp[float64]("I"),
         ),
     };
+    rv.SetBounds(basicSurfaceBounds());
+    return rv;
+}
+
+//The same as NewBasicSurface except that the regularization is used when running
+//the regression. See mathUtil.Regularization.
+func NewBasicSurfaceWithRegularization(
+        r mathUtil.Regularization[float64]) (BasicSurface,error) {
+    rv:=NewBasicSurface();
+    err:=rv.SetRegularization(r);
+    return rv,err;
 }
 
 func (b BasicSurface)ToGenericSurf() Surface { return &b; }
@@ -326,18 +346,22 @@ func (b *BasicSurface)Id() PotentialSurfaceId { return BasicSurfaceId; }
 func (b *BasicSurface)Calculations() Calculations { return BasicSurfaceCalculation; }
 
 func (b *BasicSurface)Update(vals mathUtil.Vars[float64]) error {
-    return b.UpdateSummations(vals);
+    return b.UpdateSummationsWeighted(vals,sampleWeight(vals));
+}
+
+func (b *BasicSurface)Remove(vals mathUtil.Vars[float64]) error {
+    return b.RemoveSummationsWeighted(vals,sampleWeight(vals));
 }
 
 func (b *BasicSurface)Run() (float64,error) {
     res,rcond,err:=b.LinearReg.Run();
     b.LinRegResult=res;
-    b.imposeConstraints();
     return rcond,err;
 }
 
-func (b *BasicSurface)imposeConstraints() {
-    var constraints=[...]dataStruct.Pair[float64,float64]{
+//The bounds that the constants are fit under.
+func basicSurfaceBounds() []dataStruct.Pair[float64,float64] {
+    return []dataStruct.Pair[float64,float64]{
         mathUtil.PositiveConstraint[float64](), //Eps: Error
         mathUtil.PositiveConstraint[float64](), //Eps1: Effort
         mathUtil.PositiveConstraint[float64](), //Eps2: F_w
@@ -346,9 +370,6 @@ func (b *BasicSurface)imposeConstraints() {
         mathUtil.PositiveConstraint[float64](), //Eps5: s
         mathUtil.PositiveConstraint[float64](), //Eps6: r
     };
-    for i,v:=range(b.LinRegResult.V) {
-        b.LinRegResult.V[i][0]=mathUtil.Constrain(v[0],constraints[i]);
-    }
 }
 
 func (b *BasicSurface)PredictIntensity(vals mathUtil.Vars[float64]) (float64,error) {
@@ -6,6 +6,8 @@ import (
 
 	"github.com/barbell-math/engine/db"
 	"github.com/barbell-math/engine/util/test"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
 )
 
 //All seemingly magic numbers come from: https://www.desmos.com/calculator/9zxa3zuum0
@@ -235,6 +237,73 @@ func TestBasicSurfaceVolumeSkewReps(t *testing.T){
     );
 }
 
+func TestBasicSurfaceVolumeSkewApproxSymmetrical(t *testing.T){
+    ms:=db.ModelState{
+        Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+        Eps4: 2, Eps5: 1, Eps6: 1,
+    };
+    tl:=db.TrainingLog{
+        Weight: 0, Sets: 0, Reps: 0, Intensity: 0, Effort: 10,
+        InterWorkoutFatigue: 1, InterExerciseFatigue: 1,
+    };
+    test.BasicTest(true,stdMath.Abs(
+        1-BasicSurfaceCalculation.VolumeSkewApprox(&ms,&tl))<1e-6,
+        "The approximate volume skew result was not correct.",t,
+    );
+}
+
+func TestBasicSurfaceVolumeSkewApproxSetsAndReps(t *testing.T){
+    for _,eps:=range([][2]float64{{0.5,1},{1,0.5}}) {
+        ms:=db.ModelState{
+            Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+            Eps4: 2, Eps5: eps[0], Eps6: eps[1],
+        };
+        tl:=db.TrainingLog{
+            Weight: 0, Sets: 0, Reps: 0, Intensity: 0, Effort: 10,
+            InterWorkoutFatigue: 1, InterExerciseFatigue: 1,
+        };
+        exact:=BasicSurfaceCalculation.VolumeSkew(&ms,&tl);
+        approx:=BasicSurfaceCalculation.VolumeSkewApprox(&ms,&tl);
+        test.BasicTest(exact>1,approx>1,
+            "The approximate volume skew did not skew the same way as the exact value.",t,
+        );
+    }
+}
+
+func TestBasicSurfaceVolumeSkewApproxNoCrossTerm(t *testing.T){
+    //Without the cross term the feasible region is an ellipse and the
+    //approximation is exact
+    for _,eps:=range([][2]float64{{0.5,1},{1,0.5},{1,1}}) {
+        ms:=db.ModelState{
+            Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+            Eps4: 0, Eps5: eps[0], Eps6: eps[1],
+        };
+        tl:=db.TrainingLog{
+            Weight: 0, Sets: 0, Reps: 0, Intensity: 0, Effort: 10,
+            InterWorkoutFatigue: 1, InterExerciseFatigue: 1,
+        };
+        test.BasicTest(true,stdMath.Abs(
+            BasicSurfaceCalculation.VolumeSkew(&ms,&tl)-
+            BasicSurfaceCalculation.VolumeSkewApprox(&ms,&tl))<1e-5,
+            "The approximate volume skew did not match the exact value.",t,
+        );
+    }
+}
+
+func TestBasicSurfaceVolumeSkewApproxInfeasible(t *testing.T){
+    ms:=db.ModelState{
+        Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+        Eps4: 2, Eps5: 1, Eps6: 0.5,
+    };
+    tl:=db.TrainingLog{
+        Weight: 0, Sets: 0, Reps: 0, Intensity: 0, Effort: 0,
+        InterWorkoutFatigue: 10, InterExerciseFatigue: 10,
+    };
+    test.BasicTest(true,stdMath.IsNaN(BasicSurfaceCalculation.VolumeSkewApprox(&ms,&tl)),
+        "The approximate volume skew of an empty region was not NaN.",t,
+    );
+}
+
 func BenchmarkBasicSurfaceVolumeSkew(b *testing.B) {
     ms:=db.ModelState{
         Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
@@ -248,3 +317,127 @@ func BenchmarkBasicSurfaceVolumeSkew(b *testing.B) {
         BasicSurfaceCalculation.VolumeSkew(&ms,&tl);
     }
 }
+
+func BenchmarkBasicSurfaceVolumeSkewApprox(b *testing.B) {
+    ms:=db.ModelState{
+        Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+        Eps4: 2, Eps5: 1, Eps6: 0.5,
+    };
+    tl:=db.TrainingLog{
+        Weight: 0, Sets: 0, Reps: 0, Intensity: 0, Effort: 10,
+        InterWorkoutFatigue: 1, InterExerciseFatigue: 1,
+    };
+    for i:=0; i<b.N; i++ {
+        BasicSurfaceCalculation.VolumeSkewApprox(&ms,&tl);
+    }
+}
+
+func TestBasicSurfaceWithRegularization(t *testing.T){
+    _,err:=NewBasicSurfaceWithRegularization(mathUtil.Regularization[float64]{
+        L2: -1,
+    });
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid regularization did not return an error.",t,
+        );
+    }
+    //A single set and rep scheme makes the normal matrix singular
+    update:=func(s Surface){
+        for i:=0; i<10; i++ {
+            s.Update(map[string]float64{
+                "I": 0.8+0.01*float64(i%3), "E": float64(7+i%3), "S": 3, "R": 5,
+                "F_w": float64(i), "F_e": float64(i%2),
+            });
+        }
+    }
+    plain:=NewBasicSurface();
+    update(&plain);
+    _,err=plain.Run();
+    test.BasicTest(true,err!=nil,"A singular window did not return an error.",t);
+    ridge,err:=NewBasicSurfaceWithRegularization(mathUtil.Regularization[float64]{
+        L2: 1e-3,
+    });
+    test.BasicTest(nil,err,"Creating a regularized surface returned an error.",t);
+    update(&ridge);
+    rcond,err:=ridge.Run();
+    test.BasicTest(nil,err,"A regularized singular window returned an error.",t);
+    test.BasicTest(true,rcond>0,"The regularized rcond was not positive.",t);
+}
+
+func TestBasicSurfaceBoundedFit(t *testing.T){
+    s:=NewBasicSurface();
+    vals:=[]mathUtil.Vars[float64]{};
+    for i:=0; i<30; i++ {
+        v:=map[string]float64{
+            "E": float64(6+i%4), "S": float64(1+i%3), "R": float64(1+(i*7)%5),
+            "F_w": float64((i*3)%5), "F_e": float64(i%2),
+        };
+        //Inter workout fatigue increases intensity, which the bounds do not
+        //allow the surface to model
+        v["I"]=0.5+0.05*v["E"]+0.01*v["F_w"]-0.004*v["F_e"]-
+            0.01*(v["S"]-1)*(v["S"]-1)-0.02*(v["R"]-1)*(v["R"]-1)+
+            0.002*stdMath.Sin(float64(i));
+        vals=append(vals,v);
+        s.Update(v);
+    }
+    _,err:=s.Run();
+    test.BasicTest(nil,err,"Running a bounded surface returned an error.",t);
+    for i:=0; i<7; i++ {
+        test.BasicTest(true,s.GetConstant(i)>=0,
+            "A constant was outside of its bounds.",t,
+        );
+    }
+    test.BasicTest(0.0,s.GetConstant(2),
+        "The constant with the wrong sign was not held at its bound.",t,
+    );
+    sse:=0.0;
+    for _,v:=range(vals) {
+        p,_:=s.PredictIntensity(v);
+        sse+=(v["I"]-p)*(v["I"]-p);
+    }
+    test.BasicTest(true,
+        stdMath.Abs(sse/float64(s.Dof())-s.GetResidualVariance())<1e-9,
+        "The residual variance did not match the fitted constants.",t,
+    );
+}
+
+func TestBasicSurfaceWeightedUpdate(t *testing.T){
+    weighted:=NewBasicSurface();
+    repeated:=NewBasicSurface();
+    for i:=0; i<30; i++ {
+        v:=map[string]float64{
+            "E": float64(6+i%4), "S": float64(1+i%3), "R": float64(1+(i*7)%5),
+            "F_w": float64((i*3)%5), "F_e": float64(i%2),
+        };
+        v["I"]=0.5+0.05*v["E"]-0.01*v["F_w"]-0.004*v["F_e"]-
+            0.01*(v["S"]-1)*(v["S"]-1)-0.02*(v["R"]-1)*(v["R"]-1)+
+            0.01*stdMath.Sin(float64(i));
+        repeated.Update(v);
+        if i%3==0 {
+            repeated.Update(v);
+            v[SampleWeightVar]=2;
+        }
+        weighted.Update(v);
+    }
+    //A sample with a weight of zero should be ignored
+    weighted.Update(map[string]float64{
+        "I": 10, "E": 1, "S": 1, "R": 1, "F_w": 0, "F_e": 0, SampleWeightVar: 0,
+    });
+    _,err:=weighted.Run();
+    test.BasicTest(nil,err,"Running a weighted surface returned an error.",t);
+    repeated.Run();
+    for i:=0; i<7; i++ {
+        test.BasicTest(true,
+            stdMath.Abs(weighted.GetConstant(i)-repeated.GetConstant(i))<1e-9,
+            "Weighting a sample did not match repeating it.",t,
+        );
+    }
+    err=weighted.Update(map[string]float64{
+        "I": 1, "E": 1, "S": 1, "R": 1, "F_w": 0, "F_e": 0, SampleWeightVar: -1,
+    });
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A negative sample weight did not return an error.",t,
+        );
+    }
+}
@@ -1,8 +1,6 @@
 package potentialSurface
 
 import (
-	"fmt"
-
 	"github.com/barbell-math/engine/db"
 	mathUtil "github.com/barbell-math/engine/util/math/numeric"
 )
@@ -12,16 +10,16 @@ type PotentialSurfaceId int;
 const (
     BasicSurfaceId PotentialSurfaceId=iota+1
     VolumeBaseSurfaceId
+    BanisterSurfaceId
+    NonlinearVolumeBaseSurfaceId
 );
 
 func CalculationsFromSurfaceId(id PotentialSurfaceId) (Calculations,error) {
-    switch id {
-        case BasicSurfaceId: return BasicSurfaceCalculation,nil;
-        case VolumeBaseSurfaceId: return VolumeBaseSurfacePrediction,nil;
-        default: return nil,InvalidPotentialSurfaceId(
-            fmt.Sprintf("Id: %d",id),
-        );
+    r,err:=Lookup(id);
+    if err!=nil {
+        return nil,err;
     }
+    return r.Calculations,nil;
 }
 
 type Calculations interface {
@@ -36,13 +34,55 @@ type Calculations interface {
     Stability(ms *db.ModelState) int;
 };
 
+//The variable that holds the weight of a sample when it is passed to a
+//surfaces Update method. The weight is optional, samples without it are given
+//a weight of one. A sample with a weight of zero is ignored.
+const SampleWeightVar string="W";
+
+//Returns the weight of the sample, defaulting to one when it is not present.
+func sampleWeight(vals mathUtil.Vars[float64]) float64 {
+    if w,ok:=vals[SampleWeightVar]; ok {
+        return w;
+    }
+    return 1;
+}
+
 type Surface interface {
     Id() PotentialSurfaceId;
     Calculations() Calculations;
     PredictIntensity(vals mathUtil.Vars[float64]) (float64,error);
     Run() (float64,error);
+    //Adds the sample to the regression, weighting it by the optional
+    //SampleWeightVar variable.
     Update(vals mathUtil.Vars[float64]) error;
     GetConstant(idx int) float64;
+    GetResidualVariance() float64;
+    GetSampleCount() int;
+    //The number of constants the surface fits.
+    NumConstants() int;
+    Dof() int;
+    PredictionVariance(vals mathUtil.Vars[float64]) (float64,error);
+    Leverage(a mathUtil.Vars[float64], b mathUtil.Vars[float64]) (float64,error);
     Stability() int;
     ToGenericSurf() Surface;
 };
+
+//A linear surface is a surface whose constants enter the regression linearly.
+//State generators that update the constants one data point at a time need
+//access to the individual terms of the regression, which is what EvalOps
+//provides. Both the basic and volume base surface satisfy this interface
+//through their embedded linear reg.
+type LinearSurface interface {
+    Surface;
+    EvalOps(vals mathUtil.Vars[float64]) ([]float64,float64,error);
+};
+
+//A surface whose samples can be removed after they have been added. State
+//generators use this to leave samples out of a fit without re-adding every
+//other sample. The basic, volume base, and banister surfaces satisfy this
+//interface.
+type RemovableSurface interface {
+    Surface;
+    //Removes a sample that was previously added with Update.
+    Remove(vals mathUtil.Vars[float64]) error;
+};
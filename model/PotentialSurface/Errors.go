@@ -7,3 +7,19 @@ import (
 var InvalidPotentialSurfaceId,IsInvalidPotentialSurfaceId=customerr.ErrorFactory(
     "The supplied potential surface id is not mapped to any surface.",
 );
+
+var InvalidPotentialSurfaceName,IsInvalidPotentialSurfaceName=customerr.ErrorFactory(
+    "The supplied potential surface name is not mapped to any surface.",
+);
+
+var DuplicateSurfaceRegistration,IsDuplicateSurfaceRegistration=customerr.ErrorFactory(
+    "A surface with the same id or name has already been registered.",
+);
+
+var InvalidVariable,IsInvalidVariable=customerr.ErrorFactory(
+    "The supplied variable is not a variable that can be solved for.",
+);
+
+var NoSolution,IsNoSolution=customerr.ErrorFactory(
+    "The surface does not have a solution for the supplied values.",
+);
@@ -0,0 +1,156 @@
+package potentialSurface
+
+import (
+	"fmt"
+	stdMath "math"
+
+	"github.com/barbell-math/engine/db"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The confidence that is used when a prediction interval is not explicitly
+//asked for.
+const DefaultPredictionConfidence float64=0.95;
+
+//Returns the number of constants the surface fits.
+func NumConstants(id PotentialSurfaceId) (int,error) {
+    r,err:=Lookup(id);
+    if err!=nil {
+        return 0,err;
+    }
+    return r.New().NumConstants(),nil;
+}
+
+//Surfaces that are not regressed on intensity directly. The derivative of
+//intensity with respect to the regressions dependent variable is used to move
+//variances from the units of the regression into intensity units.
+type transformedSurface interface {
+    IntensityDerivative(vals mathUtil.Vars[float64]) (float64,error);
+};
+
+//Returns the derivative of intensity with respect to the regressions dependent
+//variable at the given variables. Surfaces that are regressed on intensity
+//directly have a derivative of one.
+func intensityDerivative(s Surface, vals mathUtil.Vars[float64]) (float64,error) {
+    if t,ok:=s.(transformedSurface); ok {
+        return t.IntensityDerivative(vals);
+    }
+    return 1,nil;
+}
+
+//Returns the prediction interval for intensity from a surface that has been
+//run. The interval uses the student t distribution with the degrees of
+//freedom of the regression and includes the uncertainty in the constants. The
+//prediction variance is in the units of the regression, so it is converted to
+//intensity with the delta method:
+//  var_I=(dI/dy)^2*var_y
+//If there were not more samples than constants the interval is unknown and
+//NaN is returned for both bounds.
+func PredictionInterval(
+        s Surface,
+        vals mathUtil.Vars[float64],
+        confidence float64) (float64,float64,error) {
+    if err:=validConfidence(confidence); err!=nil {
+        return stdMath.NaN(),stdMath.NaN(),err;
+    }
+    pred,err:=s.PredictIntensity(vals);
+    if err!=nil {
+        return stdMath.NaN(),stdMath.NaN(),err;
+    }
+    if s.Dof()<=0 {
+        return stdMath.NaN(),stdMath.NaN(),nil;
+    }
+    variance,err:=s.PredictionVariance(vals);
+    if err!=nil {
+        return stdMath.NaN(),stdMath.NaN(),err;
+    }
+    deriv,err:=intensityDerivative(s,vals);
+    if err!=nil {
+        return stdMath.NaN(),stdMath.NaN(),err;
+    }
+    t,err:=mathUtil.StudentTQuantile((1+confidence)/2,float64(s.Dof()));
+    if err!=nil {
+        return stdMath.NaN(),stdMath.NaN(),err;
+    }
+    h:=t*stdMath.Abs(deriv)*stdMath.Sqrt(variance);
+    return pred-h,pred+h,nil;
+}
+
+//Returns the standard deviation of the intensity residuals of a surface that
+//has been run on the given samples:
+//  sigma=sqrt(sum(w_i*(I_i-pred_i)^2)/dof)
+//Unlike the residual variance of the surface, which is in the units of the
+//regression, sigma is always in intensity units. This is the value that is
+//saved in a model states Sigma column. If there were not more samples than
+//constants NaN is returned.
+func IntensitySigma(s Surface, samples []mathUtil.Vars[float64]) (float64,error) {
+    if s.Dof()<=0 {
+        return stdMath.NaN(),nil;
+    }
+    sse:=0.0;
+    for _,v:=range(samples) {
+        intensity,err:=v.Access("I");
+        if err!=nil {
+            return stdMath.NaN(),err;
+        }
+        pred,err:=s.PredictIntensity(v);
+        if err!=nil {
+            return stdMath.NaN(),err;
+        }
+        sse+=sampleWeight(v)*(intensity-pred)*(intensity-pred);
+    }
+    return stdMath.Sqrt(sse/float64(s.Dof())),nil;
+}
+
+//Returns half the width of the intensity prediction interval for predictions
+//made with the model state. This is for model states whose sigma is the
+//standard deviation of the residuals of the fit, in intensity units. Model
+//states do not save the covariance of their constants so the uncertainty in
+//the constants is approximated by the uncertainty of a prediction at the mean
+//of the data:
+//  h=t*sigma*sqrt(1+1/n)
+//If the model state does not have enough samples to estimate sigma the half
+//width is NaN.
+func IntensityHalfWidth(ms *db.ModelState, confidence float64) (float64,error) {
+    t,err:=halfWidthQuantile(ms,confidence);
+    return t*ms.Sigma*stdMath.Sqrt(1+1/float64(ms.SampleCount)),err;
+}
+
+//The same as IntensityHalfWidth except that it is for model states whose sigma
+//is the standard deviation of one step ahead prediction errors. Those errors
+//were made by constants that had not seen the data point yet, so sigma already
+//includes the uncertainty in the constants and is not inflated:
+//  h=t*sigma
+func PredictiveIntensityHalfWidth(
+        ms *db.ModelState,
+        confidence float64) (float64,error) {
+    t,err:=halfWidthQuantile(ms,confidence);
+    return t*ms.Sigma,err;
+}
+
+//Returns the student t quantile for the interval, NaN if the model state does
+//not have more samples than constants.
+func halfWidthQuantile(ms *db.ModelState, confidence float64) (float64,error) {
+    if err:=validConfidence(confidence); err!=nil {
+        return stdMath.NaN(),err;
+    }
+    nConst,err:=NumConstants(PotentialSurfaceId(ms.PotentialSurfaceID));
+    if err!=nil {
+        return stdMath.NaN(),err;
+    }
+    dof:=ms.SampleCount-nConst;
+    if dof<=0 {
+        return stdMath.NaN(),nil;
+    }
+    return mathUtil.StudentTQuantile((1+confidence)/2,float64(dof));
+}
+
+func validConfidence(confidence float64) error {
+    if confidence<=0 || confidence>=1 {
+        return customerr.ValOutsideRange(fmt.Sprintf(
+            "Confidence: %f not in (0,1)",confidence,
+        ));
+    }
+    return nil;
+}
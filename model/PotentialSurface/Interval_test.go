@@ -0,0 +1,180 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestNumConstants(t *testing.T){
+    v:=NewVolumeBaseSurface();
+    b:=NewBanisterSurface();
+    for id,exp:=range(map[PotentialSurfaceId]int{
+        BasicSurfaceId: 7,
+        VolumeBaseSurfaceId: v.NumConstants(),
+        BanisterSurfaceId: b.NumConstants(),
+        NonlinearVolumeBaseSurfaceId: 6,
+    }) {
+        n,err:=NumConstants(id);
+        test.BasicTest(nil,err,"Getting the number of constants returned an error.",t);
+        test.BasicTest(exp,n,"The number of constants was not correct.",t);
+    }
+    if _,err:=NumConstants(-1); !IsInvalidPotentialSurfaceId(err) {
+        test.FormatError(InvalidPotentialSurfaceId(""),err,
+            "An invalid surface did not return an error.",t,
+        );
+    }
+}
+
+func TestPredictionInterval(t *testing.T){
+    b:=NewBasicSurface();
+    s:=b.ToGenericSurf();
+    vals:=map[string]float64{};
+    for i:=0; i<40; i++ {
+        vals["F_w"]=float64(i%4);
+        vals["F_e"]=float64((i/3)%5);
+        vals["E"]=float64(6+i%5);
+        vals["S"]=float64(1+i%3);
+        vals["R"]=float64(1+i%7);
+        vals["I"]=0.5+0.05*vals["E"]-0.01*stdMath.Pow(vals["R"]-1,2)+
+            0.01*float64(i%2*2-1);
+        s.Update(vals);
+    }
+    _,err:=s.Run();
+    test.BasicTest(nil,err,"Running the surface returned an error.",t);
+    pred,_:=s.PredictIntensity(vals);
+    low,high,err:=PredictionInterval(s,vals,0.95);
+    test.BasicTest(nil,err,"Creating a prediction interval returned an error.",t);
+    test.BasicTest(true,low<pred && pred<high,
+        "The prediction was not inside the interval.",t,
+    );
+    low2,high2,_:=PredictionInterval(s,vals,0.5);
+    test.BasicTest(true,high2-low2<high-low,
+        "A lower confidence did not create a narrower interval.",t,
+    );
+    if _,_,err=PredictionInterval(s,vals,1); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid confidence did not return an error.",t,
+        );
+    }
+}
+
+func TestPredictionIntervalNotEnoughData(t *testing.T){
+    b:=NewBasicSurface();
+    s:=b.ToGenericSurf();
+    s.Update(map[string]float64{
+        "I": 0.8, "E": 8, "S": 1, "R": 3, "F_w": 0, "F_e": 0,
+    });
+    s.Run();
+    low,high,err:=PredictionInterval(s,map[string]float64{
+        "E": 8, "S": 1, "R": 3, "F_w": 0, "F_e": 0,
+    },0.95);
+    test.BasicTest(nil,err,"Creating a prediction interval returned an error.",t);
+    test.BasicTest(true,stdMath.IsNaN(low) && stdMath.IsNaN(high),
+        "An interval was created without enough data.",t,
+    );
+}
+
+func TestIntensityHalfWidth(t *testing.T){
+    ms:=db.ModelState{
+        PotentialSurfaceID: int(BasicSurfaceId), SampleCount: 17, Sigma: 0.02,
+    };
+    h,err:=IntensityHalfWidth(&ms,0.95);
+    test.BasicTest(nil,err,"Getting the half width returned an error.",t);
+    //t(0.975,10)=2.228139
+    exp:=2.228139*0.02*stdMath.Sqrt(1+1.0/17);
+    test.BasicTest(true,stdMath.Abs(exp-h)<1e-6,"The half width was not correct.",t);
+    ms.SampleCount=7;
+    h,err=IntensityHalfWidth(&ms,0.95);
+    test.BasicTest(nil,err,"Getting the half width returned an error.",t);
+    test.BasicTest(true,stdMath.IsNaN(h),
+        "A half width was returned without enough samples.",t,
+    );
+    if _,err=IntensityHalfWidth(&ms,0); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid confidence did not return an error.",t,
+        );
+    }
+}
+
+func TestPredictiveIntensityHalfWidth(t *testing.T){
+    ms:=db.ModelState{
+        PotentialSurfaceID: int(BasicSurfaceId), SampleCount: 17, Sigma: 0.02,
+    };
+    h,err:=PredictiveIntensityHalfWidth(&ms,0.95);
+    test.BasicTest(nil,err,"Getting the half width returned an error.",t);
+    //t(0.975,10)=2.228139
+    test.BasicTest(true,stdMath.Abs(2.228139*0.02-h)<1e-6,
+        "The half width was not correct.",t,
+    );
+    ms.SampleCount=7;
+    h,err=PredictiveIntensityHalfWidth(&ms,0.95);
+    test.BasicTest(nil,err,"Getting the half width returned an error.",t);
+    test.BasicTest(true,stdMath.IsNaN(h),
+        "A half width was returned without enough samples.",t,
+    );
+}
+
+func TestVariableInterval(t *testing.T){
+    ms:=db.ModelState{Eps: 0.5, Eps1: 0.05, Eps6: 0.01};
+    tl:=db.TrainingLog{Sets: 1, Reps: 3, Effort: 8, Intensity: 0.86};
+    low,high:=IntensityVar.Interval(BasicSurfaceCalculation,&ms,&tl,0.02);
+    i,_:=IntensityVar.Solve(BasicSurfaceCalculation,&ms,&tl);
+    test.BasicTest(true,stdMath.Abs(low-(i-0.02))<1e-12 &&
+        stdMath.Abs(high-(i+0.02))<1e-12,
+        "The intensity interval was not centered on the prediction.",t,
+    );
+    low,high=EffortVar.Interval(BasicSurfaceCalculation,&ms,&tl,0.02);
+    e,_:=EffortVar.Solve(BasicSurfaceCalculation,&ms,&tl);
+    test.BasicTest(true,low<e && e<high,
+        "The effort was not inside its interval.",t,
+    );
+    low,high=EffortVar.Interval(BasicSurfaceCalculation,&ms,&tl,stdMath.NaN());
+    test.BasicTest(true,stdMath.IsNaN(low) && stdMath.IsNaN(high),
+        "An unknown half width did not create an unknown interval.",t,
+    );
+}
+
+func TestVolumeBasePredictionInterval(t *testing.T){
+    v:=NewVolumeBaseSurface();
+    s:=v.ToGenericSurf();
+    samples:=[]mathUtil.Vars[float64]{};
+    for i:=0; i<40; i++ {
+        vals:=mathUtil.Vars[float64]{
+            "F_w": float64(i%4),
+            "F_e": float64((i/3)%5),
+            "E": float64(6+i%5),
+            "S": float64(1+i%3),
+            "R": float64(1+i%7),
+        };
+        vals["I"]=stdMath.Sqrt(vals["E"]/(8+0.5*vals["F_w"]+0.25*vals["F_e"]+
+            0.1*stdMath.Pow(vals["S"]-1,2)+0.05*stdMath.Pow(vals["R"]-1,2)))+
+            0.01*float64(i%2*2-1);
+        s.Update(vals);
+        samples=append(samples,vals);
+    }
+    _,err:=s.Run();
+    test.BasicTest(nil,err,"Running the surface returned an error.",t);
+    sigma,err:=IntensitySigma(s,samples);
+    test.BasicTest(nil,err,"Getting sigma returned an error.",t);
+    test.BasicTest(true,sigma>0.005 && sigma<0.02,
+        "Sigma was not in intensity units.",t,
+    );
+    vals:=samples[len(samples)-1];
+    pred,_:=s.PredictIntensity(vals);
+    low,high,err:=PredictionInterval(s,vals,0.95);
+    test.BasicTest(nil,err,"Creating a prediction interval returned an error.",t);
+    test.BasicTest(true,low<pred && pred<high,
+        "The prediction was not inside the interval.",t,
+    );
+    //The interval should be close to the interval found from the intensity
+    //residuals, t(0.975,34)=2.032245
+    exp:=2.032245*sigma;
+    test.BasicTest(true,(high-low)/2>0.8*exp && (high-low)/2<1.5*exp,
+        "The interval was not in intensity units.",t,
+    );
+}
@@ -0,0 +1,192 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+
+	"github.com/barbell-math/engine/util/dataStruct"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The values that define a parametric surface.
+//  - Id and Calculations: the same as the values in a surfaces registration,
+//    the calculations must use the constants in the same order as Intensity
+//  - Intensity: the function that predicts intensity from the constants
+//  - Jacobian: the partial derivatives of Intensity, nil uses a numeric jacobian
+//  - NumConstants: the number of constants, at most 8 can be saved to a model
+//    state
+//  - InitialConstants: the initial guess used by the solver
+//  - Initial: if not nil it is called with the samples to create the initial
+//    guess instead of using InitialConstants
+//  - Bounds: the bounds of the constants, nil leaves the constants unbounded
+//  - Solver: the options given to the Levenberg-Marquardt solver
+type ParametricSurfaceOpts struct {
+    Id PotentialSurfaceId;
+    Calculations Calculations;
+    Intensity mathUtil.ParametricFunc[float64];
+    Jacobian mathUtil.ParametricJacobian[float64];
+    NumConstants int;
+    InitialConstants []float64;
+    Initial func(samples []mathUtil.Vars[float64]) []float64;
+    Bounds []dataStruct.Pair[float64,float64];
+    Solver mathUtil.LevenbergMarquardtOpts[float64];
+};
+
+//A surface that fits any parametric intensity function with non-linear least
+//squares. Unlike the linear surfaces the error that is minimized is always the
+//error of the predicted intensity.
+type ParametricSurface struct {
+    mathUtil.NonlinearLeastSquares[float64];
+    mathUtil.NonlinearLeastSquaresResult[float64];
+    id PotentialSurfaceId;
+    calcs Calculations;
+    initialConstants []float64;
+    initial func(samples []mathUtil.Vars[float64]) []float64;
+};
+
+func NewParametricSurface(o ParametricSurfaceOpts) (ParametricSurface,error) {
+    rv:=ParametricSurface{
+        id: o.Id, calcs: o.Calculations, initial: o.Initial,
+        initialConstants: append([]float64{},o.InitialConstants...),
+    };
+    if o.Calculations==nil {
+        return rv,customerr.InvalidValue("the surfaces calculations must not be nil");
+    } else if o.NumConstants>8 {
+        return rv,customerr.ValOutsideRange("a model state can hold at most 8 constants");
+    } else if o.Initial==nil && len(o.InitialConstants)!=o.NumConstants {
+        return rv,customerr.ArrayDimsArgree(
+            o.InitialConstants,make([]float64,o.NumConstants),
+            "Need one initial value per constant.",
+        );
+    }
+    var err error;
+    rv.NonlinearLeastSquares,err=mathUtil.NewNonlinearLeastSquares(
+        o.Intensity,o.Jacobian,mathUtil.LinearSummationOp[float64]("I"),
+        o.NumConstants,
+    );
+    if err!=nil {
+        return rv,err;
+    } else if err=rv.SetBounds(o.Bounds); err!=nil {
+        return rv,err;
+    }
+    return rv,rv.SetOptions(o.Solver);
+}
+
+func (p ParametricSurface)ToGenericSurf() Surface { return &p; }
+
+func (p *ParametricSurface)Id() PotentialSurfaceId { return p.id; }
+func (p *ParametricSurface)Calculations() Calculations { return p.calcs; }
+
+func (p *ParametricSurface)Update(vals mathUtil.Vars[float64]) error {
+    return p.AddSampleWeighted(vals,sampleWeight(vals));
+}
+
+//Fits the surface, returning the rcond of the normal equations of the
+//linearized problem at the solution. The diagnostics of the fit are available
+//through the embedded result.
+func (p *ParametricSurface)Run() (float64,error) {
+    initial:=p.initialConstants;
+    if p.initial!=nil {
+        initial=p.initial(p.Samples());
+    }
+    res,err:=p.NonlinearLeastSquares.Run(initial);
+    p.NonlinearLeastSquaresResult=res;
+    return res.Rcond,err;
+}
+
+func (p *ParametricSurface)PredictIntensity(vals mathUtil.Vars[float64]) (float64,error) {
+    return p.NonlinearLeastSquaresResult.Predict(vals);
+}
+
+func (p *ParametricSurface)Stability() int {
+    rv:=0;
+    for _,v:=range(p.Params) {
+        if v>0 {
+            rv++;
+        }
+    }
+    return rv;
+}
+
+//Returns a surface that fits the volume base surface equation directly:
+//  I=(E/F_tot)^0.5
+//The volume base surface fits 1/I^2 with linear regression, which weights the
+//errors of low intensity samples more than high intensity samples. This
+//surface minimizes the error of the intensity itself, starting from the
+//linear fit. It uses the calculations of the volume base surface because the
+//equation, and therefore the meaning of the constants, is the same. It has its
+//own id so that its model states are kept separate from the linear fits.
+func NewNonlinearVolumeBaseSurface() ParametricSurface {
+    rv,_:=NewParametricSurface(ParametricSurfaceOpts{
+        Id: NonlinearVolumeBaseSurfaceId,
+        Calculations: VolumeBaseSurfacePrediction,
+        Intensity: nonlinearVolumeBaseIntensity,
+        Jacobian: nonlinearVolumeBaseJacobian,
+        NumConstants: 6,
+        Initial: nonlinearVolumeBaseInitial,
+        Bounds: volumeBaseSurfaceBounds(),
+    });
+    return rv;
+}
+
+//Returns the terms that are multiplied by each constant to create F_tot along
+//with the effort.
+func volumeBaseTerms(vals mathUtil.Vars[float64]) ([]float64,float64,error) {
+    rv:=make([]float64,6);
+    v:=map[string]float64{};
+    for _,n:=range([]string{"E","F_w","F_e","S","R"}) {
+        tmp,err:=vals.Access(n);
+        if err!=nil {
+            return rv,0,err;
+        }
+        v[n]=tmp;
+    }
+    s2,r2:=stdMath.Pow(v["S"]-1,2),stdMath.Pow(v["R"]-1,2);
+    rv[0],rv[1],rv[2],rv[3],rv[4],rv[5]=1,v["F_w"],v["F_e"],s2*r2,s2,r2;
+    return rv,v["E"],nil;
+}
+
+func nonlinearVolumeBaseIntensity(
+        params []float64,
+        vals mathUtil.Vars[float64]) (float64,error) {
+    terms,e,err:=volumeBaseTerms(vals);
+    f:=0.0;
+    for i,t:=range(terms) {
+        f+=params[i]*t;
+    }
+    return stdMath.Sqrt(e/f),err;
+}
+
+//  dI/deps_i=-0.5*E^0.5*F_tot^-1.5*t_i
+func nonlinearVolumeBaseJacobian(
+        params []float64,
+        vals mathUtil.Vars[float64]) ([]float64,error) {
+    terms,e,err:=volumeBaseTerms(vals);
+    f:=0.0;
+    for i,t:=range(terms) {
+        f+=params[i]*t;
+    }
+    scale:=-0.5*stdMath.Sqrt(e)*stdMath.Pow(f,-1.5);
+    for i,_:=range(terms) {
+        terms[i]*=scale;
+    }
+    return terms,err;
+}
+
+//Uses the linear volume base surface fit as the initial guess. If the linear
+//fit fails every constant starts at one. Constants the linear fit held at zero
+//are started slightly above zero so that F_tot can not start at zero.
+func nonlinearVolumeBaseInitial(samples []mathUtil.Vars[float64]) []float64 {
+    lin:=NewVolumeBaseSurface();
+    for _,s:=range(samples) {
+        lin.Update(s);
+    }
+    rv:=[]float64{1,1,1,1,1,1};
+    if _,err:=lin.Run(); err!=nil {
+        return rv;
+    }
+    for i,_:=range(rv) {
+        rv[i]=stdMath.Max(lin.GetConstant(i),1e-6);
+    }
+    return rv;
+}
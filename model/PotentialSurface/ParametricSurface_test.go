@@ -0,0 +1,124 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/util/test"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//I=eps*(1-exp(-E/eps_1))
+func parametricTestIntensity(p []float64, vals mathUtil.Vars[float64]) (float64,error) {
+    e,err:=vals.Access("E");
+    return p[0]*(1-stdMath.Exp(-e/p[1])),err;
+}
+
+func TestNewParametricSurfaceInvalid(t *testing.T){
+    _,err:=NewParametricSurface(ParametricSurfaceOpts{
+        Intensity: parametricTestIntensity, NumConstants: 2,
+        InitialConstants: []float64{1,1},
+    });
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Nil calculations did not return an error.",t,
+        );
+    }
+    _,err=NewParametricSurface(ParametricSurfaceOpts{
+        Calculations: BasicSurfaceCalculation, Intensity: parametricTestIntensity,
+        NumConstants: 9, InitialConstants: make([]float64,9),
+    });
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Too many constants did not return an error.",t,
+        );
+    }
+    _,err=NewParametricSurface(ParametricSurfaceOpts{
+        Calculations: BasicSurfaceCalculation, Intensity: parametricTestIntensity,
+        NumConstants: 2, InitialConstants: []float64{1},
+    });
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "A missing initial constant did not return an error.",t,
+        );
+    }
+}
+
+func TestParametricSurfaceFit(t *testing.T){
+    p,err:=NewParametricSurface(ParametricSurfaceOpts{
+        Id: BasicSurfaceId, Calculations: BasicSurfaceCalculation,
+        Intensity: parametricTestIntensity, NumConstants: 2,
+        InitialConstants: []float64{0.5,1},
+    });
+    test.BasicTest(nil,err,"Creating a parametric surface returned an error.",t);
+    var s Surface=p.ToGenericSurf();
+    for i:=0; i<20; i++ {
+        e:=float64(1+i%10);
+        s.Update(map[string]float64{
+            "E": e, "I": 0.95*(1-stdMath.Exp(-e/3)),
+        });
+    }
+    _,err=s.Run();
+    test.BasicTest(nil,err,"Running a parametric surface returned an error.",t);
+    test.BasicTest(true,stdMath.Abs(s.GetConstant(0)-0.95)<1e-6,
+        "The fitted constant was not correct.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(s.GetConstant(1)-3)<1e-6,
+        "The fitted constant was not correct.",t,
+    );
+    test.BasicTest(18,s.Dof(),"The degrees of freedom were not correct.",t);
+    test.BasicTest(2,s.Stability(),"The stability was not correct.",t);
+}
+
+func TestNonlinearVolumeBaseSurface(t *testing.T){
+    lin:=NewVolumeBaseSurface();
+    nonlin:=NewNonlinearVolumeBaseSurface();
+    vals:=[]mathUtil.Vars[float64]{};
+    for i:=0; i<40; i++ {
+        v:=map[string]float64{
+            "E": float64(6+i%4), "S": float64(1+i%3), "R": float64(1+(i*7)%5),
+            "F_w": float64((i*3)%5), "F_e": float64(i%2),
+        };
+        f:=8+0.5*v["F_w"]+0.3*v["F_e"]+0.05*stdMath.Pow(v["S"]-1,2)*
+            stdMath.Pow(v["R"]-1,2)+0.4*stdMath.Pow(v["S"]-1,2)+
+            0.6*stdMath.Pow(v["R"]-1,2);
+        v["I"]=stdMath.Sqrt(v["E"]/f)+0.02*stdMath.Sin(float64(i*3));
+        vals=append(vals,v);
+        lin.Update(v);
+        nonlin.Update(v);
+    }
+    lin.Run();
+    _,err:=nonlin.Run();
+    test.BasicTest(nil,err,"Running the nonlinear surface returned an error.",t);
+    linSse,nonlinSse:=0.0,0.0;
+    for _,v:=range(vals) {
+        lp,_:=lin.PredictIntensity(v);
+        np,_:=nonlin.PredictIntensity(v);
+        linSse+=(v["I"]-lp)*(v["I"]-lp);
+        nonlinSse+=(v["I"]-np)*(v["I"]-np);
+    }
+    test.BasicTest(true,nonlinSse<=linSse,
+        "Fitting intensity directly did not reduce the intensity error.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(nonlin.Sse-nonlinSse)<1e-9,
+        "The reported error did not match the predictions.",t,
+    );
+    for i:=0; i<6; i++ {
+        test.BasicTest(true,nonlin.GetConstant(i)>=0,
+            "A constant was outside of its bounds.",t,
+        );
+    }
+}
+
+func TestNonlinearVolumeBaseSurfaceRegistered(t *testing.T){
+    test.BasicTest(NonlinearVolumeBaseSurfaceId,
+        NewNonlinearVolumeBaseSurface().ToGenericSurf().Id(),
+        "The nonlinear surface did not use its own id.",t,
+    );
+    r,err:=Lookup(NonlinearVolumeBaseSurfaceId);
+    test.BasicTest(nil,err,"The nonlinear surface was not registered.",t);
+    test.BasicTest(NonlinearVolumeBaseSurfaceId,r.New().Id(),
+        "The registration created the wrong surface.",t,
+    );
+}
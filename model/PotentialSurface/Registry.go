@@ -0,0 +1,179 @@
+package potentialSurface
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/barbell-math/engine/db"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//A registration holds everything needed to use a potential surface. The name,
+//description, and history days are the values that are saved to the
+//PotentialSurface table, the id is the id of the row in that table. History
+//days is the number of days of training history before a data point that the
+//surface uses, zero for surfaces that only use the data point itself.
+type Registration struct {
+    Id PotentialSurfaceId;
+    Name string;
+    Description string;
+    HistoryDays int;
+    New func() Surface;
+    Calculations Calculations;
+};
+
+var registry=struct {
+    sync.RWMutex;
+    byId map[PotentialSurfaceId]Registration;
+    byName map[string]PotentialSurfaceId;
+}{
+    byId: map[PotentialSurfaceId]Registration{},
+    byName: map[string]PotentialSurfaceId{},
+};
+
+func init(){
+    if err:=Register(Registration{
+        Id: BasicSurfaceId,
+        Name: "Basic Surface",
+        Description: "Models intensity as a function of effort, fatigue, sets, and reps.",
+        New: func() Surface { return NewBasicSurface().ToGenericSurf(); },
+        Calculations: BasicSurfaceCalculation,
+    }); err!=nil {
+        panic(err);
+    }
+    if err:=Register(Registration{
+        Id: VolumeBaseSurfaceId,
+        Name: "Volume Base Surface",
+        Description: "Models intensity as a function of effort, fatigue, and volume.",
+        New: func() Surface { return NewVolumeBaseSurface().ToGenericSurf(); },
+        Calculations: VolumeBaseSurfacePrediction,
+    }); err!=nil {
+        panic(err);
+    }
+    if err:=Register(Registration{
+        Id: BanisterSurfaceId,
+        Name: "Banister Surface",
+        Description: "Models intensity as a function of effort, fitness and fatigue impulses from past training, sets, and reps.",
+        HistoryDays: BanisterHistoryDays,
+        New: func() Surface { return NewBanisterSurface().ToGenericSurf(); },
+        Calculations: BanisterSurfaceCalculation,
+    }); err!=nil {
+        panic(err);
+    }
+    if err:=Register(Registration{
+        Id: NonlinearVolumeBaseSurfaceId,
+        Name: "Nonlinear Volume Base Surface",
+        Description: "The volume base surface fit to minimize the error of the intensity itself.",
+        New: func() Surface { return NewNonlinearVolumeBaseSurface().ToGenericSurf(); },
+        Calculations: VolumeBaseSurfacePrediction,
+    }); err!=nil {
+        panic(err);
+    }
+}
+
+//Adds a surface to the registry. Surfaces should be registered from an init
+//function so they are available before the PotentialSurface table is synced.
+//Ids and names must be unique across all registered surfaces.
+func Register(r Registration) error {
+    if r.Id<=0 {
+        return customerr.InvalidValue(fmt.Sprintf(
+            "Surface id must be >0. Got: %d",r.Id,
+        ));
+    } else if r.Name=="" {
+        return customerr.InvalidValue("Surface name must not be empty.");
+    } else if r.New==nil || r.Calculations==nil {
+        return customerr.InvalidValue(
+            "Surface constructor and calculations must not be nil.",
+        );
+    }
+    registry.Lock();
+    defer registry.Unlock();
+    if _,ok:=registry.byId[r.Id]; ok {
+        return DuplicateSurfaceRegistration(fmt.Sprintf("Id: %d",r.Id));
+    } else if _,ok:=registry.byName[r.Name]; ok {
+        return DuplicateSurfaceRegistration(fmt.Sprintf("Name: %s",r.Name));
+    }
+    registry.byId[r.Id]=r;
+    registry.byName[r.Name]=r.Id;
+    return nil;
+}
+
+//Returns the registration for the given surface id.
+func Lookup(id PotentialSurfaceId) (Registration,error) {
+    registry.RLock();
+    defer registry.RUnlock();
+    if rv,ok:=registry.byId[id]; ok {
+        return rv,nil;
+    }
+    return Registration{},InvalidPotentialSurfaceId(fmt.Sprintf("Id: %d",id));
+}
+
+//Returns the registration for the given surface name.
+func LookupByName(name string) (Registration,error) {
+    registry.RLock();
+    defer registry.RUnlock();
+    if id,ok:=registry.byName[name]; ok {
+        return registry.byId[id],nil;
+    }
+    return Registration{},InvalidPotentialSurfaceName(
+        fmt.Sprintf("Name: %s",name),
+    );
+}
+
+//Returns all of the registered surfaces ordered by id.
+func All() []Registration {
+    registry.RLock();
+    rv:=make([]Registration,0,len(registry.byId));
+    for _,v:=range(registry.byId) {
+        rv=append(rv,v);
+    }
+    registry.RUnlock();
+    sort.Slice(rv,func(i int, j int) bool { return rv[i].Id<rv[j].Id; });
+    return rv;
+}
+
+//Returns a function that creates a new instance of each of the requested
+//surfaces, in the order they were given. If no ids are given then every
+//registered surface is returned. The returned function is the surface factory
+//that state generators expect.
+func SurfaceFactory(ids ...PotentialSurfaceId) (func() []Surface,error) {
+    regs:=make([]Registration,len(ids));
+    if len(ids)==0 {
+        regs=All();
+    }
+    for i,id:=range(ids) {
+        r,err:=Lookup(id);
+        if err!=nil {
+            return nil,err;
+        }
+        regs[i]=r;
+    }
+    return func() []Surface {
+        rv:=make([]Surface,len(regs));
+        for i,r:=range(regs) {
+            rv[i]=r.New();
+        }
+        return rv;
+    },nil;
+}
+
+//Makes the PotentialSurface table match the registry. Rows for registered
+//surfaces are inserted or updated in place so that existing model states keep
+//referencing the correct surface. Rows that are not in the registry are left
+//untouched.
+func SyncSurfaceTable(d *db.DB) error {
+    for _,r:=range(All()) {
+        if _,err:=db.CustomInsertQuery(d,
+            `INSERT INTO PotentialSurface(Id,T,Description,HistoryDays)
+             VALUES ($1,$2,$3,$4)
+             ON CONFLICT (Id) DO UPDATE
+             SET T=EXCLUDED.T, Description=EXCLUDED.Description,
+                HistoryDays=EXCLUDED.HistoryDays;`,
+            []any{int(r.Id),r.Name,r.Description,r.HistoryDays},
+        ); err!=nil {
+            return err;
+        }
+    }
+    return db.ResetIdSequence[db.PotentialSurface](d);
+}
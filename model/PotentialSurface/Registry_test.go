@@ -0,0 +1,125 @@
+package potentialSurface
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/util/test"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestRegistryLookup(t *testing.T){
+    r,err:=Lookup(BasicSurfaceId);
+    test.BasicTest(nil,err,"Looking up a registered surface returned an error.",t);
+    test.BasicTest(BasicSurfaceId,r.Id,"Lookup returned the wrong surface.",t);
+    test.BasicTest(BasicSurfaceId,r.New().Id(),
+        "The registered constructor created the wrong surface.",t,
+    );
+    _,err=Lookup(PotentialSurfaceId(-1));
+    if !IsInvalidPotentialSurfaceId(err) {
+        test.FormatError(InvalidPotentialSurfaceId(""),err,
+            "Looking up an unregistered surface did not return an error.",t,
+        );
+    }
+}
+
+func TestRegistryLookupByName(t *testing.T){
+    r,err:=LookupByName("Volume Base Surface");
+    test.BasicTest(nil,err,"Looking up a registered surface returned an error.",t);
+    test.BasicTest(VolumeBaseSurfaceId,r.Id,"Lookup returned the wrong surface.",t);
+    _,err=LookupByName("NotASurface");
+    if !IsInvalidPotentialSurfaceName(err) {
+        test.FormatError(InvalidPotentialSurfaceName(""),err,
+            "Looking up an unregistered surface did not return an error.",t,
+        );
+    }
+}
+
+func TestRegisterDuplicate(t *testing.T){
+    err:=Register(Registration{
+        Id: BasicSurfaceId,
+        Name: "Duplicate Id",
+        New: func() Surface { return NewBasicSurface().ToGenericSurf(); },
+        Calculations: BasicSurfaceCalculation,
+    });
+    if !IsDuplicateSurfaceRegistration(err) {
+        test.FormatError(DuplicateSurfaceRegistration(""),err,
+            "Registering a duplicate id did not return an error.",t,
+        );
+    }
+    err=Register(Registration{
+        Id: PotentialSurfaceId(1000),
+        Name: "Basic Surface",
+        New: func() Surface { return NewBasicSurface().ToGenericSurf(); },
+        Calculations: BasicSurfaceCalculation,
+    });
+    if !IsDuplicateSurfaceRegistration(err) {
+        test.FormatError(DuplicateSurfaceRegistration(""),err,
+            "Registering a duplicate name did not return an error.",t,
+        );
+    }
+}
+
+func TestRegisterInvalid(t *testing.T){
+    for _,r:=range([]Registration{
+        Registration{Id: 0, Name: "Invalid"},
+        Registration{Id: 1000, Name: ""},
+        Registration{Id: 1000, Name: "Invalid"},
+    }) {
+        if err:=Register(r); !customerr.IsInvalidValue(err) {
+            test.FormatError(customerr.InvalidValue(""),err,
+                "Registering an invalid surface did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestRegistryAll(t *testing.T){
+    all:=All();
+    if len(all)<2 {
+        test.FormatError(">=2",len(all),"Not all surfaces were registered.",t);
+    }
+    for i:=1; i<len(all); i++ {
+        if all[i-1].Id>=all[i].Id {
+            test.FormatError("ascending ids",all,"Surfaces were not ordered by id.",t);
+        }
+    }
+}
+
+func TestSurfaceFactory(t *testing.T){
+    f,err:=SurfaceFactory(VolumeBaseSurfaceId,BasicSurfaceId);
+    test.BasicTest(nil,err,"Creating a surface factory returned an error.",t);
+    surfs:=f();
+    test.BasicTest(2,len(surfs),"The factory created the wrong number of surfaces.",t);
+    test.BasicTest(VolumeBaseSurfaceId,surfs[0].Id(),
+        "The factory did not preserve the requested order.",t,
+    );
+    test.BasicTest(BasicSurfaceId,surfs[1].Id(),
+        "The factory did not preserve the requested order.",t,
+    );
+    f,err=SurfaceFactory();
+    test.BasicTest(nil,err,"Creating a surface factory returned an error.",t);
+    test.BasicTest(len(All()),len(f()),
+        "The default factory did not create every registered surface.",t,
+    );
+    _,err=SurfaceFactory(PotentialSurfaceId(-1));
+    if !IsInvalidPotentialSurfaceId(err) {
+        test.FormatError(InvalidPotentialSurfaceId(""),err,
+            "Creating a factory with an unregistered surface did not error.",t,
+        );
+    }
+}
+
+func TestRegistryHistoryDays(t *testing.T){
+    for _,id:=range([]PotentialSurfaceId{
+        BasicSurfaceId,VolumeBaseSurfaceId,NonlinearVolumeBaseSurfaceId,
+    }) {
+        r,err:=Lookup(id);
+        test.BasicTest(nil,err,"Looking up a registered surface returned an error.",t);
+        test.BasicTest(0,r.HistoryDays,"The surface had a history.",t);
+    }
+    r,err:=Lookup(BanisterSurfaceId);
+    test.BasicTest(nil,err,"Looking up a registered surface returned an error.",t);
+    test.BasicTest(BanisterHistoryDays,r.HistoryDays,
+        "The banister surface did not have the impulse history.",t,
+    );
+}
@@ -0,0 +1,115 @@
+package potentialSurface
+
+import (
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//Surfaces whose samples can be removed so that they can be re-fit.
+type resettableSurface interface {
+    Surface;
+    Reset();
+};
+
+//Fits a surface with iteratively reweighted least squares so that a single
+//mis-entered training log can not dominate the fit. The samples are kept so the
+//surface can be re-fit as the weights change, every other value is taken from
+//the wrapped surface after the final fit. The robust weights are applied on top
+//of any sample weights given with the SampleWeightVar variable.
+type RobustSurface struct {
+    Surface;
+    base resettableSurface;
+    samples []mathUtil.Vars[float64];
+    opts mathUtil.RobustOpts[float64];
+    irls mathUtil.IRLSResult[float64];
+};
+
+//Wraps the surface, which must be a pointer to a surface that can be reset
+//(ex. NewBasicSurface().ToGenericSurf()).
+func NewRobustSurface(
+        base Surface,
+        opts mathUtil.RobustOpts[float64]) (RobustSurface,error) {
+    rv:=RobustSurface{Surface: base, opts: opts};
+    r,ok:=base.(resettableSurface);
+    if !ok {
+        return rv,customerr.InvalidValue("the surface must be able to be reset");
+    } else if opts.Weight==nil {
+        return rv,customerr.InvalidValue("a robust weight function is needed");
+    }
+    rv.base=r;
+    return rv,nil;
+}
+
+//Returns a robust surface that uses Huber weights with the default tuning
+//constant.
+func NewHuberSurface(base Surface) (RobustSurface,error) {
+    return NewRobustSurface(base,mathUtil.RobustOpts[float64]{
+        Weight: mathUtil.HuberWeights(mathUtil.DefaultHuberK),
+    });
+}
+
+//Returns a robust surface that uses Tukey bisquare weights with the default
+//tuning constant.
+func NewTukeySurface(base Surface) (RobustSurface,error) {
+    return NewRobustSurface(base,mathUtil.RobustOpts[float64]{
+        Weight: mathUtil.TukeyBisquareWeights(mathUtil.DefaultTukeyC),
+    });
+}
+
+func (r RobustSurface)ToGenericSurf() Surface { return &r; }
+
+func (r *RobustSurface)Update(vals mathUtil.Vars[float64]) error {
+    if sampleWeight(vals)<0 {
+        return customerr.ValOutsideRange("weights must be >=0");
+    }
+    r.samples=append(r.samples,vals.Copy());
+    return nil;
+}
+
+//Fits the wrapped surface, returning the rcond of the final fit.
+func (r *RobustSurface)Run() (float64,error) {
+    var rcond float64;
+    base:=make([]float64,len(r.samples));
+    for i,s:=range(r.samples) {
+        base[i]=sampleWeight(s);
+    }
+    var err error;
+    r.irls,err=mathUtil.IRLS(base,func(weights []float64) ([]float64,error) {
+        var err error;
+        r.base.Reset();
+        for i,s:=range(r.samples) {
+            v:=s.Copy();
+            v[SampleWeightVar]=weights[i];
+            if err=r.base.Update(v); err!=nil {
+                return nil,err;
+            }
+        }
+        if rcond,err=r.base.Run(); err!=nil {
+            return nil,err;
+        }
+        rv:=make([]float64,len(r.samples));
+        for i,s:=range(r.samples) {
+            intensity,err:=s.Access("I");
+            if err!=nil {
+                return nil,err;
+            }
+            p,err:=r.base.PredictIntensity(s);
+            if err!=nil {
+                return nil,err;
+            }
+            rv[i]=intensity-p;
+        }
+        return rv,nil;
+    },r.opts);
+    return rcond,err;
+}
+
+//Returns the derivative of the wrapped surface, see PredictionInterval.
+func (r *RobustSurface)IntensityDerivative(
+        vals mathUtil.Vars[float64]) (float64,error) {
+    return intensityDerivative(r.base,vals);
+}
+
+//Returns the final weight of each sample in the order they were added.
+func (r *RobustSurface)Weights() []float64 { return r.irls.Weights; }
+func (r *RobustSurface)IRLSResult() mathUtil.IRLSResult[float64] { return r.irls; }
@@ -0,0 +1,76 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/util/test"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestNewRobustSurfaceInvalid(t *testing.T){
+    _,err:=NewRobustSurface(NewBasicSurface().ToGenericSurf(),
+        mathUtil.RobustOpts[float64]{},
+    );
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A missing weight function did not return an error.",t,
+        );
+    }
+    h,_:=NewHuberSurface(NewBasicSurface().ToGenericSurf());
+    _,err=NewHuberSurface(&h);
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A surface that can not be reset did not return an error.",t,
+        );
+    }
+}
+
+func TestRobustSurfaceOutlier(t *testing.T){
+    plain:=NewBasicSurface();
+    vals:=[]mathUtil.Vars[float64]{};
+    for i:=0; i<40; i++ {
+        v:=map[string]float64{
+            "E": float64(6+i%4), "S": float64(1+i%3), "R": float64(1+(i*7)%5),
+            "F_w": float64((i*3)%5), "F_e": float64(i%2),
+        };
+        v["I"]=0.5+0.05*v["E"]-0.01*v["F_w"]-0.004*v["F_e"]-
+            0.01*(v["S"]-1)*(v["S"]-1)-0.02*(v["R"]-1)*(v["R"]-1)+
+            0.002*stdMath.Sin(float64(i));
+        vals=append(vals,v);
+    }
+    //A mis-entered training log
+    vals[17]["I"]+=0.5;
+    for _,newSurf:=range([]func(s Surface) (RobustSurface,error){
+        NewHuberSurface,NewTukeySurface,
+    }) {
+        plain=NewBasicSurface();
+        robust,err:=newSurf(NewBasicSurface().ToGenericSurf());
+        test.BasicTest(nil,err,"Creating a robust surface returned an error.",t);
+        var s Surface=robust.ToGenericSurf();
+        for _,v:=range(vals) {
+            plain.Update(v);
+            s.Update(v);
+        }
+        plain.Run();
+        _,err=s.Run();
+        test.BasicTest(nil,err,"Running a robust surface returned an error.",t);
+        plainSse,robustSse:=0.0,0.0;
+        for i,v:=range(vals) {
+            if i==17 {
+                continue;
+            }
+            pp,_:=plain.PredictIntensity(v);
+            rp,_:=s.PredictIntensity(v);
+            plainSse+=(v["I"]-pp)*(v["I"]-pp);
+            robustSse+=(v["I"]-rp)*(v["I"]-rp);
+        }
+        test.BasicTest(true,robustSse<plainSse,
+            "The robust surface was not less affected by the outlier.",t,
+        );
+        w:=s.(*RobustSurface).Weights();
+        test.BasicTest(40,len(w),"Not every sample was given a weight.",t);
+        test.BasicTest(true,w[17]<0.1,"The outlier was not down weighted.",t);
+    }
+}
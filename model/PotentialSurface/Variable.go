@@ -0,0 +1,94 @@
+package potentialSurface
+
+import (
+	"fmt"
+	stdMath "math"
+
+	"github.com/barbell-math/engine/db"
+)
+
+//The variables of a training log that a surface can solve for. The values are
+//saved in the PredictedVar column of the Prediction table so they must not be
+//re-ordered.
+type Variable int;
+const (
+    IntensityVar Variable=iota
+    EffortVar
+    SetsVar
+    RepsVar
+    InterWorkoutFatigueVar
+    InterExerciseFatigueVar
+);
+
+func (v Variable)String() string {
+    switch v {
+        case IntensityVar: return "Intensity";
+        case EffortVar: return "Effort";
+        case SetsVar: return "Sets";
+        case RepsVar: return "Reps";
+        case InterWorkoutFatigueVar: return "InterWorkoutFatigue";
+        case InterExerciseFatigueVar: return "InterExerciseFatigue";
+        default: return "unknown";
+    }
+}
+
+//Solves for the variable using the given calculations. All values in the
+//training log besides the one being solved for need to be accurate. Not every
+//combination of values has a solution (ex. asking for the number of reps at an
+//intensity that is not reachable at the given effort), in which case an
+//error is returned.
+func (v Variable)Solve(
+        c Calculations,
+        ms *db.ModelState,
+        tl *db.TrainingLog) (float64,error) {
+    var rv float64;
+    switch v {
+        case IntensityVar: rv=c.Intensity(ms,tl);
+        case EffortVar: rv=c.Effort(ms,tl);
+        case SetsVar: rv=c.Sets(ms,tl);
+        case RepsVar: rv=c.Reps(ms,tl);
+        case InterWorkoutFatigueVar: rv=c.InterWorkoutFatigue(ms,tl);
+        case InterExerciseFatigueVar: rv=c.InterExerciseFatigue(ms,tl);
+        default: return 0,InvalidVariable(fmt.Sprintf("Variable: %d",v));
+    }
+    if stdMath.IsNaN(rv) || stdMath.IsInf(rv,0) {
+        return rv,NoSolution(fmt.Sprintf("Variable: %s",v));
+    }
+    return rv,nil;
+}
+
+//Maps an intensity prediction interval onto the variable. The variable is
+//solved for at the intensities on either end of the interval, which are the
+//training logs intensity +- the half width. When solving for intensity the
+//interval is centered on the solution instead. A bound that has no solution
+//is NaN.
+func (v Variable)Interval(
+        c Calculations,
+        ms *db.ModelState,
+        tl *db.TrainingLog,
+        halfWidth float64) (float64,float64) {
+    if stdMath.IsNaN(halfWidth) {
+        return stdMath.NaN(),stdMath.NaN();
+    }
+    if v==IntensityVar {
+        i,err:=v.Solve(c,ms,tl);
+        if err!=nil {
+            return stdMath.NaN(),stdMath.NaN();
+        }
+        return i-halfWidth,i+halfWidth;
+    }
+    bound:=func(intensity float64) float64 {
+        tmp:=*tl;
+        tmp.Intensity=intensity;
+        rv,err:=v.Solve(c,ms,&tmp);
+        if err!=nil {
+            return stdMath.NaN();
+        }
+        return rv;
+    };
+    low,high:=bound(tl.Intensity-halfWidth),bound(tl.Intensity+halfWidth);
+    if low>high {
+        return high,low;
+    }
+    return low,high;
+}
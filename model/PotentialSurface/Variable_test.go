@@ -0,0 +1,71 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestVariableString(t *testing.T){
+    test.BasicTest("Intensity",IntensityVar.String(),
+        "Variable string was not correct.",t,
+    );
+    test.BasicTest("InterExerciseFatigue",InterExerciseFatigueVar.String(),
+        "Variable string was not correct.",t,
+    );
+    test.BasicTest("unknown",Variable(-1).String(),
+        "Variable string was not correct.",t,
+    );
+}
+
+func TestVariableSolve(t *testing.T){
+    ms:=db.ModelState{
+        Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+        Eps4: 2, Eps5: 1, Eps6: 0.5,
+    };
+    tl:=db.TrainingLog{
+        Sets: 2, Reps: 3, Effort: 10,
+        InterWorkoutFatigue: 1, InterExerciseFatigue: 2,
+    };
+    tl.Intensity,_=IntensityVar.Solve(BasicSurfaceCalculation,&ms,&tl);
+    for v,exp:=range(map[Variable]float64{
+        EffortVar: tl.Effort,
+        SetsVar: tl.Sets,
+        RepsVar: tl.Reps,
+        InterWorkoutFatigueVar: float64(tl.InterWorkoutFatigue),
+        InterExerciseFatigueVar: float64(tl.InterExerciseFatigue),
+    }) {
+        res,err:=v.Solve(BasicSurfaceCalculation,&ms,&tl);
+        test.BasicTest(nil,err,"Solving for a variable returned an error.",t);
+        test.BasicTest(true,stdMath.Abs(exp-res)<1e-6,
+            "Solving for a variable did not invert the intensity calculation.",t,
+        );
+    }
+}
+
+func TestVariableSolveNoSolution(t *testing.T){
+    ms:=db.ModelState{
+        Eps: 5, Eps1: 5, Eps2: 1, Eps3: 1,
+        Eps4: 2, Eps5: 1, Eps6: 0.5,
+    };
+    tl:=db.TrainingLog{Sets: 2, Intensity: 1000, Effort: 1};
+    _,err:=RepsVar.Solve(BasicSurfaceCalculation,&ms,&tl);
+    if !IsNoSolution(err) {
+        test.FormatError(NoSolution(""),err,
+            "Solving for an unreachable value did not return an error.",t,
+        );
+    }
+}
+
+func TestVariableSolveInvalid(t *testing.T){
+    _,err:=Variable(-1).Solve(
+        BasicSurfaceCalculation,&db.ModelState{},&db.TrainingLog{},
+    );
+    if !IsInvalidVariable(err) {
+        test.FormatError(InvalidVariable(""),err,
+            "Solving for an invalid variable did not return an error.",t,
+        );
+    }
+}
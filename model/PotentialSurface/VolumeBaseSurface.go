@@ -95,17 +95,182 @@ func (v volumeBaseSurfacePrediction)Reps(
             ms.Eps5),0.5)+1;
 }
 
+//The volume base surface never reaches an intensity of 0, so the feasible
+//region is defined as all sets and reps that can be performed at an intensity
+//of at least the training logs intensity. Using u=s-1 and v=r-1 that region is:
+//  eps_3*u^2v^2+eps_4*u^2+eps_5*v^2<=E/I^2-(eps+eps_1*F_w+eps_2*F_e)
+//The right hand side is the 'volume budget'. If the training logs intensity is
+//not >0 then the region is unbounded and the volume skew is NaN.
+func (v volumeBaseSurfacePrediction)volumeBudget(
+    ms *db.ModelState,
+    tl *db.TrainingLog) float64 {
+    if !(tl.Intensity>0) {
+        return stdMath.NaN();
+    }
+    return tl.Effort/(tl.Intensity*tl.Intensity)-(
+        ms.Eps+
+        ms.Eps1*float64(tl.InterWorkoutFatigue)+
+        ms.Eps2*float64(tl.InterExerciseFatigue));
+}
+
+//The volume skew is the ratio of the volume weighted intensity on the sets
+//side of the feasible region (s>=r) to the volume weighted intensity on the
+//reps side of the feasible region (r>=s).
 func (v volumeBaseSurfacePrediction)VolumeSkew(
     ms *db.ModelState,
     tl *db.TrainingLog) float64 {
-    return 0;
+    budget:=v.volumeBudget(ms,tl);
+    if !(budget>0) {
+        return stdMath.NaN();
+    }
+    return (v.volumeSkewIntegral1(ms,tl,budget)+
+        v.volumeSkewIntegral2(ms,tl,budget))/(
+        v.volumeSkewIntegral3(ms,tl,budget)+
+        v.volumeSkewIntegral4(ms,tl,budget));
+}
+
+func (v volumeBaseSurfacePrediction)volumeSkewDiagonal(
+    ms *db.ModelState,
+    budget float64) float64 {
+    if ms.Eps3==0 {
+        return stdMath.Pow(stdMath.Max(budget/(ms.Eps4+ms.Eps5),0),0.5)+1;
+    }
+    return stdMath.Pow(stdMath.Max((-ms.Eps4-ms.Eps5+
+        stdMath.Pow(stdMath.Max(stdMath.Pow(ms.Eps4+ms.Eps5,2)+
+            4*ms.Eps3*budget,0),0.5))/(2*ms.Eps3),0),0.5)+1;
+}
+
+func (v volumeBaseSurfacePrediction)setsWhenRepsEquals1(
+    ms *db.ModelState,
+    budget float64) float64 {
+    return stdMath.Pow(stdMath.Max(budget/ms.Eps4,0),0.5)+1;
+}
+
+func (v volumeBaseSurfacePrediction)repsWhenSetsEquals1(
+    ms *db.ModelState,
+    budget float64) float64 {
+    return stdMath.Pow(stdMath.Max(budget/ms.Eps5,0),0.5)+1;
+}
+
+//Returns the function to integrate. The training log is copied so that the
+//callers training log is not modified.
+func (v volumeBaseSurfacePrediction)volumeSkewIntegrand(
+    ms *db.ModelState,
+    tl *db.TrainingLog) func(s float64, r float64) float64 {
+    tmp:=*tl;
+    return func(s float64, r float64) float64 {
+        tmp.Sets=s;
+        tmp.Reps=r;
+        return s*r*v.Intensity(ms,&tmp);
+    }
+}
+
+func (v volumeBaseSurfacePrediction)volumeSkewIntegral1(
+    ms *db.ModelState,
+    tl *db.TrainingLog,
+    budget float64) float64 {
+    f:=v.volumeSkewIntegrand(ms,tl);
+    rv,_:=mathUtil.DoubleIntegral(f)(
+        1,
+        v.volumeSkewDiagonal(ms,budget),
+        mathUtil.ConstIntegralBound[float64](1),
+        func(s float64) float64 { return s; },
+        1201,
+    );
+    return rv;
 }
 
+func (v volumeBaseSurfacePrediction)volumeSkewIntegral2(
+    ms *db.ModelState,
+    tl *db.TrainingLog,
+    budget float64) float64 {
+    f:=v.volumeSkewIntegrand(ms,tl);
+    rv,_:=mathUtil.DoubleIntegral(f)(
+        v.volumeSkewDiagonal(ms,budget),
+        v.setsWhenRepsEquals1(ms,budget),
+        mathUtil.ConstIntegralBound[float64](1),
+        func(s float64) float64 {
+            return stdMath.Pow(stdMath.Max((
+                budget-ms.Eps4*stdMath.Pow(s-1,2))/(
+                ms.Eps3*stdMath.Pow(s-1,2)+
+                ms.Eps5),0),0.5)+1;
+        },
+        1201,
+    );
+    return rv;
+}
+
+//The same as integral 1 with the order of the integration variables switched.
+func (v volumeBaseSurfacePrediction)volumeSkewIntegral3(
+    ms *db.ModelState,
+    tl *db.TrainingLog,
+    budget float64) float64 {
+    tmp:=v.volumeSkewIntegrand(ms,tl);
+    f:=func(r float64, s float64) float64 { return tmp(s,r); }
+    rv,_:=mathUtil.DoubleIntegral(f)(
+        1,
+        v.volumeSkewDiagonal(ms,budget),
+        mathUtil.ConstIntegralBound[float64](1),
+        func(r float64) float64 { return r; },
+        1201,
+    );
+    return rv;
+}
+
+//The same as integral 2 with the order of the integration variables switched
+//and the eps_4 and eps_5 constants swapped.
+func (v volumeBaseSurfacePrediction)volumeSkewIntegral4(
+    ms *db.ModelState,
+    tl *db.TrainingLog,
+    budget float64) float64 {
+    tmp:=v.volumeSkewIntegrand(ms,tl);
+    f:=func(r float64, s float64) float64 { return tmp(s,r); }
+    rv,_:=mathUtil.DoubleIntegral(f)(
+        v.volumeSkewDiagonal(ms,budget),
+        v.repsWhenSetsEquals1(ms,budget),
+        mathUtil.ConstIntegralBound[float64](1),
+        func(r float64) float64 {
+            return stdMath.Pow(stdMath.Max((
+                budget-ms.Eps5*stdMath.Pow(r-1,2))/(
+                ms.Eps3*stdMath.Pow(r-1,2)+
+                ms.Eps4),0),0.5)+1;
+        },
+        1201,
+    );
+    return rv;
+}
+
+//Approximates the volume skew by ignoring the eps_3 term, which turns the
+//feasible region into a quarter ellipse. In elliptical coordinates the
+//intensity is sqrt(E/(F_0+B*p^2)), where F_0=eps+eps_1*F_w+eps_2*F_e and B is
+//the volume budget, so the radial integrals have closed forms. See
+//ellipseVolumeSkewApprox for details. The approximation is exact when eps_3
+//is 0.
 func (v volumeBaseSurfacePrediction)VolumeSkewApprox(
     ms *db.ModelState,
     tl *db.TrainingLog) float64 {
-    // Sets/Reps
-    return 0;
+    budget:=v.volumeBudget(ms,tl);
+    if !(budget>0) {
+        return stdMath.NaN();
+    }
+    c:=(ms.Eps+
+        ms.Eps1*float64(tl.InterWorkoutFatigue)+
+        ms.Eps2*float64(tl.InterExerciseFatigue))/budget;
+    sc,sc1:=stdMath.Sqrt(c),stdMath.Sqrt(c+1);
+    //The constant factor of sqrt(E/B) is left off because it cancels
+    radialInts:=[3]float64{
+        sc1-sc,
+        sc1/2,
+        (stdMath.Pow(c+1,1.5)-stdMath.Pow(c,1.5))/3-c*(sc1-sc),
+    };
+    if c>0 {
+        radialInts[1]-=c/2*stdMath.Log((1+sc1)/sc);
+    }
+    return ellipseVolumeSkewApprox(
+        stdMath.Sqrt(budget/ms.Eps4),
+        stdMath.Sqrt(budget/ms.Eps5),
+        radialInts,
+    );
 }
 
 func (v volumeBaseSurfacePrediction)Stability(ms *db.ModelState) int {
@@ -145,7 +310,7 @@ type VolumeBaseSurface struct {
 //The ordering of the functions makes for this ordering of constants:
 //  Eps8,Eps1,Eps2,Eps3,Eps4,Eps5,Eps6
 func NewVolumeBaseSurface() VolumeBaseSurface {
-    return VolumeBaseSurface{
+    rv:=VolumeBaseSurface{
         LinearReg: mathUtil.NewLinearReg([]mathUtil.SummationOp[float64]{
             func(vals mathUtil.Vars[float64]) (float64, error) {
                 e,err:=vals.Access("E");
@@ -216,6 +381,17 @@ func NewVolumeBaseSurface() VolumeBaseSurface {
             },
         ),
     };
+    rv.SetBounds(volumeBaseSurfaceBounds());
+    return rv;
+}
+
+//The same as NewVolumeBaseSurface except that the regularization is used when running
+//the regression. See mathUtil.Regularization.
+func NewVolumeBaseSurfaceWithRegularization(
+        r mathUtil.Regularization[float64]) (VolumeBaseSurface,error) {
+    rv:=NewVolumeBaseSurface();
+    err:=rv.SetRegularization(r);
+    return rv,err;
 }
 
 func (v VolumeBaseSurface)ToGenericSurf() Surface { return &v; }
@@ -224,18 +400,22 @@ func (v *VolumeBaseSurface)Id() PotentialSurfaceId { return VolumeBaseSurfaceId;
 func (v *VolumeBaseSurface)Calculations() Calculations { return VolumeBaseSurfacePrediction; }
 
 func (v *VolumeBaseSurface)Update(vals mathUtil.Vars[float64]) error {
-    return v.UpdateSummations(vals);
+    return v.UpdateSummationsWeighted(vals,sampleWeight(vals));
+}
+
+func (v *VolumeBaseSurface)Remove(vals mathUtil.Vars[float64]) error {
+    return v.RemoveSummationsWeighted(vals,sampleWeight(vals));
 }
 
 func (v *VolumeBaseSurface)Run() (float64,error) {
     res,rcond,err:=v.LinearReg.Run();
     v.LinRegResult=res;
-    v.imposeConstraints();
     return rcond,err;
 }
 
-func (v *VolumeBaseSurface)imposeConstraints() {
-    var constraints=[...]dataStruct.Pair[float64,float64]{
+//The bounds that the constants are fit under.
+func volumeBaseSurfaceBounds() []dataStruct.Pair[float64,float64] {
+    return []dataStruct.Pair[float64,float64]{
         mathUtil.PositiveConstraint[float64](), //Eps: Div by zero term
         mathUtil.PositiveConstraint[float64](), //Eps1: F_w
         mathUtil.PositiveConstraint[float64](), //Eps2: F_e
@@ -243,9 +423,6 @@ func (v *VolumeBaseSurface)imposeConstraints() {
         mathUtil.PositiveConstraint[float64](), //Eps4: s
         mathUtil.PositiveConstraint[float64](), //Eps5: r
     };
-    for i,iterV:=range(v.LinRegResult.V) {
-        v.LinRegResult.V[i][0]=mathUtil.Constrain(iterV[0],constraints[i]);
-    }
 }
 
 // func (v *VolumeBaseSurface)GetConstant(i int) float64 {
@@ -277,6 +454,18 @@ func (v *VolumeBaseSurface)PredictIntensity(vals mathUtil.Vars[float64]) (float6
     return 0,err;
 }
 
+//The regression is fit to y=1/I^2, so the derivative of intensity is:
+//  dI/dy=-1/2*y^(-3/2)
+//If the prediction is not >0 the derivative is NaN.
+func (v *VolumeBaseSurface)IntensityDerivative(
+        vals mathUtil.Vars[float64]) (float64,error) {
+    y,err:=v.LinRegResult.Predict(vals);
+    if err!=nil || !(y>0) {
+        return stdMath.NaN(),err;
+    }
+    return -0.5*stdMath.Pow(y,-1.5),nil;
+}
+
 func (v *VolumeBaseSurface)Stability() int {
     rv:=0;
     for _,v:=range(v.LinRegResult.V) {
@@ -2,9 +2,12 @@ package potentialSurface
 
 import (
 	"testing"
+    stdMath "math"
 
 	"github.com/barbell-math/engine/db"
 	"github.com/barbell-math/engine/util/test"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	customerr "github.com/barbell-math/engine/util/err"
 )
 
 func TestVolumeBaseSurfaceCreation(t *testing.T){
@@ -110,3 +113,113 @@ func TestVolumeBaseSurfaceRepsPrediction(t *testing.T){
         "Reps prediction produced incorrect value.",t,
     );
 }
+
+func volumeBaseVolumeSkewTestValues() (db.ModelState,db.TrainingLog) {
+    return db.ModelState{
+        Eps: 0.5, Eps1: 0.1, Eps2: 0.1, Eps3: 0.01, Eps4: 0.1, Eps5: 0.1,
+    },db.TrainingLog{
+        Weight: 0, Sets: 0, Reps: 0, Intensity: 0.8, Effort: 8,
+        InterWorkoutFatigue: 1, InterExerciseFatigue: 1,
+    };
+}
+
+func TestVolumeBaseSurfaceVolumeSkewSymmetrical(t *testing.T){
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    test.BasicTest(true,stdMath.Abs(
+        1-VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl))<1e-6,
+        "The volume skew result was not correct.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(
+        1-VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl))<1e-6,
+        "The approximate volume skew result was not correct.",t,
+    );
+}
+
+func TestVolumeBaseSurfaceVolumeSkewSets(t *testing.T){
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    ms.Eps5=0.2;
+    exact:=VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl);
+    approx:=VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl);
+    test.BasicTest(true,exact>1 && approx>1,
+        "Penalizing reps did not skew the volume towards sets.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(exact-approx)/exact<0.2,
+        "The approximate volume skew was not close to the exact value.",t,
+    );
+}
+
+func TestVolumeBaseSurfaceVolumeSkewReps(t *testing.T){
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    ms.Eps4=0.2;
+    exact:=VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl);
+    approx:=VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl);
+    test.BasicTest(true,exact<1 && approx<1,
+        "Penalizing sets did not skew the volume towards reps.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(exact-approx)/exact<0.2,
+        "The approximate volume skew was not close to the exact value.",t,
+    );
+}
+
+func TestVolumeBaseSurfaceVolumeSkewNoCrossTerm(t *testing.T){
+    //Without the cross term the feasible region is an ellipse and the
+    //approximation is exact
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    ms.Eps3=0;
+    ms.Eps5=0.2;
+    test.BasicTest(true,stdMath.Abs(
+        VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl)-
+        VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl))<1e-5,
+        "The approximate volume skew did not match the exact value.",t,
+    );
+}
+
+func TestVolumeBaseSurfaceVolumeSkewInfeasible(t *testing.T){
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    tl.Intensity=0;
+    test.BasicTest(true,stdMath.IsNaN(VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl)),
+        "The volume skew of an unbounded region was not NaN.",t,
+    );
+    test.BasicTest(true,stdMath.IsNaN(VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl)),
+        "The approximate volume skew of an unbounded region was not NaN.",t,
+    );
+    tl.Intensity=100;
+    test.BasicTest(true,stdMath.IsNaN(VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl)),
+        "The volume skew of an empty region was not NaN.",t,
+    );
+    test.BasicTest(true,stdMath.IsNaN(VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl)),
+        "The approximate volume skew of an empty region was not NaN.",t,
+    );
+}
+
+func BenchmarkVolumeBaseSurfaceVolumeSkew(b *testing.B) {
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    for i:=0; i<b.N; i++ {
+        VolumeBaseSurfacePrediction.VolumeSkew(&ms,&tl);
+    }
+}
+
+func BenchmarkVolumeBaseSurfaceVolumeSkewApprox(b *testing.B) {
+    ms,tl:=volumeBaseVolumeSkewTestValues();
+    for i:=0; i<b.N; i++ {
+        VolumeBaseSurfacePrediction.VolumeSkewApprox(&ms,&tl);
+    }
+}
+
+func TestVolumeBaseSurfaceWithRegularization(t *testing.T){
+    _,err:=NewVolumeBaseSurfaceWithRegularization(mathUtil.Regularization[float64]{
+        Penalties: []float64{1},
+    });
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Penalties of the wrong length did not return an error.",t,
+        );
+    }
+    s,err:=NewVolumeBaseSurfaceWithRegularization(mathUtil.Regularization[float64]{
+        L1: 1e-4, L2: 1e-4,
+    });
+    test.BasicTest(nil,err,"Creating a regularized surface returned an error.",t);
+    test.BasicTest(1e-4,s.GetRegularization().L1,
+        "The regularization was not set.",t,
+    );
+}
@@ -0,0 +1,33 @@
+package potentialSurface
+
+import (
+	stdMath "math"
+)
+
+//The volume skew approximations ignore the (s-1)^2(r-1)^2 term of a surface,
+//which makes the feasible region a quarter ellipse in terms of u=s-1 and
+//v=r-1:
+//  u^2/a^2+v^2/b^2<=1
+//Using elliptical coordinates (u=a*p*cos(t), v=b*p*sin(t)) the integral of
+//s*r*I over the region separates into a radial and angular part. The radial
+//parts only depend on the shape of the intensity function and are supplied as
+//the radialInts argument:
+//  radialInts[k]=integral from 0 to 1 of I(p)*p^(k+1) dp, for k=0,1,2
+//(The extra power of p is the jacobian of the coordinate change.) The angular
+//parts all have closed forms, so the resulting approximation is a closed form
+//as well. The diagonal (s=r) is located at t=atan(a/b). The sets side of the
+//region (s>=r) is t in [0,atan(a/b)] and the reps side is t in [atan(a/b),pi/2].
+func ellipseVolumeSkewApprox(a float64, b float64, radialInts [3]float64) float64 {
+    if !(a>0) || !(b>0) {
+        return stdMath.NaN();
+    }
+    diag:=stdMath.Atan(a/b);
+    area:=func(t1 float64, t2 float64) float64 {
+        return (radialInts[0]*(t2-t1)+
+            radialInts[1]*(a*(stdMath.Sin(t2)-stdMath.Sin(t1))+
+                b*(stdMath.Cos(t1)-stdMath.Cos(t2)))+
+            radialInts[2]*a*b*(stdMath.Pow(stdMath.Sin(t2),2)-
+                stdMath.Pow(stdMath.Sin(t1),2))/2);
+    }
+    return area(0,diag)/area(diag,stdMath.Pi/2);
+}
@@ -1,6 +1,7 @@
 package model;
 
 import (
+    stdMath "math"
     "github.com/barbell-math/engine/db"
     potSurf "github.com/barbell-math/engine/model/potentialSurface"
     stateGen "github.com/barbell-math/engine/model/stateGenerator"
@@ -16,7 +17,43 @@ func GeneratePrediction(
         tl *db.TrainingLog,
         sg stateGen.StateGeneratorId,
         surf potSurf.PotentialSurfaceId) (db.Prediction,error) {
-    rv:=db.Prediction{ TrainingLogID: tl.Id };
+    return GenerateVariablePrediction(c,tl,sg,surf,potSurf.IntensityVar);
+}
+
+//The same as GeneratePrediction except that any of the variables the surface
+//can solve for can be predicted. All values in the training log besides the
+//one being predicted need to be accurate. For example, predicting reps with an
+//intensity of 0.85, an effort of 9, and 1 set gives the number of reps that can
+//be done at 85% before reaching RPE 9.
+//The prediction interval is created with the default prediction confidence.
+func GenerateVariablePrediction(
+        c *db.DB,
+        tl *db.TrainingLog,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        v potSurf.Variable) (db.Prediction,error) {
+    return GenerateVariablePredictionWithConfidence(
+        c,tl,sg,surf,v,potSurf.DefaultPredictionConfidence,
+    );
+}
+
+//The same as GenerateVariablePrediction except that the confidence of the
+//prediction interval is given. The lower and upper bounds of the prediction
+//are set to NaN if the model state does not have enough samples to create an
+//interval or if the variable has no solution at one of the bounds.
+func GenerateVariablePredictionWithConfidence(
+        c *db.DB,
+        tl *db.TrainingLog,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        v potSurf.Variable,
+        confidence float64) (db.Prediction,error) {
+    rv:=db.Prediction{
+        TrainingLogID: tl.Id,
+        PredictedVar: int(v),
+        Lower: stdMath.NaN(),
+        Upper: stdMath.NaN(),
+    };
     if ms,err,found:=db.CustomReadQuery[db.ModelState](c,
         nearestModelStateToExerciseQuery(tl),[]any{
             tl.ExerciseID,
@@ -25,15 +62,22 @@ func GeneratePrediction(
             surf,
             tl.ClientID,
     }).Nth(0); err==nil && found {
-        pred,err:=potSurf.CalculationsFromSurfaceId(
+        calc,err:=potSurf.CalculationsFromSurfaceId(
             potSurf.PotentialSurfaceId(ms.PotentialSurfaceID),
         );
         if err==nil {
-            rv.TrainingLogID=tl.Id;
-            rv.IntensityPred=pred.Intensity(ms,tl);
+            calc,err=potSurf.CalculationsWithHistory(c,calc,tl);
+        }
+        if err==nil {
+            rv.Val,err=v.Solve(calc,ms,tl);
             rv.StateGeneratorID=ms.StateGeneratorID;
             rv.PotentialSurfaceID=ms.PotentialSurfaceID;
         }
+        if err==nil {
+            var h float64;
+            h,err=stateGen.IntensityHalfWidth(ms,confidence);
+            rv.Lower,rv.Upper=v.Interval(calc,ms,tl,h);
+        }
         return rv,err;
     } else {
         return rv,err;
@@ -109,3 +109,124 @@ func TestGeneratePredictionNoModelState(t *testing.T){
         "Generate prediction returned incorrect error.",t,
     );
 }
+
+func TestGenerateVariablePrediction(t *testing.T){
+    tl:=db.TrainingLog{
+        ClientID: 1,
+        Weight: 0, Sets: 1, Reps: 0, Intensity: 0.85, Effort: 9,
+        InterWorkoutFatigue: 0, InterExerciseFatigue: 0,
+        ExerciseID: 15, DatePerformed: time.Now(),
+    };
+    sg,_:=db.GetStateGeneratorByName(&testDB,"Sliding Window");
+    for _,v:=range([]potSurf.Variable{
+        potSurf.IntensityVar,potSurf.EffortVar,potSurf.RepsVar,
+    }) {
+        pred,err:=GenerateVariablePrediction(&testDB,&tl,
+            stateGen.StateGeneratorId(sg.Id),potSurf.BasicSurfaceId,v,
+        );
+        if err!=nil && !potSurf.IsNoSolution(err) {
+            test.FormatError(nil,err,
+                "Generate variable prediction returned an error when it was not supposed to.",t,
+            );
+        }
+        test.BasicTest(int(v),pred.PredictedVar,
+            "The prediction did not record the predicted variable.",t,
+        );
+    }
+}
+
+func TestGenerateVariablePredictionInvalidVariable(t *testing.T){
+    tl:=db.TrainingLog{
+        ClientID: 1, ExerciseID: 15, DatePerformed: time.Now(),
+    };
+    sg,_:=db.GetStateGeneratorByName(&testDB,"Sliding Window");
+    _,err:=GenerateVariablePrediction(&testDB,&tl,
+        stateGen.StateGeneratorId(sg.Id),potSurf.BasicSurfaceId,
+        potSurf.Variable(-1),
+    );
+    if err!=sql.ErrNoRows && !potSurf.IsInvalidVariable(err) {
+        test.FormatError(potSurf.InvalidVariable(""),err,
+            "Generate variable prediction did not return the correct error.",t,
+        );
+    }
+}
+
+func TestGeneratePredictionInterval(t *testing.T){
+    tl:=db.TrainingLog{
+        ClientID: 1, Sets: 1, Reps: 3, Effort: 8,
+        ExerciseID: 15, DatePerformed: time.Now(),
+    };
+    sg,_:=db.GetStateGeneratorByName(&testDB,"Sliding Window");
+    narrow,err:=GenerateVariablePredictionWithConfidence(&testDB,&tl,
+        stateGen.StateGeneratorId(sg.Id),potSurf.BasicSurfaceId,
+        potSurf.IntensityVar,0.5,
+    );
+    test.BasicTest(nil,err,"Generating a prediction returned an error.",t);
+    wide,err:=GenerateVariablePredictionWithConfidence(&testDB,&tl,
+        stateGen.StateGeneratorId(sg.Id),potSurf.BasicSurfaceId,
+        potSurf.IntensityVar,0.95,
+    );
+    test.BasicTest(nil,err,"Generating a prediction returned an error.",t);
+    test.BasicTest(true,wide.Lower<=narrow.Lower && narrow.Upper<=wide.Upper,
+        "A higher confidence did not create a wider interval.",t,
+    );
+    test.BasicTest(true,wide.Lower<=wide.Val && wide.Val<=wide.Upper,
+        "The prediction was not inside the interval.",t,
+    );
+}
+
+func TestNearestModelState(t *testing.T){
+    day:=time.Date(2022,time.January,10,0,0,0,0,time.UTC);
+    states:=map[int][]db.ModelState{
+        1: []db.ModelState{
+            db.ModelState{Id: 1, Date: day},
+            db.ModelState{Id: 2, Date: day.AddDate(0,0,2)},
+        },
+    };
+    for _,vals:=range([][3]int{{1,0,0},{1,1,1},{1,2,1},{1,3,2},{2,3,0}}) {
+        ms:=nearestModelState(states,&db.TrainingLog{
+            ExerciseID: vals[0], DatePerformed: day.AddDate(0,0,vals[1]),
+        });
+        if vals[2]==0 {
+            test.BasicTest((*db.ModelState)(nil),ms,
+                "A model state was found when there should not have been one.",t,
+            );
+        } else if ms==nil || ms.Id!=vals[2] {
+            test.FormatError(vals[2],ms,"The wrong model state was found.",t);
+        }
+    }
+}
+
+func TestGeneratePredictionsForClient(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    sg,_:=db.GetStateGeneratorByName(&testDB,"Sliding Window");
+    res,err:=GeneratePredictionsForClient(&testDB,c,
+        stateGen.StateGeneratorId(sg.Id),potSurf.BasicSurfaceId,2,
+    );
+    test.BasicTest(nil,err,"Generating predictions returned an error.",t);
+    cnt,_:=db.CustomReadQuery[db.TrainingLog](&testDB,
+        "SELECT * FROM TrainingLog WHERE ClientID=$1;",[]any{c.Id},
+    ).Count();
+    test.BasicTest(cnt,res.Generated+res.Skipped+res.Failed,
+        "Not every training log was accounted for.",t,
+    );
+    //Running again should replace the existing predictions
+    res2,err:=GeneratePredictionsForClient(&testDB,c,
+        stateGen.StateGeneratorId(sg.Id),potSurf.BasicSurfaceId,2,
+    );
+    test.BasicTest(nil,err,"Generating predictions returned an error.",t);
+    test.BasicTest(res,res2,"Regenerating predictions changed the counts.",t);
+}
+
+func TestGeneratePredictionsForRangeEmpty(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    day:=time.Date(1900,time.January,1,0,0,0,0,time.UTC);
+    res,err:=GeneratePredictionsForRange(&testDB,c,
+        stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,
+        day,day.AddDate(0,0,1),1,
+    );
+    test.BasicTest(nil,err,"Generating predictions returned an error.",t);
+    test.BasicTest(PredictionCounts{},res,
+        "Predictions were generated for an empty range.",t,
+    );
+}
@@ -0,0 +1,117 @@
+package model;
+
+import (
+    "time"
+    "database/sql"
+    "github.com/barbell-math/engine/db"
+    "github.com/barbell-math/engine/util/dataStruct"
+    customerr "github.com/barbell-math/engine/util/err"
+    potSurf "github.com/barbell-math/engine/model/potentialSurface"
+    stateGen "github.com/barbell-math/engine/model/stateGenerator"
+)
+
+//The result of regenerating the stale values of a client.
+//  - ModelStates: the number of model states that were created (A) and that
+//    failed to be created (B)
+//  - Predictions: the counts of the stale predictions that were regenerated
+type RegenerationCounts struct {
+    ModelStates dataStruct.Pair[int,int];
+    Predictions PredictionCounts;
+};
+
+//Note - THE ORDER OF THE STRUCT FIELDS MUST MATCH THE ORDER OF THE VALUES
+//IN THE QUERY.
+type staleDate struct {
+    Date time.Time;
+};
+
+//Model states are marked stale by the database whenever a training log inside
+//the time frame a model state was fit with is created, changed, or deleted. For
+//surfaces that use the training history (see Registration.HistoryDays) the
+//time frame is extended back by that history. Predictions are marked stale
+//when the model state they were made with is marked stale. This recomputes
+//only the stale values of the client for the given state generator:
+//  1. Every stale model state is deleted (along with the model states of the
+//     other surfaces on the same day) and the state generator is run from the
+//     earliest stale date so the deleted states are regenerated.
+//  2. Every stale intensity prediction made with the state generator and one
+//     of the surfaces is regenerated and saved as no longer stale.
+//Any model states that were missing after the earliest stale date will also
+//be generated by the state generator.
+func RegenerateStale(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGenerator,
+        surfaceFactory func() []potSurf.Surface,
+        allotedThreads int) (RegenerationCounts,error) {
+    rv:=RegenerationCounts{};
+    var err error;
+    if rv.ModelStates,err=regenerateStaleModelStates(
+        d,c,sg,surfaceFactory,
+    ); err!=nil {
+        return rv,err;
+    }
+    for _,s:=range(surfaceFactory()) {
+        iterRv,err:=regenerateStalePredictions(d,c,sg.Id(),s.Id(),allotedThreads);
+        rv.Predictions.Generated+=iterRv.Generated;
+        rv.Predictions.Skipped+=iterRv.Skipped;
+        rv.Predictions.Failed+=iterRv.Failed;
+        if err!=nil {
+            return rv,err;
+        }
+    }
+    return rv,nil;
+}
+
+func regenerateStaleModelStates(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGenerator,
+        surfaceFactory func() []potSurf.Surface) (dataStruct.Pair[int,int],error) {
+    rv:=dataStruct.Pair[int,int]{A: 0, B: 0};
+    earliest,err,found:=db.CustomReadQuery[staleDate](d,
+        earliestStaleModelStateQuery(),[]any{c.Id,sg.Id()},
+    ).Nth(0);
+    if err==sql.ErrNoRows || (err==nil && !found) {
+        return rv,nil;
+    } else if err!=nil {
+        return rv,err;
+    }
+    err=customerr.ChainedErrorOps(
+        func(r ...any) (any,error) {
+            return db.CustomDeleteQuery(d,
+                deleteStaleModelStateCovarianceQuery(),[]any{c.Id,sg.Id()},
+            );
+        }, func(r ...any) (any,error) {
+            return db.CustomDeleteQuery(d,
+                deleteStaleModelStatesQuery(),[]any{c.Id,sg.Id()},
+            );
+        }, func(r ...any) (any,error) {
+            var err error;
+            rv,err=sg.GenerateClientModelStates(
+                d,c,earliest.Date.AddDate(0,0,-1),surfaceFactory,
+            );
+            return nil,err;
+        },
+    );
+    return rv,err;
+}
+
+func regenerateStalePredictions(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        allotedThreads int) (PredictionCounts,error) {
+    logs,err:=db.CustomReadQuery[db.TrainingLog](d,
+        staleTrainingLogsQuery(),[]any{c.Id,sg,surf,int(potSurf.IntensityVar)},
+    ).Collect();
+    if err==sql.ErrNoRows || (err==nil && len(logs)==0) {
+        return PredictionCounts{},nil;
+    } else if err!=nil {
+        return PredictionCounts{},err;
+    }
+    return generatePredictions(
+        d,c,sg,surf,logs,time.Date(9999,12,31,0,0,0,0,time.UTC),allotedThreads,
+    );
+}
@@ -0,0 +1,124 @@
+package model;
+
+import (
+    "time"
+    "testing"
+    "github.com/barbell-math/engine/db"
+    "github.com/barbell-math/engine/util/test"
+    "github.com/barbell-math/engine/util/dataStruct"
+    potSurf "github.com/barbell-math/engine/model/potentialSurface"
+    stateGen "github.com/barbell-math/engine/model/stateGenerator"
+)
+
+func staleCounts(c db.Client, sg stateGen.StateGeneratorId) (int,int) {
+    ms,_:=db.CustomReadQuery[db.ModelState](&testDB,
+        `SELECT * FROM ModelState
+        WHERE ClientID=$1 AND StateGeneratorID=$2 AND Stale;`,
+        []any{c.Id,sg},
+    ).Count();
+    p,_:=db.CustomReadQuery[db.Prediction](&testDB,
+        `SELECT Prediction.* FROM Prediction
+        JOIN TrainingLog ON Prediction.TrainingLogID=TrainingLog.Id
+        WHERE TrainingLog.ClientID=$1
+            AND Prediction.StateGeneratorID=$2
+            AND Prediction.Stale;`,
+        []any{c.Id,sg},
+    ).Count();
+    return ms,p;
+}
+
+func TestRegenerateStale(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    start:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    sw.GenerateClientModelStates(&testDB,c,start,surfs);
+    GeneratePredictionsForClient(&testDB,c,sw.Id(),potSurf.BasicSurfaceId,2);
+    tl,err,found:=db.CustomReadQuery[db.TrainingLog](&testDB,
+        `SELECT * FROM TrainingLog
+        WHERE ClientID=$1 AND ExerciseID=15 AND DatePerformed>$2
+        ORDER BY DatePerformed ASC
+        LIMIT 1;`,[]any{c.Id,start},
+    ).Nth(0);
+    if err!=nil || !found {
+        test.FormatError(nil,err,"No training log was found to change.",t);
+        return;
+    }
+    tl.Weight+=5;
+    _,err=db.Update(&testDB,db.TrainingLog{Id: tl.Id},db.OnlyIDFilter,
+        *tl,db.AllButIDFilter,
+    );
+    test.BasicTest(nil,err,"Updating the training log returned an error.",t);
+    ms,p:=staleCounts(c,sw.Id());
+    test.BasicTest(true,ms>0,"Changing a training log did not mark model states stale.",t);
+    test.BasicTest(true,p>0,"Changing a training log did not mark predictions stale.",t);
+    res,err:=RegenerateStale(&testDB,c,sw,surfs,2);
+    test.BasicTest(nil,err,"Regenerating stale values returned an error.",t);
+    test.BasicTest(true,res.ModelStates.A>=ms,
+        "Not every stale model state was regenerated.",t,
+    );
+    ms,p=staleCounts(c,sw.Id());
+    test.BasicTest(0,ms,"Stale model states remained after regenerating.",t);
+    test.BasicTest(0,p,"Stale predictions remained after regenerating.",t);
+}
+
+func TestRegenerateStaleNothingStale(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    RegenerateStale(&testDB,c,sw,surfs,1);
+    res,err:=RegenerateStale(&testDB,c,sw,surfs,1);
+    test.BasicTest(nil,err,"Regenerating stale values returned an error.",t);
+    test.BasicTest(RegenerationCounts{},res,
+        "Values were regenerated when nothing was stale.",t,
+    );
+}
+
+func TestStaleModelStatesNotUsed(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    start:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    sw.GenerateClientModelStates(&testDB,c,start,surfs);
+    _,err:=db.Update(&testDB,
+        db.ModelState{ClientID: c.Id, StateGeneratorID: int(sw.Id())},
+        func(col string) bool {
+            return col=="ClientID" || col=="StateGeneratorID";
+        },db.ModelState{Stale: true},
+        func(col string) bool { return col=="Stale"; },
+    );
+    test.BasicTest(nil,err,"Marking the model states stale returned an error.",t);
+    tl,err,found:=db.CustomReadQuery[db.TrainingLog](&testDB,
+        `SELECT * FROM TrainingLog
+        WHERE ClientID=$1 AND ExerciseID=15 AND DatePerformed>$2
+        ORDER BY DatePerformed DESC
+        LIMIT 1;`,[]any{c.Id,start},
+    ).Nth(0);
+    if err!=nil || !found {
+        test.FormatError(nil,err,"No training log was found.",t);
+        return;
+    }
+    _,err,found=db.CustomReadQuery[db.ModelState](&testDB,
+        nearestModelStateToExerciseQuery(tl),[]any{
+            tl.ExerciseID,tl.DatePerformed,sw.Id(),potSurf.BasicSurfaceId,c.Id,
+    }).Nth(0);
+    test.BasicTest(nil,err,"Reading the nearest model state returned an error.",t);
+    test.BasicTest(false,found,"A stale model state was used.",t);
+    n,err:=db.CustomReadQuery[db.ModelState](&testDB,
+        modelStatesBeforeDateQuery(),
+        []any{c.Id,tl.DatePerformed,sw.Id(),potSurf.BasicSurfaceId},
+    ).Count();
+    test.BasicTest(nil,err,"Reading the model states returned an error.",t);
+    test.BasicTest(0,n,"A stale model state was used.",t);
+    _,err=RegenerateStale(&testDB,c,sw,surfs,1);
+    test.BasicTest(nil,err,"Regenerating stale values returned an error.",t);
+}
@@ -4,6 +4,7 @@ import (
     "time"
     "github.com/barbell-math/engine/db"
 	"github.com/barbell-math/engine/util/dataStruct"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
     logUtil "github.com/barbell-math/engine/util/io/log"
 	potSurf "github.com/barbell-math/engine/model/potentialSurface"
 )
@@ -12,6 +13,7 @@ type StateGeneratorId int;
 // SQL serial values default to starting at 1
 const (
     SlidingWindowStateGenId StateGeneratorId=iota+1
+    KalmanFilterStateGenId
 );
 
 type StateGenerator interface {
@@ -27,6 +29,18 @@ type StateGenerator interface {
     ) ([]db.ModelState,error);
 };
 
+//Returns half the width of the intensity prediction interval for predictions
+//made with the model state. Kalman filter states save the standard deviation
+//of their one step ahead prediction errors as sigma, every other state
+//generator saves the standard deviation of the residuals of the fit, so the
+//interval that matches the state generator of the model state is used.
+func IntensityHalfWidth(ms *db.ModelState, confidence float64) (float64,error) {
+    if ms.StateGeneratorID==int(KalmanFilterStateGenId) {
+        return potSurf.PredictiveIntensityHalfWidth(ms,confidence);
+    }
+    return potSurf.IntensityHalfWidth(ms,confidence);
+}
+
 //The struct that holds values when linear regression is performed.
 //Note - THE ORDER OF THE STRUCT FIELDS MUST MATCH THE ORDER OF THE VALUES
 //IN THE QUERY. Otherwise the values returned will be all jumbled up.
@@ -38,8 +52,18 @@ type dataPoint struct {
     Intensity float64;
     InterExerciseFatigue float64;
     InterWorkoutFatigue float64;
+    Fitness float64;
+    Fatigue float64;
 };
 
+func (d *dataPoint)vars() mathUtil.Vars[float64] {
+    return map[string]float64{
+        "I": d.Intensity, "R": d.Reps, "E": d.Effort, "S": d.Sets,
+        "F_w": d.InterWorkoutFatigue, "F_e": d.InterExerciseFatigue,
+        "F_fit": d.Fitness, "F_fat": d.Fatigue,
+    };
+}
+
 //The struct that holds values when searching for missing model states.
 //Note - THE ORDER OF THE STRUCT FIELDS MUST MATCH THE ORDER OF THE VALUES
 //IN THE QUERY. Otherwise the values returned will be all jumbled up.
@@ -52,3 +76,5 @@ type missingModelStateData struct {
 var SLIDING_WINDOW_DP_DEBUG=logUtil.NewBlankLog[*dataPoint]();
 var SLIDING_WINDOW_MS_DEBUG=logUtil.NewBlankLog[db.ModelState]();
 var SLIDING_WINDOW_MS_PARALLEL_RESULT_DEBUG=logUtil.NewBlankLog[db.ModelState]();
+var KALMAN_FILTER_DP_DEBUG=logUtil.NewBlankLog[*dataPoint]();
+var KALMAN_FILTER_MS_DEBUG=logUtil.NewBlankLog[db.ModelState]();
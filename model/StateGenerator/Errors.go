@@ -23,3 +23,11 @@ var NotEnoughData,IsNotEnoughData=customerr.ErrorFactory(
 var InvalidStateGeneratorId,IsInvalidStateGeneratorId=customerr.ErrorFactory(
     "The supplied state generator id is not mapped to any surface.",
 );
+
+var SurfaceNotLinear,IsSurfaceNotLinear=customerr.ErrorFactory(
+    "The supplied surface does not expose its regression terms.",
+);
+
+var SurfaceNotRemovable,IsSurfaceNotRemovable=customerr.ErrorFactory(
+    "The supplied surface does not allow samples to be removed.",
+);
@@ -0,0 +1,408 @@
+package stateGenerator
+
+import (
+	"database/sql"
+	"fmt"
+	stdMath "math"
+	"sort"
+	stdTime "time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/util/algo/iter"
+	"github.com/barbell-math/engine/util/dataStruct"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	timeUtil "github.com/barbell-math/engine/util/time"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The kalman filter state generator treats the constants of each surface as a
+//hidden state that drifts over time. Every training log is a noisy measurement
+//of that state, so instead of refitting the whole time frame for every date the
+//constants are updated one training log at a time. The previous model state
+//(and its covariance if it was persisted) is used as the starting point, so
+//adding a single session only costs a single update.
+//Process noise is added once for every day that passes between training logs.
+//The measurement noise is in the units of the surfaces dependent variable (ex.
+//I for the basic surface, 1/I^2 for the volume base surface).
+type KalmanFilterStateGen struct {
+    allotedThreads int;
+    processNoise float64;
+    measurementNoise float64;
+    initialCovariance float64;
+    persistCovariance bool;
+};
+
+//The state of a single surfaces filter as it moves forward in time.
+type kalmanFilterSurface struct {
+    surface potSurf.LinearSurface;
+    filter mathUtil.KalmanFilter[float64];
+    startDate stdTime.Time;
+    lastDate stdTime.Time;
+    boundary stdTime.Time;
+    numPoints int;
+    cumulativeSe float64;
+};
+
+//The model states (one per surface) generated for a single missing date.
+type kalmanFilterResult struct {
+    ms []db.ModelState;
+    cov []mathUtil.Matrix[float64];
+    err error;
+};
+
+func NewKalmanFilterStateGen(
+        processNoise float64,
+        measurementNoise float64,
+        initialCovariance float64,
+        persistCovariance bool,
+        allotedThreads int) (KalmanFilterStateGen,error) {
+    rv:=KalmanFilterStateGen{
+        allotedThreads: mathUtil.Constrain(allotedThreads,dataStruct.Pair[int,int]{
+            A: 1, B: stdMath.MaxInt,
+        }),
+        processNoise: processNoise,
+        measurementNoise: measurementNoise,
+        initialCovariance: initialCovariance,
+        persistCovariance: persistCovariance,
+    };
+    if processNoise<0 {
+        return rv,customerr.InvalidValue("process noise < 0, should be >=0");
+    } else if measurementNoise<=0 {
+        return rv,customerr.InvalidValue("measurement noise <= 0, should be >0");
+    } else if initialCovariance<=0 {
+        return rv,customerr.InvalidValue("initial covariance <= 0, should be >0");
+    }
+    return rv,nil;
+}
+
+func (k KalmanFilterStateGen)Id() StateGeneratorId {
+    return KalmanFilterStateGenId;
+}
+
+//The method receiver is not a pointer so that the object will be copied. It is
+//meant to be called in parallel (i.e. multiple clients) so the copy is necessary.
+//Exercises are processed in parallel, but the dates within an exercise are
+//processed in order because each model state depends on the previous one.
+func (k KalmanFilterStateGen)GenerateClientModelStates(
+        d *db.DB,
+        c db.Client,
+        minTime stdTime.Time,
+        surfaceFactory func() []potSurf.Surface) (dataStruct.Pair[int,int],error) {
+    rv:=dataStruct.Pair[int,int]{A: 0, B: 0};
+    missing,err:=db.CustomReadQuery[missingModelStateData](d,
+        missingModelStatesForGivenStateGenQuery(),[]any{
+            c.Id,k.Id(),minTime,
+    }).Collect();
+    if err==sql.ErrNoRows {
+        return rv,nil;
+    } else if err!=nil {
+        return rv,err;
+    }
+    covCreator,err:=db.NewBufferedCreate[db.ModelStateCovariance](100);
+    if err!=nil {
+        return rv,err;
+    }
+    err=iter.Parallel[[]*missingModelStateData,[]kalmanFilterResult](
+        iter.SliceElems(groupMissingDataByExercise(missing)),
+        func(val []*missingModelStateData) ([]kalmanFilterResult,error) {
+            return k.generateExerciseModelStates(d,surfaceFactory(),val),nil;
+        }, func(val []*missingModelStateData, res []kalmanFilterResult, err error) {
+            for _,r:=range(res) {
+                if r.err!=nil {
+                    rv.B++;
+                    continue;
+                }
+                for i,_:=range(r.ms) {
+                    if k.saveModelState(d,&covCreator,&r.ms[i],&r.cov[i])==nil {
+                        rv.A++;
+                    } else {
+                        rv.B++;
+                    }
+                }
+            }
+        },k.allotedThreads,
+    );
+    covCreator.Flush(d);
+    rv.B+=covCreator.Failed();
+    return rv,err;
+}
+
+//The method receiver is not a pointer so that the object will be copied. It is
+//meant to be called in parallel (i.e. multiple dates/exercises) so the copy
+//is necessary.
+func (k KalmanFilterStateGen)GenerateModelState(
+        d *db.DB,
+        surface []potSurf.Surface,
+        missingData *missingModelStateData) ([]db.ModelState,error) {
+    state,err:=k.initFilters(d,surface,missingData);
+    if err!=nil {
+        return []db.ModelState{},err;
+    }
+    res:=k.advance(d,state,missingData);
+    return res.ms,res.err;
+}
+
+func groupMissingDataByExercise(
+        missing []*missingModelStateData) [][]*missingModelStateData {
+    exercises:=map[int][]*missingModelStateData{};
+    for _,m:=range(missing) {
+        exercises[m.ExerciseID]=append(exercises[m.ExerciseID],m);
+    }
+    rv:=make([][]*missingModelStateData,0,len(exercises));
+    for _,v:=range(exercises) {
+        sort.Slice(v,func(i int, j int) bool {
+            return v[i].Date.Before(v[j].Date);
+        });
+        rv=append(rv,v);
+    }
+    sort.Slice(rv,func(i int, j int) bool {
+        return rv[i][0].ExerciseID<rv[j][0].ExerciseID;
+    });
+    return rv;
+}
+
+//Missing data must be sorted by date and all be for the same exercise.
+func (k *KalmanFilterStateGen)generateExerciseModelStates(
+        d *db.DB,
+        surfaces []potSurf.Surface,
+        missing []*missingModelStateData) []kalmanFilterResult {
+    rv:=make([]kalmanFilterResult,len(missing));
+    state,err:=k.initFilters(d,surfaces,missing[0]);
+    for i,m:=range(missing) {
+        if err!=nil {
+            rv[i].err=err;
+        } else {
+            rv[i]=k.advance(d,state,m);
+        }
+    }
+    return rv;
+}
+
+//Each filter is initialized from the closest previous model state that was
+//generated by this state generator. If none exists then the filter starts with
+//all constants set to zero and the initial covariance along the diagonal.
+func (k *KalmanFilterStateGen)initFilters(
+        d *db.DB,
+        surfaces []potSurf.Surface,
+        missingData *missingModelStateData) ([]kalmanFilterSurface,error) {
+    rv:=make([]kalmanFilterSurface,len(surfaces));
+    for i,s:=range(surfaces) {
+        ls,ok:=s.(potSurf.LinearSurface);
+        if !ok {
+            return rv,SurfaceNotLinear(fmt.Sprintf("Surface Id: %d",s.Id()));
+        }
+        rv[i].surface=ls;
+        prevMs,err,found:=db.CustomReadQuery[db.ModelState](d,
+            previousModelStateQuery(),[]any{
+                missingData.ClientID,
+                missingData.ExerciseID,
+                k.Id(),
+                s.Id(),
+                missingData.Date,
+        }).Nth(0);
+        if err==nil && found {
+            if rv[i].filter,err=k.filterFromModelState(
+                d,prevMs,ls.NumConstants(),
+            ); err!=nil {
+                return rv,err;
+            }
+            rv[i].lastDate=prevMs.Date;
+            rv[i].startDate=prevMs.Date.AddDate(0, 0, -prevMs.TimeFrame);
+            //The running error continues from the previous model state.
+            rv[i].numPoints=prevMs.SampleCount;
+            rv[i].cumulativeSe=prevMs.Mse*float64(prevMs.SampleCount);
+        } else if err==nil || err==sql.ErrNoRows {
+            rv[i].filter=mathUtil.NewKalmanFilter(
+                make([]float64,ls.NumConstants()),k.initialCovariance,
+            );
+        } else {
+            return rv,err;
+        }
+    }
+    return rv,nil;
+}
+
+func (k *KalmanFilterStateGen)filterFromModelState(
+        d *db.DB,
+        ms *db.ModelState,
+        numConstants int) (mathUtil.KalmanFilter[float64],error) {
+    x:=modelStateConstants(ms)[:numConstants];
+    p:=mathUtil.NewMatrix(numConstants,numConstants,
+        func(r int, c int) float64 {
+            if r==c {
+                return k.initialCovariance;
+            }
+            return 0;
+        },
+    );
+    err:=db.Read(d,db.ModelStateCovariance{ModelStateID: ms.Id},
+        algo.GenFilter(false,"ModelStateID"),
+    ).ForEach(func(index int, val *db.ModelStateCovariance) (iter.IteratorFeedback, error) {
+        if val.RowIdx<numConstants && val.ColIdx<numConstants {
+            p.V[val.RowIdx][val.ColIdx]=val.Val;
+        }
+        return iter.Continue,nil;
+    });
+    if err!=nil && err!=sql.ErrNoRows {
+        return mathUtil.KalmanFilter[float64]{},err;
+    }
+    return mathUtil.NewKalmanFilterFromState(x,p);
+}
+
+//Moves all filters forward in time up to and including the missing date. The
+//mse that is saved in the model state is calculated from the predictions made
+//for every training log the filter has used, before each log was used to
+//update the filter, making it an out of sample error.
+func (k *KalmanFilterStateGen)advance(
+        d *db.DB,
+        state []kalmanFilterSurface,
+        missingData *missingModelStateData) kalmanFilterResult {
+    rv:=kalmanFilterResult{
+        ms: make([]db.ModelState,len(state)),
+        cov: make([]mathUtil.Matrix[float64],len(state)),
+    };
+    var minDate stdTime.Time;
+    surfs:=make([]potSurf.Surface,len(state));
+    for i,_:=range(state) {
+        surfs[i]=state[i].surface;
+        if i==0 || state[i].lastDate.Before(minDate) {
+            minDate=state[i].lastDate;
+        }
+        state[i].boundary=state[i].lastDate;
+    }
+    rv.err=db.CustomReadQuery[dataPoint](d,
+        dataBetweenDatesQuery(usesImpulses(surfs)),[]any{
+        minDate,
+        missingData.Date,
+        missingData.ExerciseID,
+        missingData.ClientID,
+    }).ForEach(func(index int, val *dataPoint) (iter.IteratorFeedback, error) {
+        for i,_:=range(state) {
+            if err:=k.updateFilter(&state[i],val); err!=nil {
+                return iter.Break,err;
+            }
+        }
+        KALMAN_FILTER_DP_DEBUG.Log("DataPoint",val);
+        return iter.Continue,nil;
+    });
+    if rv.err==sql.ErrNoRows {
+        rv.err=NoDataInSelectedTimeFrame(fmt.Sprintf(
+            "Date: %s Exercise: %d Client: %d",
+            missingData.Date,missingData.ExerciseID,missingData.ClientID,
+        ));
+    }
+    if rv.err!=nil {
+        return rv;
+    }
+    for i,_:=range(state) {
+        rv.ms[i],rv.cov[i]=k.toModelState(&state[i],missingData);
+        KALMAN_FILTER_MS_DEBUG.Log("ModelState",rv.ms[i]);
+    }
+    return rv;
+}
+
+//Data points that cannot be evaluated by the surface are skipped, the same as
+//they are when updating the sliding window generators linear regression. Every
+//data point that is used is first predicted from the filter as it was before
+//the update, and the error of that prediction is added to the running one step
+//ahead error.
+func (k *KalmanFilterStateGen)updateFilter(
+        s *kalmanFilterSurface,
+        d *dataPoint) error {
+    if !d.DatePerformed.After(s.boundary) {
+        return nil;
+    }
+    if s.startDate.IsZero() {
+        s.startDate=d.DatePerformed;
+    }
+    if !s.lastDate.IsZero() && d.DatePerformed.After(s.lastDate) {
+        s.filter.Predict(k.processNoise*float64(
+            timeUtil.DaysBetween(d.DatePerformed,s.lastDate),
+        ));
+    }
+    s.lastDate=d.DatePerformed;
+    h,z,err:=s.surface.EvalOps(d.vars());
+    if err!=nil {
+        return nil;
+    }
+    var ms db.ModelState;
+    setModelStateConstants(&ms,s.filter.State());
+    tl:=db.TrainingLog{
+        Sets: d.Sets,
+        Reps: d.Reps,
+        Effort: d.Effort,
+        Intensity: d.Intensity,
+        InterWorkoutFatigue: int(d.InterWorkoutFatigue),
+        InterExerciseFatigue: int(d.InterExerciseFatigue),
+    };
+    calc:=s.surface.Calculations();
+    if ic,ok:=calc.(potSurf.ImpulseCalculations); ok {
+        calc=ic.WithImpulses(d.Fitness,d.Fatigue);
+    }
+    if pred:=calc.Intensity(&ms,&tl); !stdMath.IsNaN(pred) {
+        s.cumulativeSe+=(pred-d.Intensity)*(pred-d.Intensity);
+        s.numPoints++;
+    }
+    _,err=s.filter.Update(h,z,k.measurementNoise);
+    return err;
+}
+
+func (k *KalmanFilterStateGen)toModelState(
+        s *kalmanFilterSurface,
+        missingData *missingModelStateData) (db.ModelState,mathUtil.Matrix[float64]) {
+    rv:=db.ModelState{
+        Date: missingData.Date,
+        ClientID: missingData.ClientID,
+        ExerciseID: missingData.ExerciseID,
+        StateGeneratorID: int(KalmanFilterStateGenId),
+        PotentialSurfaceID: int(s.surface.Id()),
+        TimeFrame: timeUtil.DaysBetween(missingData.Date,s.startDate),
+        Win: 0,
+    };
+    setModelStateConstants(&rv,s.filter.State());
+    if s.numPoints>0 {
+        rv.Mse=s.cumulativeSe/float64(s.numPoints);
+    }
+    //The errors are one step ahead prediction errors so the mse is already the
+    //variance of a new prediction.
+    rv.SampleCount=s.numPoints;
+    rv.Sigma=stdMath.Sqrt(rv.Mse);
+    //The rcond of a matrix and its inverse are the same, the inverse is only
+    //used here to get the rcond value
+    tmp:=s.filter.P.Copy();
+    rv.Rcond,_=tmp.Inverse();
+    return rv,s.filter.P.Copy();
+}
+
+func (k *KalmanFilterStateGen)saveModelState(
+        d *db.DB,
+        covCreator *db.BufferedCreate[db.ModelStateCovariance],
+        ms *db.ModelState,
+        cov *mathUtil.Matrix[float64]) error {
+    id,err:=db.Create(d,*ms);
+    if err!=nil || !k.persistCovariance {
+        return err;
+    }
+    cov.Iter(func(r int, c int, v float64){
+        covCreator.Write(d,db.ModelStateCovariance{
+            ModelStateID: id[0], RowIdx: r, ColIdx: c, Val: v,
+        });
+    });
+    return nil;
+}
+
+func modelStateConstants(ms *db.ModelState) []float64 {
+    return []float64{
+        ms.Eps,ms.Eps1,ms.Eps2,ms.Eps3,
+        ms.Eps4,ms.Eps5,ms.Eps6,ms.Eps7,
+    };
+}
+
+func setModelStateConstants(ms *db.ModelState, vals []float64){
+    c:=make([]float64,8);
+    copy(c,vals);
+    ms.Eps,ms.Eps1,ms.Eps2,ms.Eps3=c[0],c[1],c[2],c[3];
+    ms.Eps4,ms.Eps5,ms.Eps6,ms.Eps7=c[4],c[5],c[6],c[7];
+}
@@ -0,0 +1,159 @@
+package stateGenerator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/util/math"
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func kalmanInvalidCheck(kalmanSg KalmanFilterStateGen, err error) (func(t *testing.T)){
+    return func(t *testing.T){
+        if !customerr.IsInvalidValue(err) {
+            test.FormatError(customerr.InvalidValue(""),err,
+                "The wrong error was raised when creating an invalid kalman filter generator.",t,
+            );
+        }
+    }
+}
+func TestNewKalmanFilterStateGenInvalidProcessNoise(t *testing.T){
+    kalmanInvalidCheck(NewKalmanFilterStateGen(-1,1,1,false,1))(t);
+}
+func TestNewKalmanFilterStateGenInvalidMeasurementNoise(t *testing.T){
+    kalmanInvalidCheck(NewKalmanFilterStateGen(0,0,1,false,1))(t);
+}
+func TestNewKalmanFilterStateGenInvalidInitialCovariance(t *testing.T){
+    kalmanInvalidCheck(NewKalmanFilterStateGen(0,1,0,false,1))(t);
+}
+func TestNewKalmanFilterStateGenValid(t *testing.T){
+    kf,err:=NewKalmanFilterStateGen(1e-4,1e-2,1e3,true,0);
+    test.BasicTest(nil,err,
+        "Creating a kalman filter resulted in an error when it shouldn't have.",t,
+    );
+    test.BasicTest(1,kf.allotedThreads,
+        "The kalman filter was allotted the wrong number of threads.",t,
+    );
+}
+
+func TestKalmanFilterGenerateModelState(t *testing.T){
+    baseTime,_:=time.Parse("01/02/2006","09/10/2022");
+    kf,_:=NewKalmanFilterStateGen(1e-4,1e-2,1e3,false,1);
+    missingData:=missingModelStateData{
+        ClientID: 1,
+        ExerciseID: 15,
+        Date: baseTime,
+    };
+    ms,err:=kf.GenerateModelState(&testDB,[]potSurf.Surface{
+        potSurf.NewBasicSurface().ToGenericSurf(),
+        potSurf.NewVolumeBaseSurface().ToGenericSurf(),
+    },&missingData);
+    test.BasicTest(nil,err,
+        "Running the kalman filter state generator returned an error when it shouldn't have.",t,
+    );
+    test.BasicTest(2,len(ms),
+        "The kalman filter did not generate a model state for every surface.",t,
+    );
+    for i,s:=range([]potSurf.PotentialSurfaceId{
+        potSurf.BasicSurfaceId,potSurf.VolumeBaseSurfaceId,
+    }) {
+        test.BasicTest(int(s),ms[i].PotentialSurfaceID,
+            "Model state had the wrong surface id.",t,
+        );
+        test.BasicTest(int(KalmanFilterStateGenId),ms[i].StateGeneratorID,
+            "Model state had the wrong state generator id.",t,
+        );
+        test.BasicTest(true,ms[i].Date.Equal(baseTime),
+            "Model state had the wrong date.",t,
+        );
+    }
+}
+
+func TestKalmanFilterGenerateClientModelStatesPersistCovariance(t *testing.T){
+    kf,_:=NewKalmanFilterStateGen(1e-4,1e-2,1e3,true,2);
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    res,err:=kf.GenerateClientModelStates(&testDB,c,
+        time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC),
+        func() []potSurf.Surface {
+            return []potSurf.Surface{potSurf.NewBasicSurface().ToGenericSurf()};
+        },
+    );
+    test.BasicTest(nil,err,
+        "Generating client model states returned an error when it shouldn't have.",t,
+    );
+    test.BasicTest(true,res.A>0,"No model states were generated.",t);
+    ms,err,found:=db.Read(&testDB,db.ModelState{
+        StateGeneratorID: int(KalmanFilterStateGenId),
+    },algo.GenFilter(false,"StateGeneratorID")).Nth(0);
+    test.BasicTest(nil,err,"Reading a generated model state returned an error.",t);
+    test.BasicTest(true,found,"Could not find a generated model state.",t);
+    cnt,err:=db.Read(&testDB,db.ModelStateCovariance{ModelStateID: ms.Id},
+        algo.GenFilter(false,"ModelStateID"),
+    ).Count();
+    test.BasicTest(nil,err,"Reading the covariance returned an error.",t);
+    test.BasicTest(49,cnt,"The full covariance matrix was not saved.",t);
+}
+
+func TestKalmanFilterUpdateFilterAccumulatesError(t *testing.T){
+    kf,_:=NewKalmanFilterStateGen(1e-4,1e-2,1e3,false,1);
+    surf:=potSurf.NewBasicSurface();
+    s:=kalmanFilterSurface{
+        surface: surf.ToGenericSurf().(potSurf.LinearSurface),
+        filter: mathUtil.NewKalmanFilter(make([]float64,surf.NumConstants()),1e3),
+    };
+    day:=time.Date(2030,time.January,1,0,0,0,0,time.UTC);
+    for i:=0; i<5; i++ {
+        err:=kf.updateFilter(&s,&dataPoint{
+            DatePerformed: day.AddDate(0,0,i), Sets: 3, Reps: 5, Effort: 8,
+            Intensity: 0.8,
+        });
+        test.BasicTest(nil,err,"Updating the filter returned an error.",t);
+        if i==0 {
+            test.BasicTest(true,s.cumulativeSe>0.8*0.8-1e-9,
+                "The first prediction was not made before the update.",t,
+            );
+        }
+    }
+    test.BasicTest(5,s.numPoints,"Not every data point added to the error.",t);
+}
+
+func TestKalmanFilterUpdateFilterError(t *testing.T){
+    kf:=KalmanFilterStateGen{measurementNoise: 0};
+    surf:=potSurf.NewBasicSurface();
+    s:=kalmanFilterSurface{
+        surface: surf.ToGenericSurf().(potSurf.LinearSurface),
+        filter: mathUtil.NewKalmanFilter(make([]float64,surf.NumConstants()),0),
+    };
+    err:=kf.updateFilter(&s,&dataPoint{
+        DatePerformed: time.Date(2030,time.January,1,0,0,0,0,time.UTC),
+        Sets: 3, Reps: 5, Effort: 8, Intensity: 0.8,
+    });
+    if !math.IsDivByZero(err) {
+        test.FormatError(math.DivByZero(""),err,
+            "The error from updating the filter was not returned.",t,
+        );
+    }
+}
+
+func TestKalmanFilterIntensityHalfWidth(t *testing.T){
+    ms:=db.ModelState{
+        StateGeneratorID: int(KalmanFilterStateGenId),
+        PotentialSurfaceID: int(potSurf.BasicSurfaceId),
+        SampleCount: 17, Sigma: 0.02,
+    };
+    h,err:=IntensityHalfWidth(&ms,0.95);
+    test.BasicTest(nil,err,"Getting the half width returned an error.",t);
+    exp,_:=potSurf.PredictiveIntensityHalfWidth(&ms,0.95);
+    test.BasicTest(exp,h,"The kalman filter sigma was inflated.",t);
+    ms.StateGeneratorID=int(SlidingWindowStateGenId);
+    h,err=IntensityHalfWidth(&ms,0.95);
+    test.BasicTest(nil,err,"Getting the half width returned an error.",t);
+    exp,_=potSurf.IntensityHalfWidth(&ms,0.95);
+    test.BasicTest(exp,h,"The sliding window sigma was not inflated.",t);
+}
@@ -1,17 +1,53 @@
 package stateGenerator
 
-func timeFrameQuery() string {
-    return `SELECT DatePerformed,
+import (
+	"fmt"
+
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+)
+
+//The fitness and fatigue impulses are correlated sub-queries over the training
+//history of every selected training log, so they are only selected when one
+//of the surfaces uses them. Otherwise both are zero.
+func usesImpulses(surfs []potSurf.Surface) bool {
+    for _,s:=range(surfs) {
+        if _,ok:=s.Calculations().(potSurf.ImpulseCalculations); ok {
+            return true;
+        }
+    }
+    return false;
+}
+
+func impulseColumnsSql(impulses bool) []any {
+    if !impulses {
+        return []any{"0::FLOAT","0::FLOAT"};
+    }
+    return []any{
+        potSurf.BanisterImpulseSql(potSurf.BanisterFitnessTimeConstant),
+        potSurf.BanisterImpulseSql(potSurf.BanisterFatigueTimeConstant),
+    };
+}
+
+func timeFrameQuery(impulses bool) string {
+    return fmt.Sprintf(`SELECT DatePerformed,
             Sets, Reps, Effort, Intensity,
-            InterExerciseFatigue, InterWorkoutFatigue
+            InterExerciseFatigue, InterWorkoutFatigue,
+            %s AS Fitness,
+            %s AS Fatigue
         FROM TrainingLog
         WHERE TrainingLog.DatePerformed<=$1
             AND TrainingLog.DatePerformed>$2
             AND TrainingLog.ExerciseID=$3
             AND TrainingLog.ClientID=$4
+            AND %s
         ORDER BY 
             DatePerformed DESC,
-            Id ASC;`;
+            Id ASC;`,
+        append(
+            impulseColumnsSql(impulses),
+            potSurf.NotExcludedOutlierSql("TrainingLog"),
+        )...,
+    );
 }
 
 func missingModelStatesForGivenStateGenQuery() string {
@@ -39,3 +75,36 @@ func missingModelStatesForGivenStateGenQuery() string {
         ) GROUP BY newTl.DatePerformed, newTl.ExerciseID, newTl.ClientID;`;
 }
 
+func dataBetweenDatesQuery(impulses bool) string {
+    return fmt.Sprintf(`SELECT DatePerformed,
+            Sets, Reps, Effort, Intensity,
+            InterExerciseFatigue, InterWorkoutFatigue,
+            %s AS Fitness,
+            %s AS Fatigue
+        FROM TrainingLog
+        WHERE TrainingLog.DatePerformed>$1
+            AND TrainingLog.DatePerformed<=$2
+            AND TrainingLog.ExerciseID=$3
+            AND TrainingLog.ClientID=$4
+            AND %s
+        ORDER BY
+            DatePerformed ASC,
+            Id ASC;`,
+        append(
+            impulseColumnsSql(impulses),
+            potSurf.NotExcludedOutlierSql("TrainingLog"),
+        )...,
+    );
+}
+
+func previousModelStateQuery() string {
+    return `SELECT *
+        FROM ModelState
+        WHERE ModelState.ClientID=$1
+            AND ModelState.ExerciseID=$2
+            AND ModelState.StateGeneratorID=$3
+            AND ModelState.PotentialSurfaceID=$4
+            AND ModelState.Date<$5
+        ORDER BY Date DESC
+        LIMIT 1;`;
+}
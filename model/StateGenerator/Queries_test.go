@@ -0,0 +1,33 @@
+package stateGenerator
+
+import (
+	"strings"
+	"testing"
+
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestUsesImpulses(t *testing.T){
+    test.BasicTest(false,usesImpulses([]potSurf.Surface{
+        potSurf.NewBasicSurface().ToGenericSurf(),
+        potSurf.NewVolumeBaseSurface().ToGenericSurf(),
+    }),"Surfaces without impulses selected the impulses.",t);
+    test.BasicTest(true,usesImpulses([]potSurf.Surface{
+        potSurf.NewBasicSurface().ToGenericSurf(),
+        potSurf.NewBanisterSurface().ToGenericSurf(),
+    }),"The banister surface did not select the impulses.",t);
+}
+
+func TestImpulseColumns(t *testing.T){
+    for _,q:=range([]string{timeFrameQuery(false),dataBetweenDatesQuery(false)}) {
+        test.BasicTest(false,strings.Contains(q,"hist"),
+            "The impulse sub-queries were selected when not needed.",t,
+        );
+    }
+    for _,q:=range([]string{timeFrameQuery(true),dataBetweenDatesQuery(true)}) {
+        test.BasicTest(true,strings.Contains(q,"hist"),
+            "The impulse sub-queries were not selected.",t,
+        );
+    }
+}
@@ -0,0 +1,200 @@
+package stateGenerator
+
+import (
+	stdMath "math"
+
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The criteria that was used to select a model state. The values are saved in
+//the SelectionCriteria column of the ModelState table so they must not be
+//re-ordered. Zero is used by state generators that do not select between
+//candidate model states.
+type SelectionCriteriaId int;
+const (
+    NoSelectionCriteriaId SelectionCriteriaId=iota
+    StabilityThenMseCriteriaId
+    AicCriteriaId
+    BicCriteriaId
+    AdjustedR2CriteriaId
+    CrossValidationCriteriaId
+);
+
+func (s SelectionCriteriaId)String() string {
+    switch s {
+        case NoSelectionCriteriaId: return "None";
+        case StabilityThenMseCriteriaId: return "Stability Then MSE";
+        case AicCriteriaId: return "AIC";
+        case BicCriteriaId: return "BIC";
+        case AdjustedR2CriteriaId: return "Adjusted R2";
+        case CrossValidationCriteriaId: return "Cross Validation";
+        default: return "unknown";
+    }
+}
+
+//The values that describe a candidate model state. All of the errors are
+//calculated over the data points in the window.
+//  - Stability: the number of positive constants
+//  - Rcond: the reciprocal condition number of the regression
+//  - NumConstants: the number of constants in the surface
+//  - WindowPoints: the number of data points in the window
+//  - Sse: the sum of the squared errors
+//  - Sst: the total sum of squares of the actual values around their mean
+//  - CvSse: the sum of the squared errors when each day in the window is left
+//    out of the regression, only set when the criteria uses cross validation
+//  - EvalPoints: the number of data points in the common evaluation set, which
+//    is every data point in the full window
+//  - EvalSse: the sum of the squared errors over the common evaluation set
+type Candidate struct {
+    Stability int;
+    Rcond float64;
+    NumConstants int;
+    WindowPoints int;
+    Sse float64;
+    Sst float64;
+    CvSse float64;
+    EvalPoints int;
+    EvalSse float64;
+};
+
+func (c *Candidate)Mse() float64 {
+    return c.Sse/float64(c.WindowPoints);
+}
+
+//Selects between the candidate model states a state generator creates.
+type SelectionCriteria interface {
+    Id() SelectionCriteriaId;
+    //Returns true if the candidate should replace the current best candidate.
+    //The current best candidate is nil until a candidate has been accepted.
+    Better(c *Candidate, best *Candidate) bool;
+    //Returns true if the criteria needs the CvSse field of the candidates to
+    //be calculated.
+    UsesCrossValidation() bool;
+};
+
+//Accepts the candidate if it has more positive constants, and if the number of
+//positive constants is the same then the lower MSE is accepted.
+type StabilityThenMse struct {};
+func (s StabilityThenMse)Id() SelectionCriteriaId { return StabilityThenMseCriteriaId; }
+func (s StabilityThenMse)UsesCrossValidation() bool { return false; }
+func (s StabilityThenMse)Better(c *Candidate, best *Candidate) bool {
+    if best==nil {
+        return true;
+    }
+    return c.Stability>best.Stability ||
+        (c.Stability==best.Stability && c.Mse()<best.Mse());
+}
+
+//Accepts the candidate with the lowest Akaike information criterion over the
+//common evaluation set:
+//  AIC=n*ln(SSE/n)+2k
+type Aic struct {};
+func (a Aic)Id() SelectionCriteriaId { return AicCriteriaId; }
+func (a Aic)UsesCrossValidation() bool { return false; }
+func (a Aic)Better(c *Candidate, best *Candidate) bool {
+    return lowerEvalScore(c,best,func(c *Candidate) float64 {
+        n:=float64(c.EvalPoints);
+        return n*stdMath.Log(c.EvalSse/n)+2*float64(c.NumConstants);
+    });
+}
+
+//Accepts the candidate with the lowest Bayesian information criterion over the
+//common evaluation set:
+//  BIC=n*ln(SSE/n)+k*ln(n)
+type Bic struct {};
+func (b Bic)Id() SelectionCriteriaId { return BicCriteriaId; }
+func (b Bic)UsesCrossValidation() bool { return false; }
+func (b Bic)Better(c *Candidate, best *Candidate) bool {
+    return lowerEvalScore(c,best,func(c *Candidate) float64 {
+        n:=float64(c.EvalPoints);
+        return n*stdMath.Log(c.EvalSse/n)+float64(c.NumConstants)*stdMath.Log(n);
+    });
+}
+
+//Accepts the candidate with the highest adjusted R^2:
+//  R^2=1-(SSE/(n-k-1))/(SST/(n-1))
+//Candidates that do not have more points than constants are never accepted
+//over a candidate that does.
+type AdjustedR2 struct {};
+func (a AdjustedR2)Id() SelectionCriteriaId { return AdjustedR2CriteriaId; }
+func (a AdjustedR2)UsesCrossValidation() bool { return false; }
+func (a AdjustedR2)Better(c *Candidate, best *Candidate) bool {
+    return lowerScore(c,best,func(c *Candidate) float64 {
+        dof:=c.WindowPoints-c.NumConstants-1;
+        if dof<=0 || c.Sst<=0 {
+            return stdMath.Inf(1);
+        }
+        return (c.Sse/float64(dof))/(c.Sst/float64(c.WindowPoints-1))-1;
+    });
+}
+
+//Accepts the candidate with the lowest mean squared error when each day in
+//the window is left out of the regression and then predicted.
+type CrossValidation struct {};
+func (v CrossValidation)Id() SelectionCriteriaId { return CrossValidationCriteriaId; }
+func (v CrossValidation)UsesCrossValidation() bool { return true; }
+func (v CrossValidation)Better(c *Candidate, best *Candidate) bool {
+    return lowerScore(c,best,func(c *Candidate) float64 {
+        return c.CvSse/float64(c.WindowPoints);
+    });
+}
+
+//Rejects any candidate whose regression has a reciprocal condition number
+//less than the minimum and otherwise defers to the wrapped criteria. The id of
+//the wrapped criteria is used, the rcond that is saved with the model state
+//shows that the gate was passed.
+type MinRcondGate struct {
+    minRcond float64;
+    criteria SelectionCriteria;
+};
+
+func NewMinRcondGate(
+        minRcond float64,
+        criteria SelectionCriteria) (MinRcondGate,error) {
+    rv:=MinRcondGate{minRcond: minRcond, criteria: criteria};
+    if minRcond<0 || minRcond>1 {
+        return rv,customerr.ValOutsideRange("min rcond must be in the range [0,1]");
+    } else if criteria==nil {
+        return rv,customerr.InvalidValue("a criteria to gate is needed");
+    }
+    return rv,nil;
+}
+
+func (m MinRcondGate)Id() SelectionCriteriaId { return m.criteria.Id(); }
+func (m MinRcondGate)UsesCrossValidation() bool {
+    return m.criteria.UsesCrossValidation();
+}
+func (m MinRcondGate)Better(c *Candidate, best *Candidate) bool {
+    return c.Rcond>=m.minRcond && m.criteria.Better(c,best);
+}
+
+//NaN scores are never accepted.
+func lowerScore(
+        c *Candidate,
+        best *Candidate,
+        score func(c *Candidate) float64) bool {
+    cScore:=score(c);
+    if stdMath.IsNaN(cScore) {
+        return false;
+    } else if best==nil {
+        return true;
+    }
+    return cScore<score(best);
+}
+
+//Information criteria are only comparable between fits that are scored on the
+//same data points, so they are scored on the common evaluation set. A best
+//candidate that was scored on fewer points was found before the full window
+//was known, so it is replaced. Every window of a fit has the same score, so
+//ties are broken by the wider window.
+func lowerEvalScore(
+        c *Candidate,
+        best *Candidate,
+        score func(c *Candidate) float64) bool {
+    if best!=nil && c.EvalPoints!=best.EvalPoints {
+        return c.EvalPoints>best.EvalPoints && !stdMath.IsNaN(score(c));
+    } else if lowerScore(c,best,score) {
+        return true;
+    }
+    return best!=nil && score(c)==score(best) && c.WindowPoints>best.WindowPoints;
+}
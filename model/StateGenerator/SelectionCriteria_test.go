@@ -0,0 +1,247 @@
+package stateGenerator
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestSelectionCriteriaIdString(t *testing.T){
+    test.BasicTest("AIC",AicCriteriaId.String(),
+        "Selection criteria string was not correct.",t,
+    );
+    test.BasicTest("unknown",SelectionCriteriaId(-1).String(),
+        "Selection criteria string was not correct.",t,
+    );
+}
+
+func TestSelectionCriteriaAcceptsFirst(t *testing.T){
+    c:=Candidate{Stability: 1, NumConstants: 2, WindowPoints: 5, Sse: 1, Sst: 4,
+        EvalPoints: 5, EvalSse: 1,
+    };
+    for _,s:=range([]SelectionCriteria{
+        StabilityThenMse{}, Aic{}, Bic{}, AdjustedR2{}, CrossValidation{},
+    }) {
+        test.BasicTest(true,s.Better(&c,nil),
+            "The first candidate was not accepted.",t,
+        );
+    }
+}
+
+func TestStabilityThenMse(t *testing.T){
+    best:=Candidate{Stability: 5, WindowPoints: 10, Sse: 1};
+    s:=StabilityThenMse{};
+    test.BasicTest(true,
+        s.Better(&Candidate{Stability: 6, WindowPoints: 10, Sse: 5},&best),
+        "A more stable candidate was not accepted.",t,
+    );
+    test.BasicTest(true,
+        s.Better(&Candidate{Stability: 5, WindowPoints: 10, Sse: 0.5},&best),
+        "An equally stable candidate with a lower mse was not accepted.",t,
+    );
+    test.BasicTest(false,
+        s.Better(&Candidate{Stability: 4, WindowPoints: 10, Sse: 0},&best),
+        "A less stable candidate was accepted.",t,
+    );
+}
+
+func TestInformationCriteria(t *testing.T){
+    low:=Candidate{NumConstants: 7, EvalPoints: 10, EvalSse: 1};
+    high:=Candidate{NumConstants: 7, EvalPoints: 10, EvalSse: 2};
+    test.BasicTest(true,Aic{}.Better(&low,&high),
+        "AIC did not prefer the lower score.",t,
+    );
+    test.BasicTest(false,Aic{}.Better(&high,&low),
+        "AIC preferred the higher score.",t,
+    );
+    //BIC penalizes constants more than AIC when ln(n)>2
+    a:=Candidate{NumConstants: 1, EvalPoints: 100, EvalSse: 10.5};
+    b:=Candidate{NumConstants: 3, EvalPoints: 100, EvalSse: 10};
+    test.BasicTest(true,Aic{}.Better(&b,&a),
+        "AIC did not prefer the better fit.",t,
+    );
+    test.BasicTest(true,Bic{}.Better(&a,&b),
+        "BIC did not prefer the simpler model.",t,
+    );
+}
+
+func TestInformationCriteriaCommonEvaluation(t *testing.T){
+    //A wider window has a lower n*ln(SSE/n) even with a worse fit, so only the
+    //common evaluation set is used
+    best:=Candidate{NumConstants: 7, WindowPoints: 5, Sse: 0.001,
+        EvalPoints: 50, EvalSse: 0.02,
+    };
+    wide:=Candidate{NumConstants: 7, WindowPoints: 50, Sse: 0.03,
+        EvalPoints: 50, EvalSse: 0.03,
+    };
+    for _,s:=range([]SelectionCriteria{Aic{}, Bic{}}) {
+        test.BasicTest(false,s.Better(&wide,&best),
+            "A wider window with a worse fit was accepted.",t,
+        );
+        tie:=best;
+        tie.WindowPoints=10;
+        test.BasicTest(true,s.Better(&tie,&best),
+            "A tie was not broken by the wider window.",t,
+        );
+        partial:=best;
+        partial.EvalPoints,partial.EvalSse=20,0.001;
+        test.BasicTest(false,s.Better(&partial,&best),
+            "A candidate scored on a partial window was accepted.",t,
+        );
+        test.BasicTest(true,s.Better(&best,&partial),
+            "A best candidate scored on a partial window was not replaced.",t,
+        );
+    }
+}
+
+func TestAdjustedR2(t *testing.T){
+    best:=Candidate{NumConstants: 2, WindowPoints: 10, Sse: 2, Sst: 10};
+    test.BasicTest(true,AdjustedR2{}.Better(
+        &Candidate{NumConstants: 2, WindowPoints: 10, Sse: 1, Sst: 10},&best,
+    ),"A higher adjusted R2 was not accepted.",t);
+    test.BasicTest(false,AdjustedR2{}.Better(
+        &Candidate{NumConstants: 2, WindowPoints: 3, Sse: 0, Sst: 10},&best,
+    ),"A candidate without enough points was accepted.",t);
+}
+
+func TestCrossValidationCriteria(t *testing.T){
+    best:=Candidate{WindowPoints: 10, Sse: 0, CvSse: 5};
+    test.BasicTest(true,CrossValidation{}.Better(
+        &Candidate{WindowPoints: 10, Sse: 1, CvSse: 4},&best,
+    ),"A lower cross validated error was not accepted.",t);
+    test.BasicTest(false,CrossValidation{}.Better(
+        &Candidate{WindowPoints: 10, Sse: 0, CvSse: stdMath.NaN()},nil,
+    ),"A NaN score was accepted.",t);
+    test.BasicTest(true,CrossValidation{}.UsesCrossValidation(),
+        "Cross validation did not request cross validated errors.",t,
+    );
+}
+
+func TestMinRcondGate(t *testing.T){
+    _,err:=NewMinRcondGate(2,StabilityThenMse{});
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid min rcond did not return an error.",t,
+        );
+    }
+    _,err=NewMinRcondGate(0.1,nil);
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A nil criteria did not return an error.",t,
+        );
+    }
+    g,err:=NewMinRcondGate(1e-3,Aic{});
+    test.BasicTest(nil,err,"Creating a gate returned an error.",t);
+    test.BasicTest(AicCriteriaId,g.Id(),"The gate did not use the wrapped id.",t);
+    test.BasicTest(false,g.Better(
+        &Candidate{Rcond: 1e-6, NumConstants: 1, EvalPoints: 5, EvalSse: 1},nil,
+    ),"A badly conditioned candidate was accepted.",t);
+    test.BasicTest(true,g.Better(
+        &Candidate{Rcond: 1e-2, NumConstants: 1, EvalPoints: 5, EvalSse: 1},nil,
+    ),"A well conditioned candidate was rejected.",t);
+}
+
+func TestWithSelectionCriteria(t *testing.T){
+    sw,_:=NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 0, B: 1},dataStruct.Pair[int,int]{A: 0, B: 1},1,
+    );
+    test.BasicTest(StabilityThenMseCriteriaId,sw.criteria.Id(),
+        "The default selection criteria was not correct.",t,
+    );
+    _,err:=sw.WithSelectionCriteria(nil);
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A nil criteria did not return an error.",t,
+        );
+    }
+    bic,err:=sw.WithSelectionCriteria(Bic{});
+    test.BasicTest(nil,err,"Setting the criteria returned an error.",t);
+    test.BasicTest(BicCriteriaId,bic.criteria.Id(),
+        "The selection criteria was not set.",t,
+    );
+    test.BasicTest(StabilityThenMseCriteriaId,sw.criteria.Id(),
+        "Setting the criteria modified the original state generator.",t,
+    );
+}
+
+func leaveDayOutTestData() [][]dataPoint {
+    rv:=make([][]dataPoint,4);
+    for i:=0; i<24; i++ {
+        d:=dataPoint{
+            Sets: float64(1+i%3),
+            Reps: float64(1+(i*7)%5),
+            Effort: float64(6+i%4),
+            InterExerciseFatigue: float64(i%2),
+            InterWorkoutFatigue: float64((i*5)%7),
+        };
+        d.Intensity=0.5+0.05*d.Effort-0.002*d.InterWorkoutFatigue-
+            0.004*d.InterExerciseFatigue-
+            0.001*(d.Sets-1)*(d.Sets-1)*(d.Reps-1)*(d.Reps-1)-
+            0.01*(d.Sets-1)*(d.Sets-1)-0.02*(d.Reps-1)*(d.Reps-1)+
+            0.003*stdMath.Sin(float64(i));
+        rv[i%4]=append(rv[i%4],d);
+    }
+    return rv;
+}
+
+func TestLeaveDayOutSe(t *testing.T){
+    days:=leaveDayOutTestData();
+    for _,newSurf:=range([]func() potSurf.Surface{
+        func() potSurf.Surface { return potSurf.NewBasicSurface().ToGenericSurf(); },
+        func() potSurf.Surface { return potSurf.NewVolumeBaseSurface().ToGenericSurf(); },
+    }) {
+        full:=newSurf();
+        for _,day:=range(days) {
+            for _,d:=range(day) {
+                full.Update(d.vars());
+            }
+        }
+        full.Run();
+        sw:=SlidingWindowStateGen{windowValues: days};
+        for i,_:=range(days) {
+            res,err:=sw.getLeaveDayOutSe(full,i);
+            test.BasicTest(nil,err,"Leave day out se returned an error.",t);
+            //Re-fit without the day and predict it
+            partial:=newSurf();
+            for j,day:=range(days) {
+                if j!=i {
+                    for _,d:=range(day) {
+                        partial.Update(d.vars());
+                    }
+                }
+            }
+            partial.Run();
+            exp:=0.0;
+            for _,d:=range(days[i]) {
+                p,_:=partial.PredictIntensity(d.vars());
+                exp+=(d.Intensity-p)*(d.Intensity-p);
+            }
+            test.BasicTest(true,stdMath.Abs(exp-res)<1e-9*stdMath.Max(1,exp),
+                "The leave day out error did not match re-fitting without the day.",t,
+            );
+        }
+    }
+}
+
+func TestCvSeRestoresFit(t *testing.T){
+    days:=leaveDayOutTestData();
+    full:=potSurf.NewVolumeBaseSurface();
+    for _,day:=range(days) {
+        for _,d:=range(day) {
+            full.Update(d.vars());
+        }
+    }
+    full.Run();
+    exp:=full.GetConstant(0);
+    sw:=SlidingWindowStateGen{windowValues: days, criteria: CrossValidation{}};
+    cvSe,err:=sw.getCvSe(&full);
+    test.BasicTest(nil,err,"Getting the cross validated errors returned an error.",t);
+    test.BasicTest(len(days),len(cvSe),"Not every day had an error.",t);
+    test.BasicTest(true,stdMath.Abs(exp-full.GetConstant(0))<1e-12,
+        "The full fit was not restored.",t,
+    );
+}
@@ -15,13 +15,24 @@ import (
 	customerr "github.com/barbell-math/engine/util/err"
 )
 
+//Surfaces whose regressions can cache their inverse between runs.
+type inverseCacher interface {
+    SetInverseCache(enabled bool, refreshEvery int) error;
+};
+
 type SlidingWindowStateGen struct {
     allotedThreads int;
     windowLimits dataStruct.Pair[int,int];
     timeFrameLimits dataStruct.Pair[int,int];
     windowValues [][]dataPoint;
     optimalMs []db.ModelState;
+    bestCandidates []*Candidate;
     models []potSurf.Surface;
+    samples []mathUtil.Vars[float64];
+    criteria SelectionCriteria;
+    rankOneUpdates bool;
+    inverseRefresh int;
+    rolling bool;
     withinWindowLimits (func(t stdTime.Time) bool);
 };
 
@@ -42,7 +53,7 @@ func NewSlidingWindowStateGen(
         }, windowLimits: dataStruct.Pair[int,int]{
             A: -mathUtil.Abs(windowLimits.A),
             B: -mathUtil.Abs(windowLimits.B),
-        }, 
+        }, criteria: StabilityThenMse{},
     };
     if rv.timeFrameLimits.A<rv.timeFrameLimits.B {
         return rv,customerr.InvalidValue("min time frame > max time frame");
@@ -60,6 +71,47 @@ func (s SlidingWindowStateGen)Id() StateGeneratorId {
     return SlidingWindowStateGenId;
 }
 
+//Returns a copy of the state generator that selects between the candidate
+//model states with the given criteria. The default criteria is
+//StabilityThenMse.
+func (s SlidingWindowStateGen)WithSelectionCriteria(
+        c SelectionCriteria) (SlidingWindowStateGen,error) {
+    if c==nil {
+        return s,customerr.InvalidValue("a selection criteria is needed");
+    }
+    s.criteria=c;
+    return s,nil;
+}
+
+//Returns a copy of the state generator that caches the inverse of each
+//surfaces regression between the days in the time frame. Each data point is
+//then applied to the inverse as a rank one update rather than re-inverting the
+//regression every day, which is re-calculated after refreshEvery updates. A
+//value of zero uses the linear regs default refresh. Surfaces that do not
+//support caching are run normally.
+func (s SlidingWindowStateGen)WithRankOneUpdates(
+        refreshEvery int) (SlidingWindowStateGen,error) {
+    if refreshEvery<0 {
+        return s,customerr.ValOutsideRange("refreshEvery must be >=0");
+    }
+    s.rankOneUpdates,s.inverseRefresh=true,refreshEvery;
+    return s,nil;
+}
+
+//Returns a copy of the state generator that fits every model state with a
+//fixed length time frame, set by the max time frame limit. Rather than reading
+//and fitting the time frame again for every date, the model states of each
+//exercise are generated in date order and the time frame is rolled forward one
+//session at a time. New sessions are added to the surfaces and sessions that
+//fall out of the time frame are removed from them. The window is still selected
+//with the selection criteria. When combined with WithRankOneUpdates every
+//session is a rank one update of the cached inverse. Every surface must allow
+//samples to be removed (see potSurf.RemovableSurface).
+func (s SlidingWindowStateGen)WithRollingTimeFrame() SlidingWindowStateGen {
+    s.rolling=true;
+    return s;
+}
+
 //The method receiver is not a pointer so that the object will be copied. It is
 //meant to be called in parallel (i.e. multiple clients) so the copy is necessary.
 func (s SlidingWindowStateGen)GenerateClientModelStates(
@@ -67,6 +119,9 @@ func (s SlidingWindowStateGen)GenerateClientModelStates(
         c db.Client,
         minTime stdTime.Time,
         surfaceFactory func() []potSurf.Surface) (dataStruct.Pair[int,int],error) {
+    if s.rolling {
+        return s.generateRollingModelStates(d,c,minTime,surfaceFactory);
+    }
     rv:=dataStruct.Pair[int,int]{A: 0, B: 0};
     bufCreator,err:=db.NewBufferedCreate[db.ModelState](100);
     if err!=nil {
@@ -114,14 +169,36 @@ func (s SlidingWindowStateGen)GenerateModelState(
         ));
         return []db.ModelState{},err;
     }
-    return s.optimalMs,err;
+    //Surfaces that never had a candidate accepted are not returned
+    rv:=make([]db.ModelState,0,len(s.optimalMs));
+    for i,ms:=range(s.optimalMs) {
+        if s.bestCandidates[i]!=nil {
+            rv=append(rv,ms);
+        }
+    }
+    return rv,err;
 }
 
 func (s *SlidingWindowStateGen)setInitialOptimalMsValues(
         missingData *missingModelStateData,
         surfaces []potSurf.Surface){
     s.models=surfaces;
+    s.samples=[]mathUtil.Vars[float64]{};
+    if s.rankOneUpdates {
+        for _,m:=range(s.models) {
+            if c,ok:=m.(inverseCacher); ok {
+                c.SetInverseCache(true,s.inverseRefresh);
+            }
+        }
+    }
+    s.resetOptimalMsValues(missingData);
+}
+
+func (s *SlidingWindowStateGen)resetOptimalMsValues(
+        missingData *missingModelStateData){
+    s.windowValues=nil;
     s.optimalMs=make([]db.ModelState,len(s.models));
+    s.bestCandidates=make([]*Candidate,len(s.models));
     for i,_:=range(s.models) {
         s.optimalMs[i]=db.ModelState{
             Mse: stdMath.Inf(1),
@@ -130,6 +207,7 @@ func (s *SlidingWindowStateGen)setInitialOptimalMsValues(
             ExerciseID: missingData.ExerciseID,
             StateGeneratorID: int(SlidingWindowStateGenId),
             PotentialSurfaceID: int(s.models[i].Id()),
+            SelectionCriteria: int(s.criteria.Id()),
         };
     }
 }
@@ -140,7 +218,8 @@ func (s *SlidingWindowStateGen)runAlgo(
 ) (int,error) {
     cntr:=0;
     var curDate stdTime.Time;
-    err:=db.CustomReadQuery[dataPoint](d,timeFrameQuery(),[]any{
+    err:=db.CustomReadQuery[dataPoint](d,
+        timeFrameQuery(usesImpulses(s.models)),[]any{
         missingData.Date.AddDate(0, 0, s.timeFrameLimits.A),
         missingData.Date.AddDate(0, 0, s.timeFrameLimits.B),
         missingData.ExerciseID,
@@ -178,30 +257,48 @@ func (s *SlidingWindowStateGen)runAlgo(
 //         pred and actual lists are different lengths then it means there was
 //         an error generating intensity predictions and the model state should
 //         be dis-regarded
-//      3. Assuming the previous step succeeded, calculate the total SE
-//      4. Calculate the mean SE (MSE)
-//      5. If the MSE is less than the current lowest MSE then save a new
-//         model state
+//      3. Assuming the previous step succeeded, accumulate the errors of the
+//         days up to and including the current day to create a candidate.
+//         The errors of every day in the window are the candidates common
+//         evaluation set
+//      4. If the selection criteria prefers the candidate over the current
+//         best candidate then save a new model state
 func (s *SlidingWindowStateGen)calcAndSetModelState(
         d *dataPoint,
         missingData *missingModelStateData) error {
     for i,m:=range(s.models) {
-        var numPoints float64=0;
-        var cumulativeSe float64=0.0;
+        c:=Candidate{NumConstants: m.NumConstants()};
+        var sum,sumSq float64=0,0;
         rcond,_:=m.Run();
-        curStability:=m.Stability();
+        c.Rcond=rcond;
+        c.Stability=m.Stability();
+        daySe:=make([]float64,len(s.windowValues));
         for j,w:=range(s.windowValues) {
-            totalSe,err:=s.getActualAndPredSe(m,j);
-            if err!=nil {
+            var err error;
+            if daySe[j],err=s.getActualAndPredSe(m,j); err!=nil {
                 return err;
             }
-            numPoints+=float64(len(w));
-            cumulativeSe+=totalSe;
-            oldStability:=m.Calculations().Stability(&s.optimalMs[i]);
-            if err==nil && (
-                curStability>oldStability || (curStability==oldStability && 
-                cumulativeSe/numPoints<s.optimalMs[i].Mse)) {
-                s.saveModelState(i,rcond,cumulativeSe/numPoints,
+            c.EvalPoints+=len(w);
+            c.EvalSse+=daySe[j];
+        }
+        cvSe,err:=s.getCvSe(m);
+        if err!=nil {
+            return err;
+        }
+        for j,w:=range(s.windowValues) {
+            totalSe:=daySe[j];
+            c.CvSse+=cvSe[j];
+            for _,v:=range(w) {
+                sum+=v.Intensity;
+                sumSq+=v.Intensity*v.Intensity;
+            }
+            c.WindowPoints+=len(w);
+            c.Sse+=totalSe;
+            c.Sst=sumSq-sum*sum/float64(c.WindowPoints);
+            if s.criteria.Better(&c,s.bestCandidates[i]) {
+                best:=c;
+                s.bestCandidates[i]=&best;
+                s.saveModelState(i,rcond,c.Mse(),
                     timeUtil.DaysBetween(missingData.Date,w[0].DatePerformed),
                     timeUtil.DaysBetween(missingData.Date,d.DatePerformed),
                 );
@@ -218,14 +315,7 @@ func (s *SlidingWindowStateGen)getActualAndPredSe(m potSurf.Surface,
     ),make([]float64,len(s.windowValues[windowIndex]));
     for i,v:=range(s.windowValues[windowIndex]) {
         actual[i]=v.Intensity;
-        if iterPred,err:=m.PredictIntensity(map[string]float64{
-            "I": v.Intensity,
-            "E": v.Effort,
-            "R": float64(v.Reps),
-            "S": float64(v.Sets),
-            "F_w": float64(v.InterWorkoutFatigue),
-            "F_e": float64(v.InterExerciseFatigue),
-        }); err==nil {
+        if iterPred,err:=m.PredictIntensity(v.vars()); err==nil {
             pred[i]=iterPred;
         }
     }
@@ -239,6 +329,61 @@ func (s *SlidingWindowStateGen)getActualAndPredSe(m potSurf.Surface,
     return cumulativeSe,err;
 }
 
+//Returns the leave day out errors for each day in the window if the criteria
+//uses cross validation. Leaving a day out re-fits the surface, so the full fit
+//is restored before returning.
+func (s *SlidingWindowStateGen)getCvSe(m potSurf.Surface) ([]float64,error) {
+    rv:=make([]float64,len(s.windowValues));
+    if !s.criteria.UsesCrossValidation() {
+        return rv,nil;
+    }
+    for j,_:=range(s.windowValues) {
+        var err error;
+        if rv[j],err=s.getLeaveDayOutSe(m,j); err!=nil {
+            return rv,err;
+        }
+    }
+    _,err:=m.Run();
+    return rv,err;
+}
+
+//The errors when a day is left out of the regression are found by removing the
+//days points from the surface, re-fitting it, and predicting the days
+//intensities. Re-fitting keeps the sample weights, the bounds of the constants,
+//and the units of the regression the same as the full fit. The points are added
+//back once the errors are found, so the caller needs to re-run the surface. If
+//the surface does not allow points to be removed, or it can not be fit without
+//the day, the errors are infinite.
+func (s *SlidingWindowStateGen)getLeaveDayOutSe(m potSurf.Surface,
+        windowIndex int) (float64,error) {
+    r,ok:=m.(potSurf.RemovableSurface);
+    if !ok {
+        return stdMath.Inf(1),nil;
+    }
+    w:=s.windowValues[windowIndex];
+    for i,_:=range(w) {
+        if err:=r.Remove(w[i].vars()); err!=nil {
+            return 0,err;
+        }
+    }
+    rv:=0.0;
+    _,err:=r.Run();
+    for i:=0; err==nil && i<len(w); i++ {
+        var pred float64;
+        pred,err=r.PredictIntensity(w[i].vars());
+        rv+=(w[i].Intensity-pred)*(w[i].Intensity-pred);
+    }
+    if err!=nil {
+        rv=stdMath.Inf(1);
+    }
+    for i,_:=range(w) {
+        if err:=r.Update(w[i].vars()); err!=nil {
+            return 0,err;
+        }
+    }
+    return rv,nil;
+}
+
 func (s *SlidingWindowStateGen)saveModelState(
         i int,
         rcond float64,
@@ -257,6 +402,10 @@ func (s *SlidingWindowStateGen)saveModelState(
     s.optimalMs[i].Win=winLen;
     s.optimalMs[i].Rcond=rcond;
     s.optimalMs[i].Mse=mse;
+    s.optimalMs[i].SampleCount=s.models[i].GetSampleCount();
+    //The residual variance is in the units of the regression, which is not
+    //always intensity
+    s.optimalMs[i].Sigma,_=potSurf.IntensitySigma(s.models[i],s.samples);
     SLIDING_WINDOW_MS_DEBUG.Log("ModelState",s.optimalMs[i]);
 }
 
@@ -272,14 +421,23 @@ func (s *SlidingWindowStateGen)updateWindowValues(d *dataPoint){
 
 func (s *SlidingWindowStateGen)updateModel(d *dataPoint){
     for _,m:=range(s.models) {
-        m.Update(map[string]float64{
-            "I": d.Intensity, "R": d.Reps, "E": d.Effort, "S": d.Sets,
-            "F_w": d.InterWorkoutFatigue, "F_e": d.InterExerciseFatigue,
-        });
+        m.Update(d.vars());
     }
+    s.samples=append(s.samples,d.vars());
     SLIDING_WINDOW_DP_DEBUG.Log("DataPoint",d);
 }
 
+//Removes the oldest data point from the models. The models must be removable.
+func (s *SlidingWindowStateGen)removeOldestFromModel(d *dataPoint) error {
+    for _,m:=range(s.models) {
+        if err:=m.(potSurf.RemovableSurface).Remove(d.vars()); err!=nil {
+            return err;
+        }
+    }
+    s.samples=s.samples[1:];
+    return nil;
+}
+
 func (s *SlidingWindowStateGen)setWithinWindowLimits(
         missingDataTime stdTime.Time){
     s.withinWindowLimits=timeUtil.Between(
@@ -287,3 +445,124 @@ func (s *SlidingWindowStateGen)setWithinWindowLimits(
         missingDataTime.AddDate(0, 0, s.windowLimits.B),
     );
 }
+
+//Generates the missing model states of the client with a rolling time frame.
+//The exercises are generated in parallel, the dates of each exercise are
+//generated in order.
+func (s SlidingWindowStateGen)generateRollingModelStates(
+        d *db.DB,
+        c db.Client,
+        minTime stdTime.Time,
+        surfaceFactory func() []potSurf.Surface) (dataStruct.Pair[int,int],error) {
+    rv:=dataStruct.Pair[int,int]{A: 0, B: 0};
+    missing,err:=db.CustomReadQuery[missingModelStateData](d,
+        missingModelStatesForGivenStateGenQuery(),[]any{c.Id,s.Id(),minTime},
+    ).Collect();
+    if err==sql.ErrNoRows {
+        return rv,nil;
+    } else if err!=nil {
+        return rv,err;
+    }
+    bufCreator,err:=db.NewBufferedCreate[db.ModelState](100);
+    if err!=nil {
+        return rv,err;
+    }
+    err=iter.Parallel[[]*missingModelStateData,dataStruct.Pair[[]db.ModelState,int]](
+        iter.SliceElems(groupMissingDataByExercise(missing)),
+        func(val []*missingModelStateData) (dataStruct.Pair[[]db.ModelState,int], error) {
+            surfs:=surfaceFactory();
+            data,err:=db.CustomReadQuery[dataPoint](d,
+                dataBetweenDatesQuery(usesImpulses(surfs)),[]any{
+                val[0].Date.AddDate(0, 0, s.timeFrameLimits.B),
+                val[len(val)-1].Date.AddDate(0, 0, s.timeFrameLimits.A),
+                val[0].ExerciseID,
+                val[0].ClientID,
+            }).Collect();
+            if err!=nil && err!=sql.ErrNoRows {
+                return dataStruct.Pair[[]db.ModelState,int]{B: len(val)},err;
+            }
+            res,failed,err:=s.rollTimeFrame(surfs,data,val);
+            return dataStruct.Pair[[]db.ModelState,int]{A: res, B: failed},err;
+        },func(val []*missingModelStateData,
+            res dataStruct.Pair[[]db.ModelState,int],
+            err error) {
+            for _,r:=range(res.A) {
+                bufCreator.Write(d,r);
+                SLIDING_WINDOW_MS_PARALLEL_RESULT_DEBUG.Log("Optimal MS",r);
+            }
+            rv.B+=res.B;
+        },s.allotedThreads,
+    );
+    bufCreator.Flush(d);
+    rv.A=bufCreator.Succeeded();
+    rv.B+=bufCreator.Failed();
+    return rv,err;
+}
+
+//Rolls the time frame forward through the missing dates, which must all be for
+//the same exercise and in ascending order. The data points must be in
+//ascending order and cover the time frames of every date. The model states
+//that were generated are returned along with the number of dates that a model
+//state could not be generated for.
+func (s *SlidingWindowStateGen)rollTimeFrame(
+        surfaces []potSurf.Surface,
+        data []*dataPoint,
+        missing []*missingModelStateData) ([]db.ModelState,int,error) {
+    rv:=[]db.ModelState{};
+    failed:=0;
+    for _,m:=range(surfaces) {
+        if _,ok:=m.(potSurf.RemovableSurface); !ok {
+            return rv,len(missing),SurfaceNotRemovable(fmt.Sprintf(
+                "Surface: %d",m.Id(),
+            ));
+        }
+    }
+    s.setInitialOptimalMsValues(missing[0],surfaces);
+    frame:=[]*dataPoint{};
+    next:=0;
+    for i,_:=range(missing) {
+        md:=missing[i];
+        for ; next<len(data) && !data[next].DatePerformed.After(
+            md.Date.AddDate(0, 0, s.timeFrameLimits.A),
+        ); next++ {
+            s.updateModel(data[next]);
+            frame=append(frame,data[next]);
+        }
+        start:=md.Date.AddDate(0, 0, s.timeFrameLimits.B);
+        for len(frame)>0 && !frame[0].DatePerformed.After(start) {
+            if err:=s.removeOldestFromModel(frame[0]); err!=nil {
+                return rv,failed+len(missing)-i,err;
+            }
+            frame=frame[1:];
+        }
+        s.resetOptimalMsValues(md);
+        s.setWithinWindowLimits(md.Date);
+        //The window values are in the same order as runAlgo creates them
+        for j:=len(frame)-1; j>=0; j-- {
+            if s.withinWindowLimits(frame[j].DatePerformed) {
+                s.updateWindowValues(frame[j]);
+            }
+        }
+        for _,w:=range(s.windowValues) {
+            for l,r:=0,len(w)-1; l<r; l,r=l+1,r-1 {
+                w[l],w[r]=w[r],w[l];
+            }
+        }
+        if len(s.windowValues)==0 {
+            failed++;
+            continue;
+        }
+        if err:=s.calcAndSetModelState(
+            &dataPoint{DatePerformed: start},md,
+        ); err!=nil {
+            failed++;
+            continue;
+        }
+        for j,ms:=range(s.optimalMs) {
+            if s.bestCandidates[j]!=nil {
+                rv=append(rv,ms);
+            }
+        }
+    }
+    return rv,failed,nil;
+}
@@ -2,6 +2,7 @@ package stateGenerator
 
 import (
 	"fmt"
+	stdMath "math"
 	"testing"
 	"time"
 
@@ -102,6 +103,28 @@ func TestGenerateModelStateScenario2(t *testing.T){
     );
 }
 
+func TestGenerateModelStateBanisterSurface(t *testing.T){
+    baseTime,_:=time.Parse("01/02/2006","09/10/2022");
+    timeFrame:=dataStruct.Pair[int,int]{A: 0, B: 500};
+    window:=dataStruct.Pair[int,int]{A: 0, B: 10};
+    sw,_:=NewSlidingWindowStateGen(timeFrame,window,1);
+    missingData:=missingModelStateData{
+        ClientID: 1,
+        ExerciseID: 15,
+        Date: baseTime,
+    };
+    ms,err:=sw.GenerateModelState(&testDB,[]potSurf.Surface{
+        potSurf.NewBanisterSurface().ToGenericSurf(),
+    },&missingData);
+    test.BasicTest(nil,err,
+        "Running the sliding window with the banister surface returned an error when it shouldn't have.",t,
+    );
+    test.BasicTest(1,len(ms),"No model state was generated.",t);
+    test.BasicTest(int(potSurf.BanisterSurfaceId),ms[0].PotentialSurfaceID,
+        "Model state had the wrong surface id.",t,
+    );
+}
+
 func generateModelStateHelper(scenarioName string,
         baseTime time.Time,
         timeFrame dataStruct.Pair[int,int],
@@ -393,3 +416,128 @@ func runAllModelStateDebugLogTests(t *testing.T){
         return iter.Continue,nil;
     });
 }
+
+func TestWithRankOneUpdates(t *testing.T){
+    sw,_:=NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 0, B: 1},dataStruct.Pair[int,int]{A: 0, B: 1},1,
+    );
+    _,err:=sw.WithRankOneUpdates(-1);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid refresh did not return an error.",t,
+        );
+    }
+    cached,err:=sw.WithRankOneUpdates(0);
+    test.BasicTest(nil,err,"Enabling rank one updates returned an error.",t);
+    test.BasicTest(false,sw.rankOneUpdates,
+        "Enabling rank one updates modified the original state generator.",t,
+    );
+    plainSurf,cachedSurf:=potSurf.NewBasicSurface(),potSurf.NewBasicSurface();
+    sw.setInitialOptimalMsValues(&missingModelStateData{},
+        []potSurf.Surface{&plainSurf},
+    );
+    cached.setInitialOptimalMsValues(&missingModelStateData{},
+        []potSurf.Surface{&cachedSurf},
+    );
+    for _,day:=range(leaveDayOutTestData()) {
+        for _,d:=range(day) {
+            sw.updateModel(&d);
+            cached.updateModel(&d);
+        }
+        plainRcond,plainErr:=plainSurf.Run();
+        cachedRcond,cachedErr:=cachedSurf.Run();
+        test.BasicTest(plainErr==nil,cachedErr==nil,
+            "Rank one updates changed whether the surface could be run.",t,
+        );
+        if plainErr!=nil {
+            continue;
+        }
+        test.BasicTest(true,stdMath.Abs(plainRcond-cachedRcond)<=1e-9,
+            "Rank one updates changed the rcond.",t,
+        );
+        for i:=0; i<plainSurf.NumConstants(); i++ {
+            test.BasicTest(true,stdMath.Abs(
+                plainSurf.GetConstant(i)-cachedSurf.GetConstant(i),
+            )<1e-9,"Rank one updates changed the surface constants.",t);
+        }
+    }
+}
+
+func rollingTestData() []*dataPoint {
+    rv:=[]*dataPoint{};
+    start:=time.Date(2022,time.Month(1),1,0,0,0,0,time.UTC);
+    for i:=0; i<120; i++ {
+        d:=dataPoint{
+            DatePerformed: start.AddDate(0,0,i/2),
+            Sets: float64(1+i%3),
+            Reps: float64(1+(i*7)%5),
+            Effort: float64(6+i%4),
+            InterExerciseFatigue: float64(i%2),
+            InterWorkoutFatigue: float64((i*5)%7),
+        };
+        d.Intensity=0.5+0.05*d.Effort-0.002*d.InterWorkoutFatigue-
+            0.004*d.InterExerciseFatigue-
+            0.01*(d.Sets-1)*(d.Sets-1)-0.02*(d.Reps-1)*(d.Reps-1)+
+            0.003*stdMath.Sin(float64(i));
+        rv=append(rv,&d);
+    }
+    return rv;
+}
+
+func TestRollTimeFrame(t *testing.T){
+    data:=rollingTestData();
+    missing:=[]*missingModelStateData{};
+    for i:=25; i<60; i+=3 {
+        missing=append(missing,&missingModelStateData{
+            ClientID: 1, ExerciseID: 1, Date: data[0].DatePerformed.AddDate(0,0,i),
+        });
+    }
+    sw,_:=NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 0, B: 20},dataStruct.Pair[int,int]{A: 0, B: 5},1,
+    );
+    sw,_=sw.WithRankOneUpdates(0);
+    sw=sw.WithRollingTimeFrame();
+    test.BasicTest(true,sw.rolling,"The rolling time frame was not set.",t);
+    res,failed,err:=sw.rollTimeFrame(
+        []potSurf.Surface{potSurf.NewBasicSurface().ToGenericSurf()},data,missing,
+    );
+    test.BasicTest(nil,err,"Rolling the time frame returned an error.",t);
+    test.BasicTest(0,failed,"A model state could not be generated.",t);
+    test.BasicTest(len(missing),len(res),"A model state was not generated.",t);
+    for i,ms:=range(res) {
+        test.BasicTest(missing[i].Date,ms.Date,"The model state had the wrong date.",t);
+        test.BasicTest(20,ms.TimeFrame,"The time frame was not the fixed length.",t);
+        //Fitting the time frame from scratch should give the same constants
+        exp:=potSurf.NewBasicSurface();
+        for _,d:=range(data) {
+            if d.DatePerformed.After(ms.Date.AddDate(0,0,-20)) &&
+                !d.DatePerformed.After(ms.Date) {
+                exp.Update(d.vars());
+            }
+        }
+        exp.Run();
+        for j,v:=range([]float64{ms.Eps,ms.Eps1,ms.Eps2,ms.Eps3,ms.Eps4,ms.Eps5,ms.Eps6}) {
+            test.BasicTest(true,stdMath.Abs(exp.GetConstant(j)-v)<1e-8,
+                "Rolling the time frame changed the surface constants.",t,
+            );
+        }
+    }
+}
+
+func TestRollTimeFrameNotRemovable(t *testing.T){
+    sw,_:=NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 0, B: 20},dataStruct.Pair[int,int]{A: 0, B: 5},1,
+    );
+    sw=sw.WithRollingTimeFrame();
+    s:=potSurf.NewNonlinearVolumeBaseSurface();
+    _,failed,err:=sw.rollTimeFrame(
+        []potSurf.Surface{s.ToGenericSurf()},rollingTestData(),
+        []*missingModelStateData{{ClientID: 1, ExerciseID: 1}},
+    );
+    if !IsSurfaceNotRemovable(err) {
+        test.FormatError(SurfaceNotRemovable(""),err,
+            "A surface that can not remove samples did not return an error.",t,
+        );
+    }
+    test.BasicTest(1,failed,"The date was not counted as failed.",t);
+}
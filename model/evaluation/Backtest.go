@@ -0,0 +1,170 @@
+package evaluation
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model"
+	"github.com/barbell-math/engine/util/algo/iter"
+	"github.com/barbell-math/engine/util/dataStruct"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The results of a backtest for a single state generator and surface. Reports
+//from different state generators or surfaces can be written to the same CSV
+//file to compare them.
+type Report struct {
+    StateGeneratorID int;
+    PotentialSurfaceID int;
+    Start time.Time;
+    End time.Time;
+    Tolerance float64;
+    Overall Metrics;
+    ByClientExercise []Metrics;
+};
+
+//Walks forward in time through every training log in the date range (inclusive)
+//for each client and generates a prediction for it. Before walking through a
+//client the state generator creates any of the clients model states that are
+//missing using the surfaces from the surface factory. A model state only uses
+//the training logs on or before its date, so generating them up front gives
+//the same model states as generating them one date at a time. The backtest
+//itself is done by BacktestModelStates with the state generators id.
+func Backtest(
+        d *db.DB,
+        clients []db.Client,
+        start time.Time,
+        end time.Time,
+        sg stateGen.StateGenerator,
+        surfaceFactory func() []potSurf.Surface,
+        surf potSurf.PotentialSurfaceId,
+        tolerance float64) (Report,error) {
+    if sg==nil {
+        return Report{},customerr.InvalidValue("no state generator was supplied");
+    } else if err:=validBacktest(clients,start,end,surf,tolerance); err!=nil {
+        return Report{},err;
+    }
+    for _,c:=range(clients) {
+        if _,err:=sg.GenerateClientModelStates(
+            d,c,time.Time{},surfaceFactory,
+        ); err!=nil {
+            return Report{},err;
+        }
+    }
+    return BacktestModelStates(d,clients,start,end,sg.Id(),surf,tolerance);
+}
+
+//The same as Backtest except that the model states are expected to already
+//exist. Predictions are made with the closest model state that is strictly
+//before the training logs date and is not stale, so every prediction is out of
+//sample. Training logs that have no model state before them are counted as
+//skipped. Training logs that the surface has no solution for are counted as
+//failed and the backtest continues, any other error stops the backtest. A
+//prediction is considered a hit if it is within the tolerance of the actual
+//intensity.
+func BacktestModelStates(
+        d *db.DB,
+        clients []db.Client,
+        start time.Time,
+        end time.Time,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        tolerance float64) (Report,error) {
+    rv:=Report{
+        StateGeneratorID: int(sg),
+        PotentialSurfaceID: int(surf),
+        Start: start,
+        End: end,
+        Tolerance: tolerance,
+    };
+    if err:=validBacktest(clients,start,end,surf,tolerance); err!=nil {
+        return rv,err;
+    }
+    groups:=map[dataStruct.Pair[int,int]]*metricsAccumulator{};
+    for _,c:=range(clients) {
+        if err:=backtestClient(d,&c,start,end,sg,surf,groups); err!=nil {
+            return rv,err;
+        }
+    }
+    var overall metricsAccumulator;
+    for k,v:=range(groups) {
+        overall.merge(v);
+        m:=v.metrics(tolerance);
+        m.ClientID=k.A;
+        m.ExerciseID=k.B;
+        rv.ByClientExercise=append(rv.ByClientExercise,m);
+    }
+    sort.Slice(rv.ByClientExercise,func(i int, j int) bool {
+        if rv.ByClientExercise[i].ClientID==rv.ByClientExercise[j].ClientID {
+            return rv.ByClientExercise[i].ExerciseID<rv.ByClientExercise[j].ExerciseID;
+        }
+        return rv.ByClientExercise[i].ClientID<rv.ByClientExercise[j].ClientID;
+    });
+    rv.Overall=overall.metrics(tolerance);
+    rv.setIds();
+    return rv,nil;
+}
+
+func validBacktest(
+        clients []db.Client,
+        start time.Time,
+        end time.Time,
+        surf potSurf.PotentialSurfaceId,
+        tolerance float64) error {
+    if len(clients)==0 {
+        return NoClientsSupplied("");
+    } else if end.Before(start) {
+        return customerr.InvalidValue("end date is before start date");
+    } else if tolerance<0 {
+        return customerr.InvalidValue("tolerance < 0, should be >=0");
+    }
+    _,err:=potSurf.Lookup(surf);
+    return err;
+}
+
+func backtestClient(
+        d *db.DB,
+        c *db.Client,
+        start time.Time,
+        end time.Time,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        groups map[dataStruct.Pair[int,int]]*metricsAccumulator) error {
+    err:=db.CustomReadQuery[db.TrainingLog](d,trainingLogsInRangeQuery(),[]any{
+        c.Id,start,end,
+    }).ForEach(func(index int, val *db.TrainingLog) (iter.IteratorFeedback, error) {
+        key:=dataStruct.Pair[int,int]{A: val.ClientID, B: val.ExerciseID};
+        if _,ok:=groups[key]; !ok {
+            groups[key]=&metricsAccumulator{};
+        }
+        pred,err:=model.GeneratePrediction(d,val,sg,surf);
+        if err==sql.ErrNoRows {
+            groups[key].skip();
+            return iter.Continue,nil;
+        } else if potSurf.IsNoSolution(err) {
+            groups[key].fail();
+            return iter.Continue,nil;
+        } else if err!=nil {
+            return iter.Break,err;
+        }
+        groups[key].add(val.Intensity,pred.Val);
+        return iter.Continue,nil;
+    });
+    if err==sql.ErrNoRows {
+        return nil;
+    }
+    return err;
+}
+
+func (r *Report)setIds(){
+    r.Overall.StateGeneratorID=r.StateGeneratorID;
+    r.Overall.PotentialSurfaceID=r.PotentialSurfaceID;
+    for i,_:=range(r.ByClientExercise) {
+        r.ByClientExercise[i].StateGeneratorID=r.StateGeneratorID;
+        r.ByClientExercise[i].PotentialSurfaceID=r.PotentialSurfaceID;
+    }
+}
@@ -0,0 +1,95 @@
+package evaluation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func basicSurfaceFactory() []potSurf.Surface {
+    return []potSurf.Surface{potSurf.NewBasicSurface().ToGenericSurf()};
+}
+
+func testStateGen() stateGen.StateGenerator {
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    return &sw;
+}
+
+func TestBacktestNoClients(t *testing.T){
+    _,err:=Backtest(&testDB,[]db.Client{},time.Now(),time.Now(),
+        testStateGen(),basicSurfaceFactory,potSurf.BasicSurfaceId,0.05,
+    );
+    if !IsNoClientsSupplied(err) {
+        test.FormatError(NoClientsSupplied(""),err,
+            "Running a backtest with no clients did not return the correct error.",t,
+        );
+    }
+}
+
+func TestBacktestInvalidDateRange(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    _,err:=Backtest(&testDB,[]db.Client{c},time.Now(),time.Now().AddDate(0,0,-1),
+        testStateGen(),basicSurfaceFactory,potSurf.BasicSurfaceId,0.05,
+    );
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Running a backtest with an invalid date range did not return the correct error.",t,
+        );
+    }
+}
+
+func TestBacktestNoStateGenerator(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    _,err:=Backtest(&testDB,[]db.Client{c},time.Now(),time.Now(),
+        nil,basicSurfaceFactory,potSurf.BasicSurfaceId,0.05,
+    );
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Running a backtest without a state generator did not return the correct error.",t,
+        );
+    }
+}
+
+func TestBacktest(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    start:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    end:=time.Date(2022,time.Month(9),10,0,0,0,0,time.UTC);
+    res,err:=Backtest(&testDB,[]db.Client{c},start,end,
+        testStateGen(),basicSurfaceFactory,potSurf.BasicSurfaceId,0.05,
+    );
+    test.BasicTest(nil,err,"Running a backtest returned an error.",t);
+    test.BasicTest(int(stateGen.SlidingWindowStateGenId),res.StateGeneratorID,
+        "The report had the wrong state generator id.",t,
+    );
+    test.BasicTest(true,len(res.ByClientExercise)>0,
+        "Backtest did not generate metrics for any exercises.",t,
+    );
+    test.BasicTest(true,res.Overall.NumPredictions>0,
+        "Backtest did not generate model states to predict with.",t,
+    );
+    total:=0;
+    for _,m:=range(res.ByClientExercise) {
+        test.BasicTest(c.Id,m.ClientID,"Metrics had the wrong client id.",t);
+        test.BasicTest(int(potSurf.BasicSurfaceId),m.PotentialSurfaceID,
+            "Metrics had the wrong surface id.",t,
+        );
+        total+=m.NumPredictions+m.NumSkipped+m.NumFailed;
+    }
+    test.BasicTest(total,res.Overall.NumPredictions+res.Overall.NumSkipped+
+        res.Overall.NumFailed,
+        "Overall metrics do not account for every training log.",t,
+    );
+    test.BasicTest(true,strings.Contains(res.String(),"All"),
+        "Text report is missing the overall row.",t,
+    );
+}
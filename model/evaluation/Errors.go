@@ -0,0 +1,9 @@
+package evaluation;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var NoClientsSupplied,IsNoClientsSupplied=customerr.ErrorFactory(
+    "At least one client is needed to run a backtest.",
+);
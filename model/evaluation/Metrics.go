@@ -0,0 +1,109 @@
+package evaluation
+
+import (
+	stdMath "math"
+
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+)
+
+//The accuracy metrics for a group of predictions. A client or exercise id of
+//zero means the metrics are aggregated over all clients or exercises.
+//Calibration is found by regressing the actual intensity on the predicted
+//intensity (actual=slope*pred+intercept). A well calibrated model will have a
+//slope close to 1 and an intercept close to 0. If there are not enough points
+//to run the regression the calibration values are NaN.
+//Note - the field order is the column order in the CSV report.
+type Metrics struct {
+    StateGeneratorID int;
+    PotentialSurfaceID int;
+    ClientID int;
+    ExerciseID int;
+    NumPredictions int;
+    NumSkipped int;
+    NumFailed int;
+    Mae float64;
+    Rmse float64;
+    Bias float64;
+    HitRate float64;
+    CalibrationSlope float64;
+    CalibrationIntercept float64;
+};
+
+//Accumulates actual and predicted values so metrics can be calculated once all
+//predictions have been generated.
+type metricsAccumulator struct {
+    actual []float64;
+    pred []float64;
+    skipped int;
+    failed int;
+};
+
+func (m *metricsAccumulator)add(actual float64, pred float64){
+    m.actual=append(m.actual,actual);
+    m.pred=append(m.pred,pred);
+}
+
+func (m *metricsAccumulator)skip(){
+    m.skipped++;
+}
+
+func (m *metricsAccumulator)fail(){
+    m.failed++;
+}
+
+func (m *metricsAccumulator)merge(other *metricsAccumulator){
+    m.actual=append(m.actual,other.actual...);
+    m.pred=append(m.pred,other.pred...);
+    m.skipped+=other.skipped;
+    m.failed+=other.failed;
+}
+
+func (m *metricsAccumulator)metrics(tolerance float64) Metrics {
+    rv:=Metrics{
+        NumPredictions: len(m.actual),
+        NumSkipped: m.skipped,
+        NumFailed: m.failed,
+        CalibrationSlope: stdMath.NaN(),
+        CalibrationIntercept: stdMath.NaN(),
+    };
+    if len(m.actual)==0 {
+        rv.Mae,rv.Rmse,rv.Bias,rv.HitRate=
+            stdMath.NaN(),stdMath.NaN(),stdMath.NaN(),stdMath.NaN();
+        return rv;
+    }
+    hits:=0;
+    for i,a:=range(m.actual) {
+        diff:=m.pred[i]-a;
+        rv.Mae+=mathUtil.Abs(diff);
+        rv.Bias+=diff;
+        if mathUtil.Abs(diff)<=tolerance {
+            hits++;
+        }
+    }
+    n:=float64(len(m.actual));
+    mse,_:=mathUtil.MeanSqErr(m.actual,m.pred);
+    rv.Mae/=n;
+    rv.Bias/=n;
+    rv.Rmse=stdMath.Sqrt(mse);
+    rv.HitRate=float64(hits)/n;
+    rv.CalibrationSlope,rv.CalibrationIntercept=m.calibration();
+    return rv;
+}
+
+func (m *metricsAccumulator)calibration() (float64,float64) {
+    if len(m.actual)<2 {
+        return stdMath.NaN(),stdMath.NaN();
+    }
+    lr:=mathUtil.NewLinearReg[float64]([]mathUtil.SummationOp[float64]{
+        mathUtil.LinearSummationOp[float64]("P"),
+        mathUtil.ConstSummationOp[float64](1),
+    },mathUtil.LinearSummationOp[float64]("A"));
+    for i,a:=range(m.actual) {
+        lr.UpdateSummations(map[string]float64{"P": m.pred[i], "A": a});
+    }
+    res,_,err:=lr.Run();
+    if err!=nil {
+        return stdMath.NaN(),stdMath.NaN();
+    }
+    return res.GetConstant(0),res.GetConstant(1);
+}
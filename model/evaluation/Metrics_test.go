@@ -0,0 +1,69 @@
+package evaluation
+
+import (
+	stdMath "math"
+	"testing"
+
+	mathUtil "github.com/barbell-math/engine/util/math/numeric"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestMetricsNoPredictions(t *testing.T){
+    var m metricsAccumulator;
+    m.skip();
+    res:=m.metrics(0.05);
+    test.BasicTest(0,res.NumPredictions,"Wrong number of predictions.",t);
+    test.BasicTest(1,res.NumSkipped,"Wrong number of skipped predictions.",t);
+    test.BasicTest(true,stdMath.IsNaN(res.Mae),"MAE should be NaN.",t);
+    test.BasicTest(true,stdMath.IsNaN(res.CalibrationSlope),
+        "Calibration should be NaN.",t,
+    );
+}
+
+func TestMetrics(t *testing.T){
+    var m metricsAccumulator;
+    m.add(1.0,0.9);
+    m.add(0.8,0.9);
+    m.add(0.7,0.5);
+    m.add(0.6,0.6);
+    res:=m.metrics(0.1);
+    test.BasicTest(4,res.NumPredictions,"Wrong number of predictions.",t);
+    if mathUtil.Abs(res.Mae-0.1)>1e-12 {
+        test.FormatError(0.1,res.Mae,"MAE calculated incorrectly.",t);
+    }
+    if mathUtil.Abs(res.Rmse-stdMath.Sqrt(0.015))>1e-12 {
+        test.FormatError(stdMath.Sqrt(0.015),res.Rmse,"RMSE calculated incorrectly.",t);
+    }
+    if mathUtil.Abs(res.Bias+0.05)>1e-12 {
+        test.FormatError(-0.05,res.Bias,"Bias calculated incorrectly.",t);
+    }
+    test.BasicTest(0.75,res.HitRate,"Hit rate calculated incorrectly.",t);
+}
+
+func TestMetricsPerfectCalibration(t *testing.T){
+    var m metricsAccumulator;
+    for i:=0; i<10; i++ {
+        m.add(float64(i)/10,float64(i)/10);
+    }
+    res:=m.metrics(0);
+    if mathUtil.Abs(res.CalibrationSlope-1)>1e-9 ||
+        mathUtil.Abs(res.CalibrationIntercept)>1e-9 {
+        test.FormatError("1,0",res,"Calibration calculated incorrectly.",t);
+    }
+    test.BasicTest(float64(1),res.HitRate,"Hit rate calculated incorrectly.",t);
+}
+
+func TestMetricsMerge(t *testing.T){
+    var m1,m2 metricsAccumulator;
+    m1.add(1,1);
+    m1.skip();
+    m2.add(1,0);
+    m2.skip();
+    m2.fail();
+    m1.merge(&m2);
+    res:=m1.metrics(0);
+    test.BasicTest(2,res.NumPredictions,"Wrong number of predictions.",t);
+    test.BasicTest(2,res.NumSkipped,"Wrong number of skipped predictions.",t);
+    test.BasicTest(1,res.NumFailed,"Wrong number of failed predictions.",t);
+    test.BasicTest(0.5,res.HitRate,"Hit rate calculated incorrectly.",t);
+}
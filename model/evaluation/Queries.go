@@ -0,0 +1,13 @@
+package evaluation
+
+func trainingLogsInRangeQuery() string {
+    return `SELECT *
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.DatePerformed>=$2
+            AND TrainingLog.DatePerformed<=$3
+            AND TrainingLog.Intensity>0
+        ORDER BY
+            DatePerformed ASC,
+            Id ASC;`;
+}
@@ -0,0 +1,58 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/barbell-math/engine/util/algo/iter"
+	"github.com/barbell-math/engine/util/io/csv"
+)
+
+//Renders the report as a human readable table. The overall metrics are the
+//last row of the table.
+func (r Report)String() string {
+    var sb strings.Builder;
+    sb.WriteString(fmt.Sprintf(
+        "State Generator: %d Potential Surface: %d Range: %s-%s Tolerance: %v\n",
+        r.StateGeneratorID,r.PotentialSurfaceID,
+        r.Start.Format("01/02/2006"),r.End.Format("01/02/2006"),r.Tolerance,
+    ));
+    w:=tabwriter.NewWriter(&sb,0,0,2,' ',tabwriter.AlignRight);
+    fmt.Fprintln(w,"Client\tExercise\tN\tSkipped\tFailed\tMAE\tRMSE\tBias\tHit Rate\tCal Slope\tCal Intercept\t");
+    for _,m:=range(r.ByClientExercise) {
+        writeMetricsRow(w,fmt.Sprint(m.ClientID),fmt.Sprint(m.ExerciseID),&m);
+    }
+    writeMetricsRow(w,"All","All",&r.Overall);
+    w.Flush();
+    return sb.String();
+}
+
+func writeMetricsRow(w *tabwriter.Writer, client string, exercise string, m *Metrics){
+    fmt.Fprintf(w,"%s\t%s\t%d\t%d\t%d\t%.4f\t%.4f\t%.4f\t%.2f\t%.4f\t%.4f\t\n",
+        client,exercise,m.NumPredictions,m.NumSkipped,m.NumFailed,
+        m.Mae,m.Rmse,m.Bias,m.HitRate,
+        m.CalibrationSlope,m.CalibrationIntercept,
+    );
+}
+
+//Returns all of the metrics in the report, with the overall metrics last.
+func (r Report)Rows() []Metrics {
+    rv:=make([]Metrics,0,len(r.ByClientExercise)+1);
+    rv=append(rv,r.ByClientExercise...);
+    return append(rv,r.Overall);
+}
+
+//Writes the metrics from all of the supplied reports to a single CSV file.
+//Every row has the state generator and surface id so that reports can be
+//compared against each other. Rows with a client and exercise id of 0 are the
+//overall metrics for a report.
+func ReportsToCSV(file string, reports ...Report) error {
+    rows:=make([]Metrics,0);
+    for _,r:=range(reports) {
+        rows=append(rows,r.Rows()...);
+    }
+    return csv.Flatten(csv.StructToCSV(
+        iter.SliceElems(rows),true,"01/02/2006",
+    ),",").ToFile(file,true);
+}
@@ -0,0 +1,9 @@
+package load;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var InvalidMeasure,IsInvalidMeasure=customerr.ErrorFactory(
+    "The measure is not a recognized training load measure.",
+);
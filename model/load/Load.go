@@ -0,0 +1,142 @@
+package load
+
+import (
+	"database/sql"
+	"fmt"
+	stdMath "math"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The ways the load of a training log can be measured.
+//  - Tonnage: weight*sets*reps
+//  - HardSets: the number of sets performed at or above the hard set effort
+//  - INOL: reps/(100-intensity), summed over every set, where intensity is a
+//    percentage of the clients max. Intensities above 99% are treated as 99%.
+//  - SessionRPE: effort*sets. The training log does not record the duration
+//    of a session so the number of sets is used in its place.
+type Measure int;
+const (
+    Tonnage Measure=iota
+    HardSets
+    INOL
+    SessionRPE
+);
+
+func (m Measure)String() string {
+    switch m {
+        case Tonnage: return "Tonnage";
+        case HardSets: return "HardSets";
+        case INOL: return "INOL";
+        case SessionRPE: return "SessionRPE";
+        default: return "unknown";
+    }
+}
+
+//The load of every training log performed on a single day, in every measure.
+type DailyLoad struct {
+    Date time.Time;
+    Tonnage float64;
+    HardSets float64;
+    INOL float64;
+    SessionRPE float64;
+};
+
+//Returns the load in the given measure.
+func (d DailyLoad)Get(m Measure) (float64,error) {
+    switch m {
+        case Tonnage: return d.Tonnage,nil;
+        case HardSets: return d.HardSets,nil;
+        case INOL: return d.INOL,nil;
+        case SessionRPE: return d.SessionRPE,nil;
+        default: return 0,InvalidMeasure(fmt.Sprintf("Measure: %d",m));
+    }
+}
+
+func (d *DailyLoad)add(tl *db.TrainingLog, hardSetEffort float64) {
+    d.Tonnage+=tl.Weight*tl.Sets*tl.Reps;
+    if tl.Effort>=hardSetEffort {
+        d.HardSets+=tl.Sets;
+    }
+    if tl.Intensity>0 {
+        d.INOL+=tl.Sets*tl.Reps/(100-100*stdMath.Min(tl.Intensity,0.99));
+    }
+    d.SessionRPE+=tl.Effort*tl.Sets;
+}
+
+func (d *DailyLoad)merge(other DailyLoad) {
+    d.Tonnage+=other.Tonnage;
+    d.HardSets+=other.HardSets;
+    d.INOL+=other.INOL;
+    d.SessionRPE+=other.SessionRPE;
+}
+
+//Returns the load of the client for every day between the start and end dates
+//(inclusive). Days without any training are included with a load of zero so
+//that the series can be used to calculate rolling values.
+func DailyLoads(
+        d *db.DB,
+        c *db.Client,
+        start time.Time,
+        end time.Time,
+        o Options) ([]DailyLoad,error) {
+    o.fillDefaults();
+    if err:=o.validate(); err!=nil {
+        return []DailyLoad{},err;
+    } else if end.Before(start) {
+        return []DailyLoad{},customerr.InvalidValue(
+            "end date is before start date",
+        );
+    }
+    logs,err:=db.CustomReadQuery[db.TrainingLog](d,
+        trainingLogsBetweenDatesQuery(),[]any{c.Id,start,end},
+    ).Collect();
+    if err!=nil && err!=sql.ErrNoRows {
+        return []DailyLoad{},err;
+    }
+    return dailyLoads(logs,start,end,o),nil;
+}
+
+func dailyLoads(
+        logs []*db.TrainingLog,
+        start time.Time,
+        end time.Time,
+        o Options) []DailyLoad {
+    start,end=day(start),day(end);
+    rv:=make([]DailyLoad,int(end.Sub(start).Hours()/24)+1);
+    for i,_:=range(rv) {
+        rv[i].Date=start.AddDate(0,0,i);
+    }
+    for _,tl:=range(logs) {
+        i:=int(day(tl.DatePerformed).Sub(start).Hours()/24);
+        if i>=0 && i<len(rv) && o.includes(tl.ExerciseID) {
+            rv[i].add(tl,o.HardSetEffort);
+        }
+    }
+    return rv;
+}
+
+//Sums the daily loads into weeks. Weeks start on monday and the date of each
+//returned load is the monday the week starts on. The first and last weeks only
+//include the days that are in the daily loads.
+func WeeklyLoads(daily []DailyLoad) []DailyLoad {
+    rv:=[]DailyLoad{};
+    for _,v:=range(daily) {
+        s:=weekStart(v.Date);
+        if len(rv)==0 || !rv[len(rv)-1].Date.Equal(s) {
+            rv=append(rv,DailyLoad{Date: s});
+        }
+        rv[len(rv)-1].merge(v);
+    }
+    return rv;
+}
+
+func day(t time.Time) time.Time {
+    return time.Date(t.Year(),t.Month(),t.Day(),0,0,0,0,time.UTC);
+}
+
+func weekStart(t time.Time) time.Time {
+    return day(t).AddDate(0,0,-(int(t.Weekday())+6)%7);
+}
@@ -0,0 +1,112 @@
+package load
+
+import (
+	stdMath "math"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func testDate(d int) time.Time {
+    return time.Date(2023,time.Month(1),d,0,0,0,0,time.UTC);
+}
+
+func TestDailyLoadGet(t *testing.T){
+    d:=DailyLoad{Tonnage: 1, HardSets: 2, INOL: 3, SessionRPE: 4};
+    for i,m:=range([]Measure{Tonnage,HardSets,INOL,SessionRPE}) {
+        v,err:=d.Get(m);
+        test.BasicTest(nil,err,"Getting a measure returned an error.",t);
+        test.BasicTest(float64(i+1),v,"The wrong measure was returned.",t);
+    }
+    if _,err:=d.Get(Measure(-1)); !IsInvalidMeasure(err) {
+        test.FormatError(InvalidMeasure(""),err,
+            "Getting an invalid measure did not return the correct error.",t,
+        );
+    }
+}
+
+func TestDailyLoads(t *testing.T){
+    logs:=[]*db.TrainingLog{
+        {ExerciseID: 1, DatePerformed: testDate(2), Weight: 100,
+            Sets: 3, Reps: 5, Intensity: 0.8, Effort: 8},
+        {ExerciseID: 2, DatePerformed: testDate(2), Weight: 50,
+            Sets: 2, Reps: 10, Intensity: 0.6, Effort: 6},
+        {ExerciseID: 1, DatePerformed: testDate(4), Weight: 200,
+            Sets: 1, Reps: 1, Intensity: 1.0, Effort: 10},
+        {ExerciseID: 1, DatePerformed: testDate(9), Weight: 100,
+            Sets: 1, Reps: 1, Intensity: 0.5, Effort: 5},
+    };
+    o:=Options{};
+    o.fillDefaults();
+    res:=dailyLoads(logs,testDate(1),testDate(5),o);
+    test.BasicTest(5,len(res),"Rest days were not included.",t);
+    test.BasicTest(true,res[0].Date.Equal(testDate(1)),"The first date was wrong.",t);
+    test.BasicTest(0.0,res[0].Tonnage,"A rest day had a load.",t);
+    test.BasicTest(2500.0,res[1].Tonnage,"The tonnage was not correct.",t);
+    test.BasicTest(3.0,res[1].HardSets,"The hard sets were not correct.",t);
+    test.BasicTest(true,stdMath.Abs(res[1].INOL-(15.0/20.0+20.0/40.0))<1e-9,
+        "The INOL was not correct.",t,
+    );
+    test.BasicTest(36.0,res[1].SessionRPE,"The session RPE was not correct.",t);
+    test.BasicTest(true,stdMath.Abs(res[3].INOL-1)<1e-9,
+        "The intensity was not capped when calculating INOL.",t,
+    );
+    o.ExerciseIDs=[]int{2};
+    res=dailyLoads(logs,testDate(1),testDate(5),o);
+    test.BasicTest(1000.0,res[1].Tonnage,"The exercises were not filtered.",t);
+    test.BasicTest(0.0,res[3].Tonnage,"The exercises were not filtered.",t);
+}
+
+func TestWeeklyLoads(t *testing.T){
+    daily:=make([]DailyLoad,10);
+    for i,_:=range(daily) {
+        //2023-01-01 is a sunday
+        daily[i]=DailyLoad{Date: testDate(i+1), Tonnage: 1, HardSets: 1};
+    }
+    res:=WeeklyLoads(daily);
+    test.BasicTest(3,len(res),"The wrong number of weeks was returned.",t);
+    test.BasicTest(true,res[0].Date.Equal(time.Date(2022,time.Month(12),26,0,0,0,0,time.UTC)),
+        "The first week did not start on a monday.",t,
+    );
+    test.BasicTest(1.0,res[0].Tonnage,"The first week was not summed correctly.",t);
+    test.BasicTest(true,res[1].Date.Equal(testDate(2)),
+        "The second week did not start on a monday.",t,
+    );
+    test.BasicTest(7.0,res[1].Tonnage,"The second week was not summed correctly.",t);
+    test.BasicTest(2.0,res[2].HardSets,"The last week was not summed correctly.",t);
+}
+
+func TestDailyLoadsInvalidOptions(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    _,err:=DailyLoads(&testDB,&c,testDate(1),testDate(2),
+        Options{AcuteDays: 7, ChronicDays: 7},
+    );
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A chronic window that was not longer than the acute window did not error.",t,
+        );
+    }
+    _,err=DailyLoads(&testDB,&c,testDate(2),testDate(1),Options{});
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "An invalid date range did not return the correct error.",t,
+        );
+    }
+}
+
+func TestDailyLoadsFromDB(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    start:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    end:=time.Date(2022,time.Month(9),10,0,0,0,0,time.UTC);
+    res,err:=DailyLoads(&testDB,&c,start,end,Options{});
+    test.BasicTest(nil,err,"Getting the daily loads returned an error.",t);
+    test.BasicTest(41,len(res),"Not every day was returned.",t);
+    total:=0.0;
+    for _,v:=range(res) {
+        total+=v.Tonnage;
+    }
+    test.BasicTest(true,total>0,"The client had no training load.",t);
+}
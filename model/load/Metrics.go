@@ -0,0 +1,212 @@
+package load
+
+import (
+	stdMath "math"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+)
+
+//The kinds of warnings that can be raised by a series.
+type WarningKind int;
+const (
+    AcwrRollingHigh WarningKind=iota
+    AcwrRollingLow
+    AcwrEwmaHigh
+    AcwrEwmaLow
+    MonotonyHigh
+    StrainHigh
+);
+
+func (w WarningKind)String() string {
+    switch w {
+        case AcwrRollingHigh: return "AcwrRollingHigh";
+        case AcwrRollingLow: return "AcwrRollingLow";
+        case AcwrEwmaHigh: return "AcwrEwmaHigh";
+        case AcwrEwmaLow: return "AcwrEwmaLow";
+        case MonotonyHigh: return "MonotonyHigh";
+        case StrainHigh: return "StrainHigh";
+        default: return "unknown";
+    }
+}
+
+//A value that crossed one of the thresholds on the given date.
+type Warning struct {
+    Date time.Time;
+    Kind WarningKind;
+    Value float64;
+    Threshold float64;
+};
+
+//The load metrics of a single day.
+//  - Load: the load of the day
+//  - Acute and Chronic: the mean daily load of the acute and chronic windows
+//  - AcwrRolling: Acute/Chronic
+//  - AcuteEwma and ChronicEwma: the exponentially weighted moving averages of
+//    the daily load, with a decay of 2/(N+1) where N is the windows length
+//  - AcwrEwma: AcuteEwma/ChronicEwma
+//  - Monotony: the mean daily load of the acute window divided by its standard
+//    deviation
+//  - Strain: the total load of the acute window multiplied by the monotony
+//Values that can not be calculated, either because there is not a full window
+//of days before the date or because they would divide by zero, are NaN.
+type Point struct {
+    Date time.Time;
+    Load float64;
+    Acute float64;
+    Chronic float64;
+    AcwrRolling float64;
+    AcuteEwma float64;
+    ChronicEwma float64;
+    AcwrEwma float64;
+    Monotony float64;
+    Strain float64;
+};
+
+//The load metrics of every day in a range along with the warnings that were
+//raised. The points and warnings are in date order.
+type Series struct {
+    Measure Measure;
+    Points []Point;
+    Warnings []Warning;
+};
+
+//Calculates the load monitoring metrics of the client for every day between
+//the start and end dates (inclusive). Training logs from before the start date
+//are used so that the windows of the first day are full.
+func Monitor(
+        d *db.DB,
+        c *db.Client,
+        m Measure,
+        start time.Time,
+        end time.Time,
+        o Options) (Series,error) {
+    o.fillDefaults();
+    daily,err:=DailyLoads(d,c,start.AddDate(0,0,-(o.ChronicDays-1)),end,o);
+    if err!=nil {
+        return Series{Measure: m},err;
+    }
+    rv,err:=NewSeries(daily,m,o);
+    rv.trim(day(start));
+    return rv,err;
+}
+
+//Calculates the load monitoring metrics from a list of daily loads. The daily
+//loads are expected to be consecutive days, as returned by DailyLoads.
+func NewSeries(daily []DailyLoad, m Measure, o Options) (Series,error) {
+    rv:=Series{
+        Measure: m,
+        Points: make([]Point,len(daily)),
+        Warnings: []Warning{},
+    };
+    o.fillDefaults();
+    if err:=o.validate(); err!=nil {
+        return rv,err;
+    }
+    loads:=make([]float64,len(daily));
+    for i,v:=range(daily) {
+        var err error;
+        if loads[i],err=v.Get(m); err!=nil {
+            return rv,err;
+        }
+    }
+    acuteDecay:=2/float64(o.AcuteDays+1);
+    chronicDecay:=2/float64(o.ChronicDays+1);
+    for i,v:=range(loads) {
+        p:=&rv.Points[i];
+        p.Date=daily[i].Date;
+        p.Load=v;
+        p.AcuteEwma,p.ChronicEwma=v,v;
+        if i>0 {
+            p.AcuteEwma=ewma(rv.Points[i-1].AcuteEwma,v,acuteDecay);
+            p.ChronicEwma=ewma(rv.Points[i-1].ChronicEwma,v,chronicDecay);
+        }
+        p.AcwrEwma=ratio(p.AcuteEwma,p.ChronicEwma);
+        p.Acute,p.Monotony,p.Strain=stdMath.NaN(),stdMath.NaN(),stdMath.NaN();
+        if i+1>=o.AcuteDays {
+            window:=loads[i+1-o.AcuteDays:i+1];
+            mean,sd:=meanStdDev(window);
+            p.Acute=mean;
+            p.Monotony=ratio(mean,sd);
+            p.Strain=mean*float64(len(window))*p.Monotony;
+        }
+        p.Chronic,p.AcwrRolling=stdMath.NaN(),stdMath.NaN();
+        if i+1>=o.ChronicDays {
+            p.Chronic,_=meanStdDev(loads[i+1-o.ChronicDays:i+1]);
+            p.AcwrRolling=ratio(p.Acute,p.Chronic);
+            //The ewma is seeded with the first day so it is only comparable
+            //to the thresholds once a full chronic window has been seen.
+            rv.checkAcwr(p.Date,p.AcwrRolling,AcwrRollingHigh,AcwrRollingLow,o);
+            rv.checkAcwr(p.Date,p.AcwrEwma,AcwrEwmaHigh,AcwrEwmaLow,o);
+        }
+        rv.checkHigh(p.Date,p.Monotony,MonotonyHigh,o.Thresholds.MonotonyHigh);
+        rv.checkHigh(p.Date,p.Strain,StrainHigh,o.Thresholds.StrainHigh);
+    }
+    return rv,nil;
+}
+
+func (s *Series)checkAcwr(
+        date time.Time,
+        acwr float64,
+        high WarningKind,
+        low WarningKind,
+        o Options) {
+    s.checkHigh(date,acwr,high,o.Thresholds.AcwrHigh);
+    if o.Thresholds.AcwrLow>0 && acwr<o.Thresholds.AcwrLow {
+        s.Warnings=append(s.Warnings,Warning{
+            Date: date, Kind: low, Value: acwr, Threshold: o.Thresholds.AcwrLow,
+        });
+    }
+}
+
+//NaN values are never greater than the threshold so they do not raise
+//warnings.
+func (s *Series)checkHigh(
+        date time.Time,
+        val float64,
+        kind WarningKind,
+        threshold float64) {
+    if threshold>0 && val>threshold {
+        s.Warnings=append(s.Warnings,Warning{
+            Date: date, Kind: kind, Value: val, Threshold: threshold,
+        });
+    }
+}
+
+//Removes the points and warnings that are before the given date.
+func (s *Series)trim(start time.Time) {
+    i:=0;
+    for i<len(s.Points) && s.Points[i].Date.Before(start) {
+        i++;
+    }
+    s.Points=s.Points[i:];
+    j:=0;
+    for j<len(s.Warnings) && s.Warnings[j].Date.Before(start) {
+        j++;
+    }
+    s.Warnings=s.Warnings[j:];
+}
+
+func ewma(prev float64, cur float64, decay float64) float64 {
+    return decay*cur+(1-decay)*prev;
+}
+
+func ratio(num float64, denom float64) float64 {
+    if denom==0 {
+        return stdMath.NaN();
+    }
+    return num/denom;
+}
+
+//Returns the mean and the population standard deviation of the values.
+func meanStdDev(vals []float64) (float64,float64) {
+    mean,sd:=0.0,0.0;
+    for _,v:=range(vals) {
+        mean+=v;
+    }
+    mean/=float64(len(vals));
+    for _,v:=range(vals) {
+        sd+=(v-mean)*(v-mean);
+    }
+    return mean,stdMath.Sqrt(sd/float64(len(vals)));
+}
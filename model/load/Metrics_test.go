@@ -0,0 +1,115 @@
+package load
+
+import (
+	stdMath "math"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func constantDaily(days int, load float64) []DailyLoad {
+    rv:=make([]DailyLoad,days);
+    for i,_:=range(rv) {
+        rv[i]=DailyLoad{Date: testDate(1).AddDate(0,0,i), Tonnage: load};
+    }
+    return rv;
+}
+
+func TestNewSeriesInvalid(t *testing.T){
+    if _,err:=NewSeries(constantDaily(3,1),Measure(-1),Options{}); !IsInvalidMeasure(err) {
+        test.FormatError(InvalidMeasure(""),err,
+            "An invalid measure did not return the correct error.",t,
+        );
+    }
+}
+
+func TestNewSeriesWindows(t *testing.T){
+    o:=Options{AcuteDays: 2, ChronicDays: 4};
+    res,err:=NewSeries(constantDaily(5,10),Tonnage,o);
+    test.BasicTest(nil,err,"Creating a series returned an error.",t);
+    test.BasicTest(5,len(res.Points),"Not every day had a point.",t);
+    test.BasicTest(true,stdMath.IsNaN(res.Points[0].Acute),
+        "An incomplete acute window was not NaN.",t,
+    );
+    test.BasicTest(10.0,res.Points[1].Acute,"The acute load was not correct.",t);
+    test.BasicTest(true,stdMath.IsNaN(res.Points[2].Chronic),
+        "An incomplete chronic window was not NaN.",t,
+    );
+    test.BasicTest(10.0,res.Points[3].Chronic,"The chronic load was not correct.",t);
+    test.BasicTest(1.0,res.Points[3].AcwrRolling,"The rolling ACWR was not correct.",t);
+    test.BasicTest(1.0,res.Points[4].AcwrEwma,"The EWMA ACWR was not correct.",t);
+    test.BasicTest(true,stdMath.IsNaN(res.Points[4].Monotony),
+        "Monotony with no variation was not NaN.",t,
+    );
+    test.BasicTest(0,len(res.Warnings),"A constant load raised warnings.",t);
+}
+
+func TestNewSeriesMonotonyAndStrain(t *testing.T){
+    daily:=constantDaily(2,0);
+    daily[0].Tonnage,daily[1].Tonnage=10,30;
+    res,err:=NewSeries(daily,Tonnage,Options{
+        AcuteDays: 2, ChronicDays: 3,
+        Thresholds: Thresholds{MonotonyHigh: 1.5, StrainHigh: 50},
+    });
+    test.BasicTest(nil,err,"Creating a series returned an error.",t);
+    test.BasicTest(2.0,res.Points[1].Monotony,"The monotony was not correct.",t);
+    test.BasicTest(80.0,res.Points[1].Strain,"The strain was not correct.",t);
+    test.BasicTest(2,len(res.Warnings),"The wrong number of warnings were raised.",t);
+    test.BasicTest(MonotonyHigh,res.Warnings[0].Kind,"The wrong warning was raised.",t);
+    test.BasicTest(StrainHigh,res.Warnings[1].Kind,"The wrong warning was raised.",t);
+}
+
+func TestNewSeriesAcwrWarnings(t *testing.T){
+    daily:=constantDaily(8,10);
+    daily[7].Tonnage=100;
+    res,err:=NewSeries(daily,Tonnage,Options{AcuteDays: 1, ChronicDays: 4});
+    test.BasicTest(nil,err,"Creating a series returned an error.",t);
+    kinds:=map[WarningKind]bool{};
+    for _,w:=range(res.Warnings) {
+        test.BasicTest(true,w.Date.Equal(daily[7].Date),
+            "A warning was raised on the wrong day.",t,
+        );
+        kinds[w.Kind]=true;
+    }
+    test.BasicTest(true,kinds[AcwrRollingHigh],"A rolling ACWR spike was not warned.",t);
+    test.BasicTest(true,kinds[AcwrEwmaHigh],"An EWMA ACWR spike was not warned.",t);
+
+    daily=constantDaily(8,10);
+    daily[7].Tonnage=1;
+    res,_=NewSeries(daily,Tonnage,Options{
+        AcuteDays: 1, ChronicDays: 4, Thresholds: Thresholds{AcwrHigh: -1},
+    });
+    kinds=map[WarningKind]bool{};
+    for _,w:=range(res.Warnings) {
+        kinds[w.Kind]=true;
+    }
+    test.BasicTest(true,kinds[AcwrRollingLow],"A rolling ACWR drop was not warned.",t);
+    test.BasicTest(false,kinds[AcwrRollingHigh],"A disabled warning was raised.",t);
+}
+
+func TestSeriesTrim(t *testing.T){
+    s:=Series{
+        Points: []Point{{Date: testDate(1)},{Date: testDate(2)},{Date: testDate(3)}},
+        Warnings: []Warning{{Date: testDate(1)},{Date: testDate(3)}},
+    };
+    s.trim(testDate(2));
+    test.BasicTest(2,len(s.Points),"The points were not trimmed.",t);
+    test.BasicTest(1,len(s.Warnings),"The warnings were not trimmed.",t);
+}
+
+func TestMonitor(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    start:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    end:=time.Date(2022,time.Month(9),10,0,0,0,0,time.UTC);
+    res,err:=Monitor(&testDB,&c,Tonnage,start,end,Options{});
+    test.BasicTest(nil,err,"Monitoring the load returned an error.",t);
+    test.BasicTest(41,len(res.Points),"The series was not trimmed to the range.",t);
+    test.BasicTest(true,res.Points[0].Date.Equal(start),
+        "The series did not start on the start date.",t,
+    );
+    test.BasicTest(false,stdMath.IsNaN(res.Points[0].Chronic),
+        "The history before the start date was not used.",t,
+    );
+}
@@ -0,0 +1,93 @@
+package load
+
+import (
+	"fmt"
+
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The default values used when an option is left as its zero value.
+const (
+    DefaultAcuteDays int=7
+    DefaultChronicDays int=28
+    DefaultHardSetEffort float64=7.0
+    DefaultAcwrHigh float64=1.5
+    DefaultAcwrLow float64=0.8
+    DefaultMonotonyHigh float64=2.0
+);
+
+//The values that trigger warnings. A zero value uses the default, a negative
+//value disables the warning. The strain threshold has no default because
+//strain depends on the measure that is used, so it is disabled unless it is
+//set.
+type Thresholds struct {
+    AcwrHigh float64;
+    AcwrLow float64;
+    MonotonyHigh float64;
+    StrainHigh float64;
+};
+
+//The options used to calculate the load metrics.
+//  - ExerciseIDs: the exercises that are included, empty includes all of them
+//  - AcuteDays: the number of days in the acute window
+//  - ChronicDays: the number of days in the chronic window, must be larger
+//    than the acute window
+//  - HardSetEffort: the lowest effort (RPE) that makes a set a hard set
+type Options struct {
+    ExerciseIDs []int;
+    AcuteDays int;
+    ChronicDays int;
+    HardSetEffort float64;
+    Thresholds Thresholds;
+};
+
+func (o *Options)fillDefaults() {
+    if o.AcuteDays==0 {
+        o.AcuteDays=DefaultAcuteDays;
+    }
+    if o.ChronicDays==0 {
+        o.ChronicDays=DefaultChronicDays;
+    }
+    if o.HardSetEffort==0 {
+        o.HardSetEffort=DefaultHardSetEffort;
+    }
+    if o.Thresholds.AcwrHigh==0 {
+        o.Thresholds.AcwrHigh=DefaultAcwrHigh;
+    }
+    if o.Thresholds.AcwrLow==0 {
+        o.Thresholds.AcwrLow=DefaultAcwrLow;
+    }
+    if o.Thresholds.MonotonyHigh==0 {
+        o.Thresholds.MonotonyHigh=DefaultMonotonyHigh;
+    }
+}
+
+func (o Options)validate() error {
+    if o.AcuteDays<1 {
+        return customerr.ValOutsideRange(
+            fmt.Sprintf("Acute days: %d, must be >=1",o.AcuteDays),
+        );
+    } else if o.ChronicDays<=o.AcuteDays {
+        return customerr.ValOutsideRange(fmt.Sprintf(
+            "Chronic days: %d, must be > acute days (%d)",
+            o.ChronicDays,o.AcuteDays,
+        ));
+    } else if o.HardSetEffort<0 {
+        return customerr.ValOutsideRange(
+            fmt.Sprintf("Hard set effort: %f, must be >=0",o.HardSetEffort),
+        );
+    }
+    return nil;
+}
+
+func (o Options)includes(exerciseID int) bool {
+    if len(o.ExerciseIDs)==0 {
+        return true;
+    }
+    for _,v:=range(o.ExerciseIDs) {
+        if v==exerciseID {
+            return true;
+        }
+    }
+    return false;
+}
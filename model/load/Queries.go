@@ -0,0 +1,12 @@
+package load
+
+func trainingLogsBetweenDatesQuery() string {
+    return `SELECT *
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.DatePerformed>=$2
+            AND TrainingLog.DatePerformed<=$3
+        ORDER BY
+            DatePerformed ASC,
+            Id ASC;`;
+}
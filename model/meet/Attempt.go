@@ -0,0 +1,45 @@
+package meet
+
+import (
+	"github.com/barbell-math/engine/util/algo/iter"
+	"github.com/barbell-math/engine/util/io/csv"
+)
+
+//A single proposed attempt. Intensity is the attempt relative to the clients
+//max and PredictedIntensity is the intensity the model predicts the client can
+//single under the expected meet day fatigue. Likelihood is the chance that the
+//attempt is successful.
+type Attempt struct {
+    ExerciseID int;
+    Number int;
+    Weight float64;
+    Intensity float64;
+    PredictedIntensity float64;
+    Likelihood float64;
+};
+
+//The attempts for every lift of a meet, ordered by lift and then by attempt
+//number.
+type AttemptTable []Attempt;
+
+//The total if every attempt is successful, which is the sum of the heaviest
+//attempt of each lift.
+func (a AttemptTable)Total() float64 {
+    heaviest:=map[int]float64{};
+    for _,v:=range(a) {
+        if v.Weight>heaviest[v.ExerciseID] {
+            heaviest[v.ExerciseID]=v.Weight;
+        }
+    }
+    rv:=0.0;
+    for _,w:=range(heaviest) {
+        rv+=w;
+    }
+    return rv;
+}
+
+func (a AttemptTable)ToCSV(file string) error {
+    return csv.Flatten(csv.StructToCSV(
+        iter.SliceElems(a),true,"01/02/2006",
+    ),",").ToFile(file,true);
+}
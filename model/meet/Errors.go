@@ -0,0 +1,13 @@
+package meet;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var NoFeasibleAttempts,IsNoFeasibleAttempts=customerr.ErrorFactory(
+    "Three attempts that follow the federations rules could not be found.",
+);
+
+var InvalidStrategy,IsInvalidStrategy=customerr.ErrorFactory(
+    "The strategy is not a recognized strategy.",
+);
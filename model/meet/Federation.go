@@ -0,0 +1,46 @@
+package meet
+
+import (
+	stdMath "math"
+
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The attempt rules of a federation. Every attempt must be a multiple of the
+//increment and each attempt must be at least the minimum jump heavier than the
+//previous attempt of the same lift.
+type Federation struct {
+    Name string;
+    increment float64;
+    minJump float64;
+};
+
+func NewFederation(
+        name string,
+        increment float64,
+        minJump float64) (Federation,error) {
+    rv:=Federation{Name: name, increment: increment, minJump: minJump};
+    if increment<=0 {
+        return rv,customerr.InvalidValue("increment <= 0, should be >0");
+    } else if minJump<increment {
+        return rv,customerr.InvalidValue(
+            "minimum jump < increment, should be >=increment",
+        );
+    }
+    return rv,nil;
+}
+
+func (f Federation)Increment() float64 { return f.increment; }
+func (f Federation)MinJump() float64 { return f.minJump; }
+
+//Returns the heaviest legal attempt that is <= the given weight.
+func (f Federation)RoundDown(weight float64) float64 {
+    //The small constant keeps floating point error from dropping a weight
+    //that is already legal down an increment.
+    return stdMath.Floor(weight/f.increment+1e-9)*f.increment;
+}
+
+//Returns the lightest legal attempt that is >= the given weight.
+func (f Federation)RoundUp(weight float64) float64 {
+    return stdMath.Ceil(weight/f.increment-1e-9)*f.increment;
+}
@@ -0,0 +1,34 @@
+package meet
+
+import (
+	"testing"
+
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestNewFederation(t *testing.T){
+    f,err:=NewFederation("IPF",2.5,2.5);
+    test.BasicTest(nil,err,"A valid federation returned an error.",t);
+    test.BasicTest(2.5,f.Increment(),"The increment was not set.",t);
+    test.BasicTest(2.5,f.MinJump(),"The minimum jump was not set.",t);
+    for _,vals:=range([][2]float64{{0,2.5},{-2.5,2.5},{5,2.5}}) {
+        if _,err=NewFederation("",vals[0],vals[1]); !customerr.IsInvalidValue(err) {
+            test.FormatError(customerr.InvalidValue(""),err,
+                "An invalid federation did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestFederationRounding(t *testing.T){
+    f,_:=NewFederation("IPF",2.5,2.5);
+    test.BasicTest(float64(200),f.RoundDown(201),"Rounding down was not correct.",t);
+    test.BasicTest(float64(202.5),f.RoundDown(202.5),
+        "Rounding down changed a legal attempt.",t,
+    );
+    test.BasicTest(float64(202.5),f.RoundUp(201),"Rounding up was not correct.",t);
+    test.BasicTest(float64(202.5),f.RoundUp(202.5),
+        "Rounding up changed a legal attempt.",t,
+    );
+}
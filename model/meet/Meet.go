@@ -0,0 +1,198 @@
+package meet
+
+import (
+	"fmt"
+	stdMath "math"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model/oneRepMax"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The number of attempts each lift gets at a meet.
+const AttemptsPerLift int=3;
+
+//The expected conditions on meet day.
+type MeetDay struct {
+    Date time.Time;
+    //The competition lifts in the order they are performed.
+    ExerciseIDs []int;
+    //The fatigue expected from the training leading up to the meet.
+    InterWorkoutFatigue int;
+    //Only used by the TargetTotal strategy.
+    TargetTotal float64;
+};
+
+type Planner struct {
+    sg stateGen.StateGeneratorId;
+    surf potSurf.PotentialSurfaceId;
+    fed Federation;
+    strategy Strategy;
+};
+
+//Holds everything that is needed to plan the attempts of a single lift.
+type liftState struct {
+    exerciseID int;
+    ms db.ModelState;
+    calc potSurf.Calculations;
+    max float64;
+    interExerciseFatigue int;
+    interWorkoutFatigue int;
+};
+
+func NewPlanner(
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        fed Federation,
+        strategy Strategy) (Planner,error) {
+    rv:=Planner{sg: sg, surf: surf, fed: fed, strategy: strategy};
+    if _,err:=potSurf.Lookup(surf); err!=nil {
+        return rv,err;
+    } else if fed.increment<=0 {
+        return rv,customerr.InvalidValue(
+            "federation was not created with NewFederation",
+        );
+    } else if strategy<Conservative || strategy>MaxTotal {
+        return rv,InvalidStrategy(fmt.Sprintf("Strategy: %d",strategy));
+    }
+    return rv,nil;
+}
+
+//Proposes an opener, second, and third attempt for every lift of the meet.
+//For each lift the model state from the state generator and surface that is
+//closest to (but before) the meet is used to predict the intensity the client
+//can single at an effort of 10. Fatigue is accumulated in the order the lifts
+//are performed:
+//  - Inter exercise fatigue is the number of attempts taken on earlier lifts.
+//  - Inter workout fatigue is the expected fatigue given in the meet day.
+//The likelihood of an attempt succeeding assumes the clients true intensity is
+//normally distributed around the predicted intensity with a standard deviation
+//equal to the sigma of the model state, which is in intensity units. The maxes are taken from the clients max history,
+//falling back to the max implied by the most recent training log.
+func (p Planner)Plan(
+        d *db.DB,
+        c *db.Client,
+        m MeetDay) (AttemptTable,error) {
+    lifts:=make([]liftState,len(m.ExerciseIDs));
+    calc,err:=potSurf.CalculationsFromSurfaceId(p.surf);
+    if err!=nil {
+        return AttemptTable{},err;
+    }
+    for i,eId:=range(m.ExerciseIDs) {
+        ms,err:=db.GetModelStateBeforeDate(
+            d,c.Id,eId,int(p.sg),int(p.surf),m.Date,
+        );
+        if err!=nil {
+            return AttemptTable{},err;
+        }
+        liftCalc,err:=potSurf.CalculationsWithHistory(d,calc,&db.TrainingLog{
+            ClientID: c.Id, ExerciseID: eId, DatePerformed: m.Date,
+        });
+        if err!=nil {
+            return AttemptTable{},err;
+        }
+        eMax,err:=oneRepMax.MaxOnDate(d,c.Id,eId,m.Date);
+        if err!=nil {
+            return AttemptTable{},err;
+        }
+        lifts[i]=liftState{
+            exerciseID: eId,
+            ms: ms,
+            calc: liftCalc,
+            max: eMax,
+            interExerciseFatigue: i*AttemptsPerLift,
+            interWorkoutFatigue: m.InterWorkoutFatigue,
+        };
+    }
+    return p.plan(lifts,m.TargetTotal);
+}
+
+func (p Planner)plan(lifts []liftState, targetTotal float64) (AttemptTable,error) {
+    rv:=AttemptTable{};
+    predicted:=make([]float64,len(lifts));
+    predictedTotal:=0.0;
+    for i,_:=range(lifts) {
+        predicted[i]=lifts[i].predictedIntensity();
+        if stdMath.IsNaN(predicted[i]) || predicted[i]<=0 {
+            return rv,NoFeasibleAttempts(fmt.Sprintf(
+                "Exercise: %d Predicted intensity: %v",
+                lifts[i].exerciseID,predicted[i],
+            ));
+        }
+        if stdMath.IsNaN(lifts[i].ms.Sigma) {
+            return rv,NoFeasibleAttempts(fmt.Sprintf(
+                "Exercise: %d Sigma: %v",lifts[i].exerciseID,lifts[i].ms.Sigma,
+            ));
+        }
+        predictedTotal+=predicted[i]*lifts[i].max;
+    }
+    if p.strategy==TargetTotal && targetTotal<=0 {
+        return rv,customerr.InvalidValue("target total <= 0, should be >0");
+    }
+    for i,l:=range(lifts) {
+        var third float64;
+        switch p.strategy {
+            case Conservative:
+                z:=stdMath.Sqrt2*stdMath.Erfinv(2*ConservativeLikelihood-1);
+                third=p.fed.RoundDown(
+                    (predicted[i]-z*l.ms.Sigma)*l.max,
+                );
+            case TargetTotal:
+                third=p.fed.RoundUp(
+                    targetTotal*predicted[i]*l.max/predictedTotal,
+                );
+            case MaxTotal: third=p.fed.RoundDown(predicted[i]*l.max);
+        }
+        second:=stdMath.Min(
+            p.fed.RoundDown(third*SecondPercent),
+            p.fed.RoundDown(third-p.fed.minJump),
+        );
+        opener:=stdMath.Min(
+            p.fed.RoundDown(third*OpenerPercent),
+            p.fed.RoundDown(second-p.fed.minJump),
+        );
+        if opener<=0 {
+            return rv,NoFeasibleAttempts(fmt.Sprintf(
+                "Exercise: %d Third attempt: %v",l.exerciseID,third,
+            ));
+        }
+        for j,w:=range([]float64{opener,second,third}) {
+            rv=append(rv,Attempt{
+                ExerciseID: l.exerciseID,
+                Number: j+1,
+                Weight: w,
+                Intensity: w/l.max,
+                PredictedIntensity: predicted[i],
+                Likelihood: likelihood(w/l.max,predicted[i],l.ms.Sigma*l.ms.Sigma),
+            });
+        }
+    }
+    return rv,nil;
+}
+
+//The intensity the client is predicted to be able to single on meet day.
+func (l *liftState)predictedIntensity() float64 {
+    return l.calc.Intensity(&l.ms,&db.TrainingLog{
+        Sets: 1,
+        Reps: 1,
+        Effort: 10,
+        InterExerciseFatigue: l.interExerciseFatigue,
+        InterWorkoutFatigue: l.interWorkoutFatigue,
+    });
+}
+
+//The probability that a normally distributed variable with the given mean and
+//variance is >= the given intensity. A variance of zero means the prediction
+//is certain.
+func likelihood(intensity float64, mean float64, variance float64) float64 {
+    if variance<=0 {
+        if intensity<=mean+1e-9 {
+            return 1;
+        }
+        return 0;
+    }
+    return 0.5*stdMath.Erfc((intensity-mean)/stdMath.Sqrt(2*variance));
+}
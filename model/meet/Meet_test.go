@@ -0,0 +1,170 @@
+package meet
+
+import (
+	stdMath "math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+)
+
+func testLifts() []liftState {
+    ms:=db.ModelState{Eps: 0.5, Eps1: 0.05, Eps2: 0.001, Eps3: 0.002, Sigma: 0.02};
+    return []liftState{
+        liftState{exerciseID: 1, ms: ms, calc: potSurf.BasicSurfaceCalculation, max: 500},
+        liftState{exerciseID: 2, ms: ms, calc: potSurf.BasicSurfaceCalculation, max: 300,
+            interExerciseFatigue: AttemptsPerLift,
+        },
+        liftState{exerciseID: 3, ms: ms, calc: potSurf.BasicSurfaceCalculation, max: 600,
+            interExerciseFatigue: 2*AttemptsPerLift,
+        },
+    };
+}
+
+func testPlanner(s Strategy) Planner {
+    f,_:=NewFederation("IPF",2.5,2.5);
+    p,_:=NewPlanner(stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,f,s);
+    return p;
+}
+
+func TestNewPlannerInvalid(t *testing.T){
+    f,_:=NewFederation("IPF",2.5,2.5);
+    _,err:=NewPlanner(stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,f,-1);
+    if !IsInvalidStrategy(err) {
+        test.FormatError(InvalidStrategy(""),err,
+            "Creating a planner with an invalid strategy did not error.",t,
+        );
+    }
+    _,err=NewPlanner(
+        stateGen.SlidingWindowStateGenId,potSurf.PotentialSurfaceId(-1),f,MaxTotal,
+    );
+    if !potSurf.IsInvalidPotentialSurfaceId(err) {
+        test.FormatError(potSurf.InvalidPotentialSurfaceId(""),err,
+            "Creating a planner with an invalid surface did not error.",t,
+        );
+    }
+}
+
+func TestPlanFollowsFederationRules(t *testing.T){
+    for _,s:=range([]Strategy{Conservative,TargetTotal,MaxTotal}) {
+        p:=testPlanner(s);
+        res,err:=p.plan(testLifts(),1200);
+        test.BasicTest(nil,err,"Planning returned an error.",t);
+        test.BasicTest(9,len(res),"Not every attempt was planned.",t);
+        for i,a:=range(res) {
+            test.BasicTest(p.fed.RoundDown(a.Weight),a.Weight,
+                "An attempt was not a legal weight.",t,
+            );
+            test.BasicTest(i%AttemptsPerLift+1,a.Number,
+                "Attempts were not numbered in order.",t,
+            );
+            if a.Number==1 {
+                continue;
+            }
+            test.BasicTest(true,a.Weight-res[i-1].Weight>=p.fed.MinJump(),
+                "An attempt did not follow the minimum jump.",t,
+            );
+            test.BasicTest(true,a.Likelihood<=res[i-1].Likelihood,
+                "A heavier attempt was more likely to succeed.",t,
+            );
+        }
+    }
+}
+
+func TestPlanStrategies(t *testing.T){
+    conservative,_:=testPlanner(Conservative).plan(testLifts(),0);
+    maxTotal,_:=testPlanner(MaxTotal).plan(testLifts(),0);
+    test.BasicTest(true,conservative.Total()<maxTotal.Total(),
+        "The conservative total was not less than the max total.",t,
+    );
+    for i:=AttemptsPerLift-1; i<len(maxTotal); i+=AttemptsPerLift {
+        test.BasicTest(true,conservative[i].Likelihood>=ConservativeLikelihood,
+            "A conservative third attempt was not likely enough to succeed.",t,
+        );
+        test.BasicTest(true,maxTotal[i].Likelihood>=0.5,
+            "A max total third attempt was less than 50% likely to succeed.",t,
+        );
+    }
+    target,err:=testPlanner(TargetTotal).plan(testLifts(),1300);
+    test.BasicTest(nil,err,"Planning returned an error.",t);
+    test.BasicTest(true,target.Total()>=1300 && target.Total()<1300+3*2.5,
+        "The target total was not met.",t,
+    );
+    _,err=testPlanner(TargetTotal).plan(testLifts(),0);
+    if err==nil {
+        test.FormatError("error",err,"A target total of 0 did not error.",t);
+    }
+}
+
+func TestPlanFatigue(t *testing.T){
+    res,_:=testPlanner(MaxTotal).plan(testLifts(),0);
+    test.BasicTest(true,
+        res[AttemptsPerLift].PredictedIntensity<res[0].PredictedIntensity,
+        "Fatigue from earlier lifts did not lower the predicted intensity.",t,
+    );
+}
+
+func TestPlanInfeasible(t *testing.T){
+    l:=testLifts();
+    l[0].ms=db.ModelState{Eps: -1};
+    _,err:=testPlanner(MaxTotal).plan(l,0);
+    if !IsNoFeasibleAttempts(err) {
+        test.FormatError(NoFeasibleAttempts(""),err,
+            "A lift that cannot be performed did not return an error.",t,
+        );
+    }
+    l=testLifts();
+    l[1].ms.Sigma=stdMath.NaN();
+    _,err=testPlanner(Conservative).plan(l,0);
+    if !IsNoFeasibleAttempts(err) {
+        test.FormatError(NoFeasibleAttempts(""),err,
+            "A lift without a sigma did not return an error.",t,
+        );
+    }
+}
+
+func TestLikelihood(t *testing.T){
+    test.BasicTest(0.5,likelihood(0.9,0.9,0.01),
+        "An attempt at the predicted intensity was not 50% likely.",t,
+    );
+    test.BasicTest(float64(1),likelihood(0.8,0.9,0),
+        "A certain prediction did not give a likelihood of 1.",t,
+    );
+    test.BasicTest(float64(0),likelihood(1,0.9,0),
+        "A certain prediction did not give a likelihood of 0.",t,
+    );
+}
+
+func TestAttemptTableToCSV(t *testing.T){
+    res,_:=testPlanner(MaxTotal).plan(testLifts(),0);
+    err:=res.ToCSV("testData/attempts.csv");
+    test.BasicTest(nil,err,"Exporting the attempt table returned an error.",t);
+    os.Remove("testData/attempts.csv");
+}
+
+func TestPlan(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    sw.GenerateClientModelStates(&testDB,c,
+        time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC),surfs,
+    );
+    res,err:=testPlanner(MaxTotal).Plan(&testDB,&c,MeetDay{
+        Date: time.Date(2022,time.Month(9),12,0,0,0,0,time.UTC),
+        ExerciseIDs: []int{15},
+    });
+    if err!=nil && !IsNoFeasibleAttempts(err) {
+        test.FormatError(nil,err,"Planning a meet returned an error.",t);
+    }
+    if err==nil {
+        test.BasicTest(AttemptsPerLift,len(res),"Not every attempt was planned.",t);
+    }
+}
@@ -0,0 +1,33 @@
+package meet;
+
+//Determines how the third attempt of each lift is selected. The opener and
+//second attempt are always set relative to the third attempt.
+//  - Conservative: the heaviest third attempt that has at least a
+//    ConservativeLikelihood chance of success.
+//  - TargetTotal: the target total is split between the lifts proportionally
+//    to their predicted maxes.
+//  - MaxTotal: the heaviest third attempt that has at least a 50% chance of
+//    success, which is the predicted max rounded down.
+type Strategy int;
+const (
+    Conservative Strategy=iota
+    TargetTotal
+    MaxTotal
+);
+
+//The percentages of the third attempt that the opener and second attempt are
+//set to before being rounded down to legal attempts.
+const (
+    OpenerPercent float64=0.91;
+    SecondPercent float64=0.96;
+    ConservativeLikelihood float64=0.9;
+);
+
+func (s Strategy)String() string {
+    switch s {
+        case Conservative: return "Conservative";
+        case TargetTotal: return "Target Total";
+        case MaxTotal: return "Max Total";
+        default: return "unknown";
+    }
+}
@@ -0,0 +1,18 @@
+package meet;
+
+import (
+    "testing"
+    "github.com/barbell-math/engine/util/test"
+)
+
+func TestStrategyString(t *testing.T){
+    test.BasicTest("Conservative",Conservative.String(),
+        "Strategy string was not correct.",t,
+    );
+    test.BasicTest("Max Total",MaxTotal.String(),
+        "Strategy string was not correct.",t,
+    );
+    test.BasicTest("unknown",Strategy(-1).String(),
+        "Strategy string was not correct.",t,
+    );
+}
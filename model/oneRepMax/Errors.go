@@ -0,0 +1,17 @@
+package oneRepMax;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var NoMaxForIntensity,IsNoMaxForIntensity=customerr.ErrorFactory(
+    "A max could not be found to derive the intensity from.",
+);
+
+var NoMaxForExercise,IsNoMaxForExercise=customerr.ErrorFactory(
+    "Could not find a max for the exercise.",
+);
+
+var InvalidMaxSource,IsInvalidMaxSource=customerr.ErrorFactory(
+    "The max source does not have an associated estimation formula.",
+);
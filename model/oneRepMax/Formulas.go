@@ -0,0 +1,115 @@
+package oneRepMax;
+
+import (
+	"fmt"
+	stdMath "math"
+
+	"github.com/barbell-math/engine/db"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The rep ranges the formulas are considered accurate over. Outside of these
+//ranges an error is returned rather than a poor estimate.
+const (
+    MaxFormulaReps=12;
+    MinRpe=6.0;
+    MaxRpe=10.0;
+);
+
+//Percentages of a 1RM indexed by 2*(reps-1)+2*(10-rpe). Each step down the
+//table is half an RPE, so reps 1 at RPE 10 is index 0, reps 1 at RPE 9.5 is
+//index 1, and so on. The values are the commonly used RTS chart.
+var rpeTable=[...]float64{
+    1.000,0.978,0.955,0.939,0.922,0.907,0.892,0.878,0.863,0.850,
+    0.837,0.824,0.811,0.799,0.786,0.774,0.762,0.751,0.739,0.723,
+    0.707,0.694,0.680,0.667,0.653,0.640,0.626,0.613,0.599,0.586,
+    0.574,
+};
+
+func validWeightReps(w float64, reps float64) error {
+    if w<0 {
+        return customerr.ValOutsideRange(fmt.Sprintf("Weight: %f<0",w));
+    } else if reps<1 || reps>MaxFormulaReps {
+        return customerr.ValOutsideRange(fmt.Sprintf(
+            "Reps: %f not in [1,%d]",reps,MaxFormulaReps,
+        ));
+    }
+    return nil;
+}
+
+//1RM=w*(1+r/30). A single is returned as is.
+func Epley(w float64, reps float64) (float64,error) {
+    if err:=validWeightReps(w,reps); err!=nil {
+        return 0,err;
+    }
+    if reps==1 {
+        return w,nil;
+    }
+    return w*(1+reps/30),nil;
+}
+
+//1RM=w*36/(37-r)
+func Brzycki(w float64, reps float64) (float64,error) {
+    if err:=validWeightReps(w,reps); err!=nil {
+        return 0,err;
+    }
+    return w*36/(37-reps),nil;
+}
+
+//1RM=w*r^0.1
+func Lombardi(w float64, reps float64) (float64,error) {
+    if err:=validWeightReps(w,reps); err!=nil {
+        return 0,err;
+    }
+    return w*stdMath.Pow(reps,0.1),nil;
+}
+
+//Estimates a 1RM from the percentage in the RPE table. Fractional reps and
+//RPE values are linearly interpolated between table entries.
+func RpeTable(w float64, reps float64, rpe float64) (float64,error) {
+    if err:=validWeightReps(w,reps); err!=nil {
+        return 0,err;
+    } else if rpe<MinRpe || rpe>MaxRpe {
+        return 0,customerr.ValOutsideRange(fmt.Sprintf(
+            "RPE: %f not in [%f,%f]",rpe,MinRpe,MaxRpe,
+        ));
+    }
+    idx:=2*(reps-1)+2*(MaxRpe-rpe);
+    low:=int(stdMath.Floor(idx));
+    high:=int(stdMath.Ceil(idx));
+    frac:=idx-float64(low);
+    pct:=rpeTable[low]+frac*(rpeTable[high]-rpeTable[low]);
+    return w/pct,nil;
+}
+
+//Estimates a 1RM using the intensity a model state predicts for the training
+//log. All values of the training log besides intensity need to be accurate.
+func FromModelState(
+        c potSurf.Calculations,
+        ms *db.ModelState,
+        tl *db.TrainingLog) (float64,error) {
+    i,err:=potSurf.IntensityVar.Solve(c,ms,tl);
+    if err!=nil {
+        return 0,err;
+    } else if !(i>0) {
+        return 0,customerr.ValOutsideRange(fmt.Sprintf(
+            "Predicted intensity: %f<=0",i,
+        ));
+    }
+    return tl.Weight/i,nil;
+}
+
+//Estimates a 1RM from a training log using the formula associated with the
+//source. The effort of the training log is used as the RPE. Tested and model
+//state maxes do not have a formula that only uses the training log so they
+//return an error, model state maxes are recorded with RecordFromModelState.
+func Estimate(src db.MaxSource, tl *db.TrainingLog) (float64,error) {
+    switch src {
+        case db.EpleyMax: return Epley(tl.Weight,tl.Reps);
+        case db.BrzyckiMax: return Brzycki(tl.Weight,tl.Reps);
+        case db.LombardiMax: return Lombardi(tl.Weight,tl.Reps);
+        case db.RpeTableMax: return RpeTable(tl.Weight,tl.Reps,tl.Effort);
+        default: return 0,InvalidMaxSource(fmt.Sprintf("Source: %s",src));
+    }
+}
@@ -0,0 +1,107 @@
+package oneRepMax;
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestEpley(t *testing.T){
+    v,err:=Epley(300,1);
+    test.BasicTest(nil,err,"Epley returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(300),v,"Epley did not return singles as is.",t);
+    v,err=Epley(300,5);
+    test.BasicTest(nil,err,"Epley returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(350),v,"Epley estimate was not correct.",t);
+}
+
+func TestBrzycki(t *testing.T){
+    v,err:=Brzycki(320,1);
+    test.BasicTest(nil,err,"Brzycki returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(320),v,"Brzycki did not return singles as is.",t);
+    v,err=Brzycki(310,6);
+    test.BasicTest(nil,err,"Brzycki returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(360),v,"Brzycki estimate was not correct.",t);
+}
+
+func TestLombardi(t *testing.T){
+    v,err:=Lombardi(300,1);
+    test.BasicTest(nil,err,"Lombardi returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(300),v,"Lombardi did not return singles as is.",t);
+    v,err=Lombardi(100,10);
+    test.BasicTest(nil,err,"Lombardi returned an error when it shouldn't have.",t);
+    test.BasicTest(true,v>125.89 && v<125.90,"Lombardi estimate was not correct.",t);
+}
+
+func TestFormulasInvalid(t *testing.T){
+    for _,f:=range([]func(w float64, r float64) (float64,error){
+        Epley,Brzycki,Lombardi,
+    }) {
+        for _,vals:=range([][2]float64{{-1,5},{100,0},{100,13}}) {
+            if _,err:=f(vals[0],vals[1]); !customerr.IsValOutsideRange(err) {
+                test.FormatError(customerr.ValOutsideRange(""),err,
+                    "Invalid formula inputs did not return an error.",t,
+                );
+            }
+        }
+    }
+}
+
+func TestRpeTable(t *testing.T){
+    v,err:=RpeTable(300,1,10);
+    test.BasicTest(nil,err,"RpeTable returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(300),v,"RpeTable did not return singles as is.",t);
+    v,err=RpeTable(86.3,5,10);
+    test.BasicTest(nil,err,"RpeTable returned an error when it shouldn't have.",t);
+    test.BasicTest(true,v>99.999 && v<100.001,"RpeTable estimate was not correct.",t);
+    //Reps 4 at RPE 9 is the same table entry as reps 5 at RPE 10
+    v2,err:=RpeTable(86.3,4,9);
+    test.BasicTest(nil,err,"RpeTable returned an error when it shouldn't have.",t);
+    test.BasicTest(v,v2,"RpeTable did not use the correct index.",t);
+    v,err=RpeTable(100,1,9.75);
+    test.BasicTest(nil,err,"RpeTable returned an error when it shouldn't have.",t);
+    test.BasicTest(true,v>100 && v<100/0.978,
+        "RpeTable did not interpolate between entries.",t,
+    );
+    for _,vals:=range([][3]float64{{100,1,5},{100,1,11},{100,13,10},{-1,1,10}}) {
+        if _,err:=RpeTable(vals[0],vals[1],vals[2]); !customerr.IsValOutsideRange(err) {
+            test.FormatError(customerr.ValOutsideRange(""),err,
+                "Invalid RpeTable inputs did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestFromModelState(t *testing.T){
+    ms:=db.ModelState{Eps: 0.5};
+    tl:=db.TrainingLog{Weight: 150, Sets: 1, Reps: 1, Effort: 0};
+    c:=potSurf.BasicSurfaceCalculation;
+    v,err:=FromModelState(c,&ms,&tl);
+    test.BasicTest(nil,err,
+        "FromModelState returned an error when it shouldn't have.",t,
+    );
+    test.BasicTest(float64(300),v,"FromModelState estimate was not correct.",t);
+    ms=db.ModelState{Eps: -1};
+    if _,err=FromModelState(c,&ms,&tl); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A non-positive predicted intensity did not return an error.",t,
+        );
+    }
+}
+
+func TestEstimate(t *testing.T){
+    tl:=db.TrainingLog{Weight: 300, Reps: 5, Effort: 10};
+    v,err:=Estimate(db.EpleyMax,&tl);
+    test.BasicTest(nil,err,"Estimate returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(350),v,"Estimate did not use the Epley formula.",t);
+    for _,s:=range([]db.MaxSource{db.TestedMax,db.ModelStateMax,db.MaxSource(-1)}) {
+        if _,err=Estimate(s,&tl); !IsInvalidMaxSource(err) {
+            test.FormatError(InvalidMaxSource(""),err,
+                "A source without a formula did not return an error.",t,
+            );
+        }
+    }
+}
@@ -0,0 +1,134 @@
+package oneRepMax;
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+)
+
+//Estimates a 1RM from the training log using the formula associated with the
+//source and saves it to the clients max history on the date the log was
+//performed. The max is in the unit of the training log.
+func Record(d *db.DB, src db.MaxSource, tl *db.TrainingLog) (db.ExerciseMax,error) {
+    w,err:=Estimate(src,tl);
+    if err!=nil {
+        return db.ExerciseMax{},err;
+    }
+    return saveMax(d,src,w,tl);
+}
+
+//Estimates a 1RM from the training log using the intensity the model state
+//predicts for it (see FromModelState) and saves it to the clients max history
+//on the date the log was performed with a source of db.ModelStateMax. The max
+//is in the unit of the training log.
+func RecordFromModelState(
+        d *db.DB,
+        c potSurf.Calculations,
+        ms *db.ModelState,
+        tl *db.TrainingLog) (db.ExerciseMax,error) {
+    w,err:=FromModelState(c,ms,tl);
+    if err!=nil {
+        return db.ExerciseMax{},err;
+    }
+    return saveMax(d,db.ModelStateMax,w,tl);
+}
+
+func saveMax(
+        d *db.DB,
+        src db.MaxSource,
+        w float64,
+        tl *db.TrainingLog) (db.ExerciseMax,error) {
+    rv:=db.ExerciseMax{
+        ClientID: tl.ClientID,
+        ExerciseID: tl.ExerciseID,
+        Date: tl.DatePerformed,
+        Weight: w,
+        Source: int(src),
+        Unit: tl.Unit,
+    };
+    ids,err:=db.Create(d,rv);
+    if err==nil {
+        rv.Id=ids[0];
+    }
+    return rv,err;
+}
+
+//Note - THE ORDER OF THE STRUCT FIELDS MUST MATCH THE ORDER OF THE VALUES
+//IN THE QUERY.
+type impliedMax struct {
+    Max float64;
+};
+
+//Returns the max, in kilograms, to base loads on for the exercise on the given
+//date. The max history is preferred, falling back to the max implied by the
+//most recent training log with an intensity before the date when no max has
+//been recorded for the exercise.
+func MaxOnDate(
+        d *db.DB,
+        clientId int,
+        eId int,
+        date time.Time) (float64,error) {
+    if m,err:=db.GetMaxOnDate(d,clientId,eId,date); err==nil && m.Weight>0 {
+        return m.Weight,nil;
+    } else if err!=nil && err!=sql.ErrNoRows {
+        return 0,err;
+    }
+    m,err,_:=db.CustomReadQuery[impliedMax](d,
+        latestMaxQuery(),[]any{clientId,eId,date},
+    ).Nth(0);
+    if err==sql.ErrNoRows || (err==nil && (m==nil || !(m.Max>0))) {
+        return 0,NoMaxForExercise(fmt.Sprintf("Exercise: %d",eId));
+    }
+    return m.Max,err;
+}
+
+//Creates the training logs, deriving the intensity of every log that does not
+//have one from the max that was in effect on the day it was performed (see
+//DeriveIntensity). Logs that already have an intensity are saved as they are.
+//The weights of the logs are in the unit of each log.
+func CreateTrainingLogs(d *db.DB, logs ...db.TrainingLog) ([]int,error) {
+    rows:=append([]db.TrainingLog{},logs...);
+    for i,_:=range(rows) {
+        if rows[i].Intensity>0 {
+            continue;
+        }
+        if err:=DeriveIntensity(d,&rows[i]); err!=nil {
+            return []int{},err;
+        }
+    }
+    return db.Create(d,rows...);
+}
+
+//Reads training logs from a CSV file and creates them with CreateTrainingLogs.
+//The file is read in the same way as db.ImportCSV.
+func ImportTrainingLogs(
+        d *db.DB,
+        file string,
+        timeDateFormat string,
+        defaultUnit db.Unit) ([]int,error) {
+    logs,err:=db.ReadCSV[db.TrainingLog](file,timeDateFormat,defaultUnit);
+    if err!=nil {
+        return []int{},err;
+    }
+    return CreateTrainingLogs(d,logs...);
+}
+
+//Sets the intensity of the training log relative to the max that was in effect
+//on the day the log was performed. The weight of the training log needs to be
+//set and is expected to be in the unit of the training log.
+func DeriveIntensity(d *db.DB, tl *db.TrainingLog) error {
+    m,err:=db.GetMaxOnDate(d,tl.ClientID,tl.ExerciseID,tl.DatePerformed);
+    if err==sql.ErrNoRows || (err==nil && !(m.Weight>0)) {
+        return NoMaxForIntensity(fmt.Sprintf(
+            "Client: %d Exercise: %d Date: %s",
+            tl.ClientID,tl.ExerciseID,tl.DatePerformed,
+        ));
+    } else if err!=nil {
+        return err;
+    }
+    tl.Intensity=db.Unit(tl.Unit).ToKg(tl.Weight)/m.Weight;
+    return nil;
+}
@@ -0,0 +1,108 @@
+package oneRepMax;
+
+import (
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestRecordAndDeriveIntensity(t *testing.T){
+    day:=time.Date(2030,time.January,10,0,0,0,0,time.UTC);
+    tl:=db.TrainingLog{
+        ClientID: 1, ExerciseID: 1, DatePerformed: day,
+        Weight: 300, Sets: 1, Reps: 5, Effort: 10,
+    };
+    m,err:=Record(&testDB,db.EpleyMax,&tl);
+    test.BasicTest(nil,err,"Recording a max returned an error.",t);
+    test.BasicTest(float64(350),m.Weight,"The recorded max was not correct.",t);
+    test.BasicTest(true,m.Id>0,"The id of the recorded max was not set.",t);
+    tl.DatePerformed=day.AddDate(0,0,1);
+    tl.Weight=315;
+    err=DeriveIntensity(&testDB,&tl);
+    test.BasicTest(nil,err,"Deriving intensity returned an error.",t);
+    test.BasicTest(float64(0.9),tl.Intensity,"The derived intensity was not correct.",t);
+    tl.ExerciseID=-1;
+    if err=DeriveIntensity(&testDB,&tl); !IsNoMaxForIntensity(err) {
+        test.FormatError(NoMaxForIntensity(""),err,
+            "Deriving intensity without a max did not return an error.",t,
+        );
+    }
+}
+
+func TestRecordFromModelState(t *testing.T){
+    tl:=db.TrainingLog{
+        ClientID: 1, ExerciseID: 3,
+        DatePerformed: time.Date(2030,time.February,10,0,0,0,0,time.UTC),
+        Weight: 150, Sets: 1, Reps: 1, Effort: 0,
+    };
+    c:=potSurf.BasicSurfaceCalculation;
+    m,err:=RecordFromModelState(&testDB,c,&db.ModelState{Eps: 0.5},&tl);
+    test.BasicTest(nil,err,"Recording a max returned an error.",t);
+    test.BasicTest(float64(300),m.Weight,"The recorded max was not correct.",t);
+    test.BasicTest(int(db.ModelStateMax),m.Source,
+        "The recorded max did not have a model state source.",t,
+    );
+    test.BasicTest(true,m.Id>0,"The id of the recorded max was not set.",t);
+    if _,err=RecordFromModelState(
+        &testDB,c,&db.ModelState{Eps: -1},&tl,
+    ); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A non-positive predicted intensity did not return an error.",t,
+        );
+    }
+}
+
+func TestMaxOnDate(t *testing.T){
+    day:=time.Date(2031,time.January,10,0,0,0,0,time.UTC);
+    _,err:=Record(&testDB,db.EpleyMax,&db.TrainingLog{
+        ClientID: 1, ExerciseID: 2, DatePerformed: day,
+        Weight: 300, Sets: 1, Reps: 5, Effort: 10,
+    });
+    test.BasicTest(nil,err,"Recording a max returned an error.",t);
+    m,err:=MaxOnDate(&testDB,1,2,day);
+    test.BasicTest(nil,err,"Getting the max returned an error.",t);
+    test.BasicTest(float64(350),m,"The max history was not used.",t);
+    if _,err=MaxOnDate(&testDB,1,-1,day); !IsNoMaxForExercise(err) {
+        test.FormatError(NoMaxForExercise(""),err,
+            "Getting a max that does not exist did not return an error.",t,
+        );
+    }
+}
+
+func TestCreateTrainingLogs(t *testing.T){
+    day:=time.Date(2032,time.January,10,0,0,0,0,time.UTC);
+    _,err:=Record(&testDB,db.EpleyMax,&db.TrainingLog{
+        ClientID: 1, ExerciseID: 3, DatePerformed: day,
+        Weight: 300, Sets: 1, Reps: 5, Effort: 10,
+    });
+    test.BasicTest(nil,err,"Recording a max returned an error.",t);
+    ids,err:=CreateTrainingLogs(&testDB,db.TrainingLog{
+        ClientID: 1, ExerciseID: 3, RotationID: 1, DatePerformed: day.AddDate(0,0,1),
+        Weight: 315, Sets: 1, Reps: 1, Effort: 10, Volume: 315,
+    },db.TrainingLog{
+        ClientID: 1, ExerciseID: 3, RotationID: 1, DatePerformed: day.AddDate(0,0,1),
+        Weight: 300, Sets: 1, Reps: 1, Intensity: 0.5, Effort: 10, Volume: 300,
+    });
+    test.BasicTest(nil,err,"Creating training logs returned an error.",t);
+    for i,exp:=range([]float64{0.9,0.5}) {
+        tl,err,_:=db.Read(&testDB,db.TrainingLog{Id: ids[i]},
+            algo.GenFilter(false,"Id"),
+        ).Nth(0);
+        test.BasicTest(nil,err,"Reading a created training log returned an error.",t);
+        test.BasicTest(exp,tl.Intensity,"The intensity was not correct.",t);
+    }
+    _,err=CreateTrainingLogs(&testDB,db.TrainingLog{
+        ClientID: 1, ExerciseID: -1, RotationID: 1, DatePerformed: day,
+        Weight: 315, Sets: 1, Reps: 1, Effort: 10,
+    });
+    if !IsNoMaxForIntensity(err) {
+        test.FormatError(NoMaxForIntensity(""),err,
+            "Creating a log without a max did not return an error.",t,
+        );
+    }
+}
@@ -0,0 +1,14 @@
+package oneRepMax;
+
+func latestMaxQuery() string {
+    return `SELECT Weight/Intensity
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.ExerciseID=$2
+            AND TrainingLog.DatePerformed<$3
+            AND TrainingLog.Intensity>0
+        ORDER BY
+            DatePerformed DESC,
+            Id DESC
+        LIMIT 1;`;
+}
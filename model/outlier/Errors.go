@@ -0,0 +1,9 @@
+package outlier;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var NoFlagForTrainingLog,IsNoFlagForTrainingLog=customerr.ErrorFactory(
+    "The training log has not been flagged as an outlier.",
+);
@@ -0,0 +1,148 @@
+package outlier
+
+import (
+	"database/sql"
+	"fmt"
+	stdMath "math"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/util/algo/iter"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The z-score above which a training log is flagged when no other threshold is
+//given.
+const DefaultThreshold float64=3.0;
+
+//The training logs that were flagged by Detect. Checked is the number of
+//training logs that had a model state to compare against, Skipped is the
+//number that did not.
+type Report struct {
+    StateGeneratorID int;
+    PotentialSurfaceID int;
+    Start time.Time;
+    End time.Time;
+    Threshold float64;
+    Checked int;
+    Skipped int;
+    Flagged []db.OutlierFlag;
+};
+
+//Compares every training log of the client in the date range (inclusive)
+//against the closest model state that is strictly before it. The model state
+//is taken from before the training log so that a bad training log can not pull
+//the fit towards itself and hide its own residual. The residual is the logged
+//intensity minus the predicted intensity and the z-score is the residual
+//divided by the standard error (Sigma) of the model state. Training logs with
+//an absolute z-score greater than the threshold are flagged.
+//Model states with a standard error of zero can not be used to standardize a
+//residual, training logs that only have those model states are skipped.
+//Nothing is written to the database, use Save to keep the flags.
+func Detect(
+        d *db.DB,
+        c db.Client,
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        start time.Time,
+        end time.Time,
+        threshold float64) (Report,error) {
+    rv:=Report{
+        StateGeneratorID: int(sg),
+        PotentialSurfaceID: int(surf),
+        Start: start,
+        End: end,
+        Threshold: threshold,
+        Flagged: []db.OutlierFlag{},
+    };
+    if end.Before(start) {
+        return rv,customerr.InvalidValue("end date is before start date");
+    } else if threshold<=0 {
+        return rv,customerr.InvalidValue("threshold <= 0, should be >0");
+    }
+    calc,err:=potSurf.CalculationsFromSurfaceId(surf);
+    if err!=nil {
+        return rv,err;
+    }
+    err=db.CustomReadQuery[db.TrainingLog](d,trainingLogsInRangeQuery(),[]any{
+        c.Id,start,end,
+    }).ForEach(func(index int, val *db.TrainingLog) (iter.IteratorFeedback, error) {
+        ms,err,found:=db.CustomReadQuery[db.ModelState](d,
+            modelStateBeforeTrainingLogQuery(),[]any{
+                val.ClientID,val.ExerciseID,sg,surf,val.DatePerformed,
+        }).Nth(0);
+        if err==sql.ErrNoRows || (err==nil && (!found || !(ms.Sigma>0))) {
+            rv.Skipped++;
+            return iter.Continue,nil;
+        } else if err!=nil {
+            return iter.Break,err;
+        }
+        histCalc,err:=potSurf.CalculationsWithHistory(d,calc,val);
+        if err!=nil {
+            return iter.Break,err;
+        }
+        pred,err:=potSurf.IntensityVar.Solve(histCalc,ms,val);
+        if potSurf.IsNoSolution(err) {
+            rv.Skipped++;
+            return iter.Continue,nil;
+        } else if err!=nil {
+            return iter.Break,err;
+        }
+        rv.Checked++;
+        if f:=newFlag(ms,val,pred); stdMath.Abs(f.ZScore)>threshold {
+            rv.Flagged=append(rv.Flagged,f);
+        }
+        return iter.Continue,nil;
+    });
+    if err==sql.ErrNoRows {
+        return rv,nil;
+    }
+    return rv,err;
+}
+
+func newFlag(ms *db.ModelState, tl *db.TrainingLog, pred float64) db.OutlierFlag {
+    residual:=tl.Intensity-pred;
+    return db.OutlierFlag{
+        TrainingLogID: tl.Id,
+        StateGeneratorID: ms.StateGeneratorID,
+        PotentialSurfaceID: ms.PotentialSurfaceID,
+        Residual: residual,
+        ZScore: residual/ms.Sigma,
+    };
+}
+
+//Saves the flagged training logs. A training log that was already flagged has
+//its residual and z-score replaced but keeps its excluded value.
+func Save(d *db.DB, flags []db.OutlierFlag) error {
+    for _,f:=range(flags) {
+        if _,err:=db.CustomInsertQuery(d,saveFlagQuery(),[]any{
+            f.TrainingLogID,f.StateGeneratorID,f.PotentialSurfaceID,
+            f.Residual,f.ZScore,
+        }); err!=nil {
+            return err;
+        }
+    }
+    return nil;
+}
+
+//Sets whether or not a flagged training log is used by the state generators.
+//The training log is never changed or deleted, so excluding it can be undone
+//by setting excluded to false. Changing the value marks the model states and
+//predictions that depended on the training log as stale.
+func SetExcluded(d *db.DB, trainingLogID int, excluded bool) error {
+    n,err:=db.Update(d,
+        db.OutlierFlag{TrainingLogID: trainingLogID},
+        algo.GenFilter(false,"TrainingLogID"),
+        db.OutlierFlag{Excluded: excluded},
+        algo.GenFilter(false,"Excluded"),
+    );
+    if err==nil && n==0 {
+        return NoFlagForTrainingLog(
+            fmt.Sprintf("Training log id: %d",trainingLogID),
+        );
+    }
+    return err;
+}
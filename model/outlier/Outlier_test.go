@@ -0,0 +1,118 @@
+package outlier
+
+import (
+	stdMath "math"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestNewFlag(t *testing.T){
+    f:=newFlag(
+        &db.ModelState{StateGeneratorID: 1, PotentialSurfaceID: 2, Sigma: 0.05},
+        &db.TrainingLog{Id: 3, Intensity: 0.7},0.8,
+    );
+    test.BasicTest(3,f.TrainingLogID,"The training log id was not set.",t);
+    test.BasicTest(1,f.StateGeneratorID,"The state generator id was not set.",t);
+    test.BasicTest(2,f.PotentialSurfaceID,"The surface id was not set.",t);
+    test.BasicTest(true,stdMath.Abs(f.Residual+0.1)<1e-9,
+        "The residual was not correct.",t,
+    );
+    test.BasicTest(true,stdMath.Abs(f.ZScore+2)<1e-9,
+        "The z-score was not correct.",t,
+    );
+    test.BasicTest(false,f.Excluded,"A new flag was excluded.",t);
+}
+
+func TestDetectInvalidArguments(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    _,err:=Detect(&testDB,c,stateGen.SlidingWindowStateGenId,
+        potSurf.BasicSurfaceId,time.Now(),time.Now().AddDate(0,0,-1),
+        DefaultThreshold,
+    );
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "An invalid date range did not return the correct error.",t,
+        );
+    }
+    _,err=Detect(&testDB,c,stateGen.SlidingWindowStateGenId,
+        potSurf.BasicSurfaceId,time.Now(),time.Now(),0,
+    );
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A threshold of zero did not return the correct error.",t,
+        );
+    }
+}
+
+func TestDetectSaveAndExclude(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    start:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    end:=time.Date(2022,time.Month(9),10,0,0,0,0,time.UTC);
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    sw.GenerateClientModelStates(&testDB,c,start.AddDate(0,0,-30),
+        func() []potSurf.Surface {
+            return []potSurf.Surface{potSurf.NewBasicSurface().ToGenericSurf()};
+        },
+    );
+    res,err:=Detect(&testDB,c,stateGen.SlidingWindowStateGenId,
+        potSurf.BasicSurfaceId,start,end,1e-6,
+    );
+    test.BasicTest(nil,err,"Detecting outliers returned an error.",t);
+    test.BasicTest(true,res.Checked>0,"No training logs were checked.",t);
+    test.BasicTest(true,len(res.Flagged)>0,
+        "A tiny threshold did not flag any training logs.",t,
+    );
+    test.BasicTest(true,len(res.Flagged)<=res.Checked,
+        "More training logs were flagged than were checked.",t,
+    );
+    strict,err:=Detect(&testDB,c,stateGen.SlidingWindowStateGenId,
+        potSurf.BasicSurfaceId,start,end,DefaultThreshold,
+    );
+    test.BasicTest(nil,err,"Detecting outliers returned an error.",t);
+    test.BasicTest(true,len(strict.Flagged)<=len(res.Flagged),
+        "A larger threshold flagged more training logs.",t,
+    );
+
+    err=Save(&testDB,res.Flagged);
+    test.BasicTest(nil,err,"Saving the flags returned an error.",t);
+    if len(res.Flagged)==0 {
+        return;
+    }
+    id:=res.Flagged[0].TrainingLogID;
+    err=SetExcluded(&testDB,id,true);
+    test.BasicTest(nil,err,"Excluding a training log returned an error.",t);
+    err=Save(&testDB,res.Flagged);
+    test.BasicTest(nil,err,"Saving the flags again returned an error.",t);
+    flag,err,_:=db.Read(&testDB,db.OutlierFlag{TrainingLogID: id},
+        algo.GenFilter(false,"TrainingLogID"),
+    ).Nth(0);
+    test.BasicTest(nil,err,"Reading the flag returned an error.",t);
+    test.BasicTest(true,flag.Excluded,
+        "Saving the flags again cleared the excluded value.",t,
+    );
+    _,err,found:=db.Read(&testDB,db.TrainingLog{Id: id},db.OnlyIDFilter).Nth(0);
+    test.BasicTest(nil,err,"Reading the training log returned an error.",t);
+    test.BasicTest(true,found,"Excluding a training log deleted it.",t);
+    err=SetExcluded(&testDB,id,false);
+    test.BasicTest(nil,err,"Including a training log returned an error.",t);
+}
+
+func TestSetExcludedNoFlag(t *testing.T){
+    err:=SetExcluded(&testDB,-1,true);
+    if !IsNoFlagForTrainingLog(err) {
+        test.FormatError(NoFlagForTrainingLog(""),err,
+            "Excluding a log without a flag did not return the correct error.",t,
+        );
+    }
+}
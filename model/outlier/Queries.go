@@ -0,0 +1,38 @@
+package outlier
+
+func trainingLogsInRangeQuery() string {
+    return `SELECT *
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.DatePerformed>=$2
+            AND TrainingLog.DatePerformed<=$3
+            AND TrainingLog.Intensity>0
+        ORDER BY
+            DatePerformed ASC,
+            Id ASC;`;
+}
+
+func modelStateBeforeTrainingLogQuery() string {
+    return `SELECT *
+        FROM ModelState
+        WHERE ModelState.ClientID=$1
+            AND ModelState.ExerciseID=$2
+            AND ModelState.StateGeneratorID=$3
+            AND ModelState.PotentialSurfaceID=$4
+            AND ModelState.Date<$5
+        ORDER BY Date DESC
+        LIMIT 1;`;
+}
+
+//Excluded is left out of the update so that saving a new report does not
+//clear the decisions that were already made about which logs to exclude.
+func saveFlagQuery() string {
+    return `INSERT INTO OutlierFlag(
+            TrainingLogID,StateGeneratorID,PotentialSurfaceID,Residual,ZScore
+        ) VALUES ($1,$2,$3,$4,$5)
+        ON CONFLICT(TrainingLogID) DO UPDATE SET
+            StateGeneratorID=EXCLUDED.StateGeneratorID,
+            PotentialSurfaceID=EXCLUDED.PotentialSurfaceID,
+            Residual=EXCLUDED.Residual,
+            ZScore=EXCLUDED.ZScore;`;
+}
@@ -0,0 +1,124 @@
+package plates
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/util/algo/iter"
+)
+
+//Used as the number of pairs of a plate when there is no limit to how many of
+//the plate are available.
+const Unlimited int=-1;
+
+//The plates of a single weight that are available. The weight is the weight
+//of one plate.
+type Plate struct {
+    Weight float64;
+    Pairs int;
+};
+
+//Describes the equipment that is available to load a barbell. Plates are
+//loaded in pairs, one on each side of the bar, and the collars are put on the
+//bar whenever there are plates on it. The weights are all in the equipments
+//unit, the collar weight is the weight of both collars.
+type Equipment struct {
+    bar float64;
+    collars float64;
+    plates []Plate;
+    unit db.Unit;
+};
+
+func NewEquipment(
+        bar float64,
+        collars float64,
+        plates []Plate,
+        u db.Unit) (Equipment,error) {
+    rv:=Equipment{
+        bar: bar, collars: collars, plates: append([]Plate{},plates...), unit: u,
+    };
+    if u!=db.Kilograms && u!=db.Pounds {
+        return rv,db.InvalidUnit(fmt.Sprintf("Unit: %d",u));
+    } else if bar<0 {
+        return rv,InvalidEquipment("bar weight < 0, should be >=0");
+    } else if collars<0 {
+        return rv,InvalidEquipment("collar weight < 0, should be >=0");
+    } else if len(plates)==0 {
+        return rv,InvalidEquipment("at least one plate size is needed");
+    }
+    for _,p:=range(rv.plates) {
+        if toTicks(p.Weight)<=0 {
+            return rv,InvalidEquipment(fmt.Sprintf(
+                "plate weight %v should be >=%v",p.Weight,1/float64(ticksPerUnit),
+            ));
+        } else if p.Pairs<=0 && p.Pairs!=Unlimited {
+            return rv,InvalidEquipment(fmt.Sprintf(
+                "plate %v has %d pairs, should be >0",p.Weight,p.Pairs,
+            ));
+        }
+    }
+    sort.SliceStable(rv.plates,func(i int, j int) bool {
+        return rv.plates[i].Weight>rv.plates[j].Weight;
+    });
+    return rv,nil;
+}
+
+//Returns the clients equipment using the bar and collars with the given names
+//and all of the clients plates. An empty collar name means collars are not
+//used. The weights are converted to the given unit. If the bar or collars do
+//not exist sql.ErrNoRows is returned.
+func GetEquipment(
+        d *db.DB,
+        clientID int,
+        bar string,
+        collar string,
+        u db.Unit) (Equipment,error) {
+    b,err,found:=db.Read(d,db.Bar{ClientID: clientID, Name: bar},
+        algo.GenFilter(false,"ClientID","Name"),
+    ).Nth(0);
+    if err==nil && !found {
+        err=sql.ErrNoRows;
+    }
+    if err!=nil {
+        return Equipment{},err;
+    }
+    barRow,err:=db.InUnit(*b,u);
+    if err!=nil {
+        return Equipment{},err;
+    }
+    collarRow:=db.Collar{};
+    if collar!="" {
+        c,err,found:=db.Read(d,db.Collar{ClientID: clientID, Name: collar},
+            algo.GenFilter(false,"ClientID","Name"),
+        ).Nth(0);
+        if err==nil && !found {
+            err=sql.ErrNoRows;
+        }
+        if err!=nil {
+            return Equipment{},err;
+        }
+        if collarRow,err=db.InUnit(*c,u); err!=nil {
+            return Equipment{},err;
+        }
+    }
+    plates:=[]Plate{};
+    err=db.Read(d,db.PlatePair{ClientID: clientID},
+        algo.GenFilter(false,"ClientID"),
+    ).ForEach(func(index int, val *db.PlatePair) (iter.IteratorFeedback,error) {
+        p,err:=db.InUnit(*val,u);
+        plates=append(plates,Plate{Weight: p.Weight, Pairs: p.Pairs});
+        return iter.Continue,err;
+    });
+    if err!=nil {
+        return Equipment{},err;
+    }
+    return NewEquipment(barRow.Weight,collarRow.Weight,plates,u);
+}
+
+func (e Equipment)Bar() float64 { return e.bar; }
+func (e Equipment)Collars() float64 { return e.collars; }
+func (e Equipment)Unit() db.Unit { return e.unit; }
+func (e Equipment)Plates() []Plate { return append([]Plate{},e.plates...); }
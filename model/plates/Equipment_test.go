@@ -0,0 +1,57 @@
+package plates
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestNewEquipmentInvalid(t *testing.T){
+    for _,v:=range([]struct{ bar float64; collars float64; plates []Plate }{
+        {bar: -1, plates: []Plate{{Weight: 2.5, Pairs: 1}}},
+        {bar: 20, collars: -1, plates: []Plate{{Weight: 2.5, Pairs: 1}}},
+        {bar: 20, plates: []Plate{}},
+        {bar: 20, plates: []Plate{{Weight: 0, Pairs: 1}}},
+        {bar: 20, plates: []Plate{{Weight: 2.5, Pairs: 0}}},
+        {bar: 20, plates: []Plate{{Weight: 2.5, Pairs: -2}}},
+    }) {
+        if _,err:=NewEquipment(
+            v.bar,v.collars,v.plates,db.Kilograms,
+        ); !IsInvalidEquipment(err) {
+            test.FormatError(InvalidEquipment(""),err,
+                "Creating invalid equipment did not return an error.",t,
+            );
+        }
+    }
+    _,err:=NewEquipment(20,0,[]Plate{{Weight: 2.5, Pairs: 1}},db.Unit(5));
+    if !db.IsInvalidUnit(err) {
+        test.FormatError(db.InvalidUnit(""),err,
+            "Creating equipment with an invalid unit did not return an error.",t,
+        );
+    }
+}
+
+func TestGetEquipment(t *testing.T){
+    db.Create(&testDB,db.Bar{ClientID: 1, Name: "Power Bar", Weight: 45, Unit: int(db.Pounds)});
+    db.Create(&testDB,db.Collar{ClientID: 1, Name: "Spring", Weight: 0.5});
+    db.Create(&testDB,
+        db.PlatePair{ClientID: 1, Weight: 20, Pairs: 4},
+        db.PlatePair{ClientID: 1, Weight: 2.5, Pairs: 1},
+    );
+    e,err:=GetEquipment(&testDB,1,"Power Bar","Spring",db.Kilograms);
+    test.BasicTest(nil,err,"Reading equipment returned an error.",t);
+    test.BasicTest(true,e.Bar()-45*db.KgPerLb<1e-6 && 45*db.KgPerLb-e.Bar()<1e-6,
+        "The bar was not converted to the requested unit.",t,
+    );
+    test.BasicTest(0.5,e.Collars(),"The collars were not read.",t);
+    test.BasicTest(2,len(e.Plates()),"The plates were not read.",t);
+    test.BasicTest(20.0,e.Plates()[0].Weight,"The plates were not sorted.",t);
+    e,err=GetEquipment(&testDB,1,"Power Bar","",db.Pounds);
+    test.BasicTest(nil,err,"Reading equipment without collars errored.",t);
+    test.BasicTest(0.0,e.Collars(),"Collars were used when none were given.",t);
+    _,err=GetEquipment(&testDB,1,"Missing","",db.Pounds);
+    if err==nil {
+        test.FormatError("an error",err,"Reading a missing bar did not error.",t);
+    }
+}
@@ -0,0 +1,9 @@
+package plates;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var InvalidEquipment,IsInvalidEquipment=customerr.ErrorFactory(
+    "The equipment cannot be used to load a bar.",
+);
@@ -0,0 +1,160 @@
+package plates
+
+import (
+	stdMath "math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Weights are converted to an integer number of ticks so that the achievable
+//loads can be found without floating point error. A tick is 0.01 of the
+//equipments unit, which is smaller than any standard plate.
+const ticksPerUnit int=100;
+
+//A load that can be put on the bar. The weight is the total weight, including
+//the bar and collars, and the plates are the plates that go on each side of
+//the bar, heaviest plate first. Both are in the equipments unit.
+type Load struct {
+    Weight float64;
+    PerSide []float64;
+    Collars bool;
+};
+
+//Returns the plates that go on each side of the bar as a space separated list,
+//for example '25 25 10 1.25'. An empty bar is returned as an empty string.
+func (l Load)PlatesString() string {
+    rv:=make([]string,len(l.PerSide));
+    for i,p:=range(l.PerSide) {
+        rv[i]=strconv.FormatFloat(p,'f',-1,64);
+    }
+    return strings.Join(rv," ");
+}
+
+func toTicks(w float64) int {
+    return int(stdMath.Round(w*float64(ticksPerUnit)));
+}
+
+func fromTicks(t int) float64 {
+    return float64(t)/float64(ticksPerUnit);
+}
+
+//The achievable weights for one side of the bar, up to a limit. For every
+//achievable weight the fewest number of plates that make the weight is used.
+//The fewest number of plates is -1 for weights that are not achievable.
+type sides struct {
+    fewest []int;
+    plate []int;
+};
+
+func (e Equipment)sides(limit int) sides {
+    rv:=sides{fewest: make([]int,limit+1), plate: make([]int,limit+1)};
+    for i,_:=range(rv.fewest) {
+        rv.fewest[i]=-1;
+    }
+    rv.fewest[0]=0;
+    update:=func(s int, w int, p int) {
+        if rv.fewest[s-w]>=0 && (rv.fewest[s]<0 || rv.fewest[s-w]+1<rv.fewest[s]) {
+            rv.fewest[s]=rv.fewest[s-w]+1;
+            rv.plate[s]=p;
+        }
+    };
+    for p,plate:=range(e.plates) {
+        w:=toTicks(plate.Weight);
+        if plate.Pairs==Unlimited {
+            for s:=w; s<=limit; s++ {
+                update(s,w,p);
+            }
+            continue;
+        }
+        for i:=0; i<plate.Pairs; i++ {
+            for s:=limit; s>=w; s-- {
+                update(s,w,p);
+            }
+        }
+    }
+    return rv;
+}
+
+func (e Equipment)load(s sides, side int) Load {
+    rv:=Load{Weight: e.bar, PerSide: []float64{}};
+    if side==0 {
+        return rv;
+    }
+    rv.Weight=e.bar+e.collars+2*fromTicks(side);
+    rv.Collars=e.collars>0;
+    for ; side>0; side-=toTicks(e.plates[s.plate[side]].Weight) {
+        rv.PerSide=append(rv.PerSide,e.plates[s.plate[side]].Weight);
+    }
+    sort.Sort(sort.Reverse(sort.Float64Slice(rv.PerSide)));
+    return rv;
+}
+
+//Returns the most ticks that can go on one side of the bar without going over
+//the given weight. The small constant keeps floating point error from dropping
+//a weight that is already achievable down a tick.
+func (e Equipment)sideTicks(weight float64) int {
+    return int(stdMath.Floor((weight-e.bar-e.collars)/2*float64(ticksPerUnit)+1e-6));
+}
+
+//Returns the heaviest achievable load that is <= the given weight. Weights
+//lighter than the bar with collars and the lightest plates are rounded down to
+//the empty bar, and weights lighter than the bar are rounded to the bar.
+func (e Equipment)RoundDown(weight float64) Load {
+    limit:=e.sideTicks(weight);
+    if limit<=0 {
+        return e.load(sides{},0);
+    }
+    s:=e.sides(limit);
+    for side:=limit; side>0; side-- {
+        if s.fewest[side]>0 {
+            return e.load(s,side);
+        }
+    }
+    return e.load(s,0);
+}
+
+//Returns the achievable load that is closest to the given weight. When two
+//loads are equally close the lighter one is returned. Weights that are heavier
+//than the equipment can make are rounded down to the heaviest achievable load.
+func (e Equipment)Nearest(weight float64) Load {
+    limit:=e.sideTicks(weight);
+    if limit<0 {
+        limit=0;
+    }
+    s:=e.sides(limit+toTicks(e.plates[0].Weight));
+    down:=e.load(s,0);
+    for side:=limit; side>0; side-- {
+        if s.fewest[side]>0 {
+            down=e.load(s,side);
+            break;
+        }
+    }
+    for side:=limit; side<len(s.fewest); side++ {
+        if side==0 || s.fewest[side]<=0 {
+            continue;
+        }
+        up:=e.load(s,side);
+        if up.Weight<weight-1e-9 {
+            continue;
+        }
+        if up.Weight-weight<weight-down.Weight-1e-9 {
+            return up;
+        }
+        break;
+    }
+    return down;
+}
+
+//The same as RoundDown except the weight is given and returned in kilograms,
+//which is the unit weights are stored in.
+func (e Equipment)RoundDownKg(weight float64) float64 {
+    return e.unit.ToKg(e.RoundDown(e.unit.FromKg(weight)).Weight);
+}
+
+//Returns the weight, which is in kilograms, in the equipments unit rounded
+//down to an achievable weight. This is the weight that should be shown to a
+//client that uses the equipment.
+func (e Equipment)DisplayWeight(weight float64) float64 {
+    return e.RoundDown(e.unit.FromKg(weight)).Weight;
+}
@@ -0,0 +1,79 @@
+package plates
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func testEquipment() Equipment {
+    e,_:=NewEquipment(20,0,[]Plate{
+        {Weight: 1.25, Pairs: 1},
+        {Weight: 25, Pairs: 2},
+        {Weight: 10, Pairs: 1},
+        {Weight: 2.5, Pairs: 1},
+        {Weight: 5, Pairs: 1},
+    },db.Kilograms);
+    return e;
+}
+
+func TestRoundDown(t *testing.T){
+    e:=testEquipment();
+    for w,exp:=range(map[float64]float64{
+        10: 20, 20: 20, 22: 20, 22.5: 22.5, 147.83: 147.5, 100: 100, 300: 157.5,
+    }) {
+        test.BasicTest(exp,e.RoundDown(w).Weight,"Rounding down was not correct.",t);
+    }
+    l:=e.RoundDown(147.83);
+    test.SlicesMatch[float64]([]float64{25,25,10,2.5,1.25},l.PerSide,t);
+    test.BasicTest("25 25 10 2.5 1.25",l.PlatesString(),
+        "The plates were not formatted correctly.",t,
+    );
+    test.BasicTest(0,len(e.RoundDown(21).PerSide),
+        "The empty bar had plates on it.",t,
+    );
+}
+
+func TestRoundDownLimitedPlates(t *testing.T){
+    e,_:=NewEquipment(20,0,[]Plate{{Weight: 20, Pairs: 1},{Weight: 5, Pairs: 1}},db.Kilograms);
+    test.BasicTest(70.0,e.RoundDown(75).Weight,
+        "A load that needs more plates than are available was returned.",t,
+    );
+    test.BasicTest(60.0,e.RoundDown(69).Weight,"Rounding down was not correct.",t);
+    test.BasicTest(30.0,e.RoundDown(59).Weight,
+        "A load that skips an unavailable weight was not correct.",t,
+    );
+}
+
+func TestNearest(t *testing.T){
+    e:=testEquipment();
+    for w,exp:=range(map[float64]float64{
+        147.83: 147.5, 148.9: 150, 148.75: 147.5, 19: 20, 21.3: 22.5, 400: 157.5,
+    }) {
+        test.BasicTest(exp,e.Nearest(w).Weight,"The nearest load was not correct.",t);
+    }
+}
+
+func TestCollars(t *testing.T){
+    e,_:=NewEquipment(20,5,[]Plate{{Weight: 2.5, Pairs: Unlimited}},db.Kilograms);
+    l:=e.RoundDown(32);
+    test.BasicTest(30.0,l.Weight,"The collars were not included.",t);
+    test.BasicTest(true,l.Collars,"The collars were not used.",t);
+    test.SlicesMatch[float64]([]float64{2.5},l.PerSide,t);
+    l=e.RoundDown(29);
+    test.BasicTest(20.0,l.Weight,"The collars were used without plates.",t);
+    test.BasicTest(false,l.Collars,"The collars were used without plates.",t);
+}
+
+func TestEquipmentUnits(t *testing.T){
+    e,_:=NewEquipment(45,0,[]Plate{
+        {Weight: 45, Pairs: Unlimited},{Weight: 2.5, Pairs: Unlimited},
+    },db.Pounds);
+    test.BasicTest(315.0,e.DisplayWeight(db.Pounds.ToKg(317)),
+        "The displayed weight was not rounded in pounds.",t,
+    );
+    test.BasicTest(db.Pounds.ToKg(315),e.RoundDownKg(db.Pounds.ToKg(317)),
+        "Rounding in kilograms did not use pounds.",t,
+    );
+}
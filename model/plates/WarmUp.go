@@ -0,0 +1,79 @@
+package plates
+
+import (
+	"fmt"
+)
+
+//The number of reps performed with the empty bar at the start of a warm up.
+const DefaultEmptyBarReps int=10;
+
+//A single step of a warm up. The percent is the percent of the working weight
+//that is used, given as a fraction.
+type WarmUpStep struct {
+    Percent float64;
+    Reps int;
+};
+
+//A single warm up set.
+type WarmUpSet struct {
+    Load Load;
+    Reps int;
+};
+
+//The warm up that is used when no warm up steps are given.
+func DefaultWarmUp() []WarmUpStep {
+    return []WarmUpStep{
+        {Percent: 0.4, Reps: 5},
+        {Percent: 0.6, Reps: 3},
+        {Percent: 0.8, Reps: 2},
+    };
+}
+
+func validWarmUp(steps []WarmUpStep) error {
+    for i,s:=range(steps) {
+        if s.Percent<=0 || s.Percent>=1 {
+            return InvalidEquipment(fmt.Sprintf(
+                "warm up percent %v is not in (0,1)",s.Percent,
+            ));
+        } else if s.Reps<=0 {
+            return InvalidEquipment(fmt.Sprintf(
+                "warm up reps %d should be >0",s.Reps,
+            ));
+        } else if i>0 && s.Percent<=steps[i-1].Percent {
+            return InvalidEquipment("warm up percents should be increasing");
+        }
+    }
+    return nil;
+}
+
+//Returns the warm up sets that lead up to the working weight, which is in the
+//equipments unit. The warm up starts with the empty bar and then each step is
+//loaded with the achievable load that is closest to its percent of the working
+//weight. Steps that would not be heavier than the previous set, or that would
+//not be lighter than the working weight, are skipped. If no steps are given
+//the default warm up is used.
+func (e Equipment)WarmUp(working float64, steps []WarmUpStep) ([]WarmUpSet,error) {
+    rv:=[]WarmUpSet{};
+    if len(steps)==0 {
+        steps=DefaultWarmUp();
+    }
+    if err:=validWarmUp(steps); err!=nil {
+        return rv,err;
+    }
+    workingLoad:=e.RoundDown(working);
+    if workingLoad.Weight<=e.bar {
+        return rv,nil;
+    }
+    rv=append(rv,WarmUpSet{
+        Load: e.RoundDown(e.bar), Reps: DefaultEmptyBarReps,
+    });
+    for _,s:=range(steps) {
+        l:=e.Nearest(s.Percent*workingLoad.Weight);
+        if l.Weight<=rv[len(rv)-1].Load.Weight+1e-9 ||
+            l.Weight>=workingLoad.Weight-1e-9 {
+            continue;
+        }
+        rv=append(rv,WarmUpSet{Load: l, Reps: s.Reps});
+    }
+    return rv,nil;
+}
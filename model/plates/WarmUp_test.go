@@ -0,0 +1,43 @@
+package plates
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestWarmUp(t *testing.T){
+    e,_:=NewEquipment(20,0,[]Plate{
+        {Weight: 25, Pairs: Unlimited},
+        {Weight: 10, Pairs: Unlimited},
+        {Weight: 5, Pairs: Unlimited},
+        {Weight: 2.5, Pairs: Unlimited},
+        {Weight: 1.25, Pairs: Unlimited},
+    },db.Kilograms);
+    sets,err:=e.WarmUp(150,nil);
+    test.BasicTest(nil,err,"Creating a warm up returned an error.",t);
+    test.BasicTest(4,len(sets),"The warm up had the wrong number of sets.",t);
+    for i,exp:=range([]struct{ w float64; reps int }{
+        {20,10},{60,5},{90,3},{120,2},
+    }) {
+        test.BasicTest(exp.w,sets[i].Load.Weight,"The warm up weight was wrong.",t);
+        test.BasicTest(exp.reps,sets[i].Reps,"The warm up reps were wrong.",t);
+    }
+    sets,_=e.WarmUp(30,nil);
+    test.BasicTest(2,len(sets),"Repeated warm up weights were not skipped.",t);
+    sets,_=e.WarmUp(20,nil);
+    test.BasicTest(0,len(sets),"The empty bar was warmed up for.",t);
+    for _,steps:=range([][]WarmUpStep{
+        {{Percent: 0, Reps: 5}},
+        {{Percent: 1, Reps: 5}},
+        {{Percent: 0.5, Reps: 0}},
+        {{Percent: 0.6, Reps: 5},{Percent: 0.5, Reps: 3}},
+    }) {
+        if _,err:=e.WarmUp(150,steps); !IsInvalidEquipment(err) {
+            test.FormatError(InvalidEquipment(""),err,
+                "An invalid warm up did not return an error.",t,
+            );
+        }
+    }
+}
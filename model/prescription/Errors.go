@@ -0,0 +1,21 @@
+package prescription;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var NoTargetForExercise,IsNoTargetForExercise=customerr.ErrorFactory(
+    "A session contained an exercise that does not have a target.",
+);
+
+var NoFeasiblePrescription,IsNoFeasiblePrescription=customerr.ErrorFactory(
+    "No combination of sets, reps, and load satisfied the target effort range.",
+);
+
+var InvalidTemplate,IsInvalidTemplate=customerr.ErrorFactory(
+    "The periodization template is not valid.",
+);
+
+var NoModelStateForExercise,IsNoModelStateForExercise=customerr.ErrorFactory(
+    "An effort based slot needs a model state for its exercise.",
+);
@@ -0,0 +1,35 @@
+package prescription
+
+import (
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model/plates"
+)
+
+//Returns equipment with the given bar and an unlimited number of each plate
+//and no collars. This describes a commercial gym, where there are always
+//enough plates to load the bar. The bar and plate weights are in kilograms.
+func NewLoading(barWeight float64, plateWeights []float64) (plates.Equipment,error) {
+    return NewLoadingInUnit(barWeight,plateWeights,db.Kilograms);
+}
+
+//The same as NewLoading except the bar and plate weights are in the given
+//unit.
+func NewLoadingInUnit(
+        barWeight float64,
+        plateWeights []float64,
+        u db.Unit) (plates.Equipment,error) {
+    p:=make([]plates.Plate,len(plateWeights));
+    for i,w:=range(plateWeights) {
+        p[i]=plates.Plate{Weight: w, Pairs: plates.Unlimited};
+    }
+    return plates.NewEquipment(barWeight,0,p,u);
+}
+
+//Returns the standard barbell and plates for the unit. A 20kg bar with
+//25-1.25kg plates, or a 45lb bar with 45-2.5lb plates.
+func StandardLoading(u db.Unit) (plates.Equipment,error) {
+    if u==db.Pounds {
+        return NewLoadingInUnit(45,[]float64{45,35,25,10,5,2.5},u);
+    }
+    return NewLoadingInUnit(20,[]float64{25,20,15,10,5,2.5,1.25},u);
+}
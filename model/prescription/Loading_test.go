@@ -0,0 +1,59 @@
+package prescription
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model/plates"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestNewLoadingInvalid(t *testing.T){
+    for _,v:=range([]struct{ bar float64; plates []float64 }{
+        {bar: -1, plates: []float64{2.5}},
+        {bar: 20, plates: []float64{}},
+        {bar: 20, plates: []float64{0,2.5}},
+    }) {
+        if _,err:=NewLoading(v.bar,v.plates); !plates.IsInvalidEquipment(err) {
+            test.FormatError(plates.InvalidEquipment(""),err,
+                "Creating invalid loading did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestLoadingUnlimitedPlates(t *testing.T){
+    l,err:=NewLoading(20,[]float64{1.25,2.5});
+    test.BasicTest(nil,err,"Creating loading returned an error.",t);
+    for _,p:=range(l.Plates()) {
+        test.BasicTest(plates.Unlimited,p.Pairs,"The plates were limited.",t);
+    }
+    test.BasicTest(0.0,l.Collars(),"The loading had collars.",t);
+    test.BasicTest(300.0,l.RoundDown(300).Weight,
+        "The loading ran out of plates.",t,
+    );
+    test.BasicTest(142.5,l.RoundDown(143).Weight,
+        "Rounding down was not correct.",t,
+    );
+}
+
+func TestStandardLoading(t *testing.T){
+    kg,err:=StandardLoading(db.Kilograms);
+    test.BasicTest(nil,err,"Creating the kg loading returned an error.",t);
+    test.BasicTest(20.0,kg.Bar(),"The kg bar weight was not correct.",t);
+    test.SlicesMatch[float64](
+        []float64{25,1.25},kg.RoundDown(72.5).PerSide,t,
+    );
+    lb,err:=StandardLoading(db.Pounds);
+    test.BasicTest(nil,err,"Creating the lb loading returned an error.",t);
+    test.BasicTest(db.Pounds,lb.Unit(),"The lb loading had the wrong unit.",t);
+    test.BasicTest(45.0,lb.Bar(),"The lb bar weight was not correct.",t);
+    test.BasicTest(315.0,lb.DisplayWeight(db.Pounds.ToKg(317)),
+        "The displayed weight was not rounded in pounds.",t,
+    );
+    if _,err:=NewLoadingInUnit(20,[]float64{2.5},db.Unit(5)); !db.IsInvalidUnit(err) {
+        test.FormatError(db.InvalidUnit(""),err,
+            "Creating a loading with an invalid unit did not return an error.",t,
+        );
+    }
+}
@@ -0,0 +1,314 @@
+package prescription
+
+import (
+	"fmt"
+	stdMath "math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo/iter"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/io/csv"
+	"github.com/barbell-math/engine/model/oneRepMax"
+	"github.com/barbell-math/engine/model/plates"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The allowed ranges (inclusive) for a single exercise.
+type Target struct {
+    ExerciseID int;
+    Effort dataStruct.Pair[float64,float64];
+    Sets dataStruct.Pair[int,int];
+    Reps dataStruct.Pair[int,int];
+};
+
+//A session slot in the week. The exercises are performed in the order they
+//are given.
+type Session struct {
+    Date time.Time;
+    ExerciseIDs []int;
+};
+
+type Prescriber struct {
+    sg stateGen.StateGeneratorId;
+    surf potSurf.PotentialSurfaceId;
+    loading plates.Equipment;
+};
+
+//Holds everything that is needed to prescribe an exercise that does not change
+//between sessions.
+type exerciseState struct {
+    target Target;
+    ms db.ModelState;
+    calc potSurf.Calculations;
+    max float64;
+};
+
+//Loads are rounded down to weights that can be made with the equipment. See
+//NewLoading for equipment that has an unlimited number of each plate.
+func NewPrescriber(
+        sg stateGen.StateGeneratorId,
+        surf potSurf.PotentialSurfaceId,
+        e plates.Equipment) (Prescriber,error) {
+    rv:=Prescriber{sg: sg, surf: surf, loading: e};
+    if _,err:=potSurf.Lookup(surf); err!=nil {
+        return rv,err;
+    } else if len(e.Plates())==0 {
+        return rv,plates.InvalidEquipment("equipment was not created with NewEquipment");
+    }
+    return rv,nil;
+}
+
+//Creates a planned workout for every exercise in every session. For each
+//exercise the model state from the state generator and surface that is closest
+//to (but before) the first session is used. Every combination of sets and reps
+//in the exercises target is considered, and the load is set so that the
+//predicted effort is at the top of the target effort range. The load is then
+//rounded down to a loadable weight and the combination is only kept if the
+//predicted effort at the rounded load is still within the target range. Of the
+//remaining combinations the one with the most volume (sets*reps*weight) is
+//prescribed.
+//Fatigue is accumulated in the order the exercises are performed:
+//  - Inter exercise fatigue is the number of sets prescribed before the
+//    exercise in the same session.
+//  - Inter workout fatigue is the number of sets prescribed in the earlier
+//    sessions of the plan.
+//The loads are based on the clients max for the exercise on the date of the
+//first session, see oneRepMax.MaxOnDate. The weights of the planned workouts
+//are in the clients unit, see inClientUnit.
+func (p Prescriber)PrescribeWeek(
+        d *db.DB,
+        c *db.Client,
+        targets []Target,
+        sessions []Session) ([]db.PlannedWorkout,error) {
+    rv:=[]db.PlannedWorkout{};
+    if len(sessions)==0 {
+        return rv,customerr.InvalidValue("at least one session is needed");
+    }
+    sorted:=append([]Session{},sessions...);
+    sort.SliceStable(sorted,func(i int, j int) bool {
+        return sorted[i].Date.Before(sorted[j].Date);
+    });
+    exercises,err:=p.exerciseStates(d,c,targets,sorted);
+    if err!=nil {
+        return rv,err;
+    }
+    prevSessionSets:=0;
+    for _,s:=range(sorted) {
+        sessionSets:=0;
+        for _,eId:=range(s.ExerciseIDs) {
+            e:=exercises[eId];
+            calc,err:=potSurf.CalculationsWithHistory(d,e.calc,&db.TrainingLog{
+                ClientID: c.Id, ExerciseID: eId, DatePerformed: s.Date,
+            });
+            if err!=nil {
+                return rv,err;
+            }
+            iterRv,err:=p.prescribe(&e,calc,sessionSets,prevSessionSets);
+            if err!=nil {
+                return rv,err;
+            }
+            iterRv.ClientID=c.Id;
+            iterRv.DatePlanned=s.Date;
+            p.inClientUnit(c,&iterRv);
+            rv=append(rv,iterRv);
+            sessionSets+=int(iterRv.Sets);
+        }
+        prevSessionSets+=sessionSets;
+    }
+    return rv,nil;
+}
+
+func (p Prescriber)exerciseStates(
+        d *db.DB,
+        c *db.Client,
+        targets []Target,
+        sessions []Session) (map[int]exerciseState,error) {
+    rv:=map[int]exerciseState{};
+    byId:=map[int]Target{};
+    for _,t:=range(targets) {
+        if err:=validTarget(&t); err!=nil {
+            return rv,err;
+        }
+        byId[t.ExerciseID]=t;
+    }
+    calc,err:=potSurf.CalculationsFromSurfaceId(p.surf);
+    if err!=nil {
+        return rv,err;
+    }
+    for _,s:=range(sessions) {
+        for _,eId:=range(s.ExerciseIDs) {
+            if _,ok:=rv[eId]; ok {
+                continue;
+            }
+            t,ok:=byId[eId];
+            if !ok {
+                return rv,NoTargetForExercise(fmt.Sprintf("Exercise: %d",eId));
+            }
+            ms,err:=db.GetModelStateBeforeDate(
+                d,c.Id,eId,int(p.sg),int(p.surf),sessions[0].Date,
+            );
+            if err!=nil {
+                return rv,err;
+            }
+            m,err:=oneRepMax.MaxOnDate(d,c.Id,eId,sessions[0].Date);
+            if err!=nil {
+                return rv,err;
+            }
+            rv[eId]=exerciseState{target: t, ms: ms, calc: calc, max: m};
+        }
+    }
+    return rv,nil;
+}
+
+func validTarget(t *Target) error {
+    if t.Effort.A>t.Effort.B {
+        return customerr.InvalidValue(fmt.Sprintf(
+            "Exercise: %d min effort > max effort",t.ExerciseID,
+        ));
+    } else if t.Sets.A<1 || t.Sets.A>t.Sets.B {
+        return customerr.InvalidValue(fmt.Sprintf(
+            "Exercise: %d sets range must be >=1 and min<=max",t.ExerciseID,
+        ));
+    } else if t.Reps.A<1 || t.Reps.A>t.Reps.B {
+        return customerr.InvalidValue(fmt.Sprintf(
+            "Exercise: %d reps range must be >=1 and min<=max",t.ExerciseID,
+        ));
+    }
+    return nil;
+}
+
+func (p Prescriber)prescribe(
+        e *exerciseState,
+        calc potSurf.Calculations,
+        interExerciseFatigue int,
+        interWorkoutFatigue int) (db.PlannedWorkout,error) {
+    rv:=db.PlannedWorkout{
+        ExerciseID: e.target.ExerciseID,
+        StateGeneratorID: int(p.sg),
+        PotentialSurfaceID: int(p.surf),
+        InterExerciseFatigue: interExerciseFatigue,
+        InterWorkoutFatigue: interWorkoutFatigue,
+    };
+    bestVolume:=0.0;
+    for s:=e.target.Sets.A; s<=e.target.Sets.B; s++ {
+        for r:=e.target.Reps.A; r<=e.target.Reps.B; r++ {
+            tl:=db.TrainingLog{
+                Sets: float64(s),
+                Reps: float64(r),
+                Effort: e.target.Effort.B,
+                InterExerciseFatigue: interExerciseFatigue,
+                InterWorkoutFatigue: interWorkoutFatigue,
+            };
+            intensity:=calc.Intensity(&e.ms,&tl);
+            if stdMath.IsNaN(intensity) || intensity<=0 {
+                continue;
+            }
+            tl.Weight=p.loading.RoundDownKg(intensity*e.max);
+            tl.Intensity=tl.Weight/e.max;
+            effort:=calc.Effort(&e.ms,&tl);
+            if stdMath.IsNaN(effort) ||
+                effort<e.target.Effort.A || effort>e.target.Effort.B+1e-9 {
+                continue;
+            }
+            if vol:=tl.Sets*tl.Reps*tl.Weight; vol>bestVolume {
+                bestVolume=vol;
+                rv.Sets,rv.Reps=tl.Sets,tl.Reps;
+                rv.Weight,rv.Intensity,rv.Effort=tl.Weight,tl.Intensity,effort;
+            }
+        }
+    }
+    if bestVolume==0 {
+        return rv,NoFeasiblePrescription(fmt.Sprintf(
+            "Exercise: %d Effort: [%v,%v]",
+            e.target.ExerciseID,e.target.Effort.A,e.target.Effort.B,
+        ));
+    }
+    return rv,nil;
+}
+
+//Sets the unit of the planned workout to the clients unit and converts its
+//weight, which is in kilograms, to that unit. The weight is the loadings
+//display weight so the load that is shown is the one that goes on the bar,
+//which is rounded to the plate increments of the client when the loading is in
+//the clients unit.
+func (p Prescriber)inClientUnit(c *db.Client, w *db.PlannedWorkout) {
+    w.Unit=c.Unit;
+    w.Weight=db.ConvertWeight(
+        p.loading.DisplayWeight(w.Weight),p.loading.Unit(),db.Unit(c.Unit),
+    );
+}
+
+//Saves the planned workouts to the PlannedWorkout table.
+func Save(d *db.DB, plan []db.PlannedWorkout) ([]int,error) {
+    return db.Create(d,plan...);
+}
+
+//Writes the planned workouts to a CSV file. The weights are written in the unit
+//of each planned workout.
+func ToCSV(file string, plan []db.PlannedWorkout) error {
+    return csv.Flatten(csv.StructToCSV(
+        iter.SliceElems(plan),true,"01/02/2006",
+    ),",").ToFile(file,true);
+}
+
+//A planned workout with the weight converted to the equipments unit and the
+//plates needed to load it. The warm up is a space separated list of
+//weight x reps sets.
+//Note - THE ORDER OF THE STRUCT FIELDS IS THE ORDER OF THE CSV COLUMNS.
+type loadedWorkout struct {
+    ClientID int;
+    ExerciseID int;
+    DatePlanned time.Time;
+    Weight float64;
+    Unit string;
+    PlatesPerSide string;
+    WarmUp string;
+    Sets float64;
+    Reps float64;
+    Intensity float64;
+    Effort float64;
+};
+
+//Writes the planned workouts to a CSV file along with the plates needed to
+//load each workout and a warm up leading up to it. The weights are written in
+//the equipments unit and are rounded down to achievable loads. If no warm up
+//steps are given the default warm up is used.
+func ToCSVWithEquipment(
+        file string,
+        plan []db.PlannedWorkout,
+        e plates.Equipment,
+        warmUp []plates.WarmUpStep) error {
+    rows:=make([]loadedWorkout,len(plan));
+    for i,p:=range(plan) {
+        l:=e.RoundDown(db.ConvertWeight(p.Weight,db.Unit(p.Unit),e.Unit()));
+        sets,err:=e.WarmUp(l.Weight,warmUp);
+        if err!=nil {
+            return err;
+        }
+        w:=make([]string,len(sets));
+        for j,s:=range(sets) {
+            w[j]=fmt.Sprintf("%vx%d",s.Load.Weight,s.Reps);
+        }
+        rows[i]=loadedWorkout{
+            ClientID: p.ClientID,
+            ExerciseID: p.ExerciseID,
+            DatePlanned: p.DatePlanned,
+            Weight: l.Weight,
+            Unit: e.Unit().String(),
+            PlatesPerSide: l.PlatesString(),
+            WarmUp: strings.Join(w," "),
+            Sets: p.Sets,
+            Reps: p.Reps,
+            Intensity: p.Intensity,
+            Effort: p.Effort,
+        };
+    }
+    return csv.Flatten(csv.StructToCSV(
+        iter.SliceElems(rows),true,"01/02/2006",
+    ),",").ToFile(file,true);
+}
@@ -0,0 +1,209 @@
+package prescription
+
+import (
+	stdMath "math"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	"github.com/barbell-math/engine/model/plates"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func testExerciseState() exerciseState {
+    return exerciseState{
+        target: Target{
+            ExerciseID: 1,
+            Effort: dataStruct.Pair[float64,float64]{A: 7, B: 9},
+            Sets: dataStruct.Pair[int,int]{A: 1, B: 5},
+            Reps: dataStruct.Pair[int,int]{A: 1, B: 8},
+        },
+        ms: db.ModelState{
+            Eps: 0.5, Eps1: 0.05, Eps2: 0.001, Eps3: 0.002,
+            Eps4: 0.0005, Eps5: 0.002, Eps6: 0.004,
+        },
+        calc: potSurf.BasicSurfaceCalculation,
+        max: 200,
+    };
+}
+
+func testPrescriber() Prescriber {
+    l,_:=NewLoading(20,[]float64{25,20,15,10,5,2.5,1.25});
+    p,_:=NewPrescriber(
+        stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,l,
+    );
+    return p;
+}
+
+func TestNewPrescriberInvalid(t *testing.T){
+    l,_:=NewLoading(20,[]float64{2.5});
+    _,err:=NewPrescriber(
+        stateGen.SlidingWindowStateGenId,potSurf.PotentialSurfaceId(-1),l,
+    );
+    if !potSurf.IsInvalidPotentialSurfaceId(err) {
+        test.FormatError(potSurf.InvalidPotentialSurfaceId(""),err,
+            "Creating a prescriber with an invalid surface did not error.",t,
+        );
+    }
+    _,err=NewPrescriber(
+        stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,plates.Equipment{},
+    );
+    if !plates.IsInvalidEquipment(err) {
+        test.FormatError(plates.InvalidEquipment(""),err,
+            "Creating a prescriber with invalid equipment did not error.",t,
+        );
+    }
+}
+
+func TestValidTarget(t *testing.T){
+    e:=testExerciseState();
+    test.BasicTest(nil,validTarget(&e.target),"A valid target returned an error.",t);
+    for _,f:=range([]func(t *Target){
+        func(t *Target){ t.Effort.A=10; },
+        func(t *Target){ t.Sets.A=0; },
+        func(t *Target){ t.Sets.A=6; },
+        func(t *Target){ t.Reps.A=0; },
+        func(t *Target){ t.Reps.A=9; },
+    }) {
+        tmp:=e.target;
+        f(&tmp);
+        if err:=validTarget(&tmp); !customerr.IsInvalidValue(err) {
+            test.FormatError(customerr.InvalidValue(""),err,
+                "An invalid target did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestPrescribe(t *testing.T){
+    p:=testPrescriber();
+    e:=testExerciseState();
+    res,err:=p.prescribe(&e,e.calc,0,0);
+    test.BasicTest(nil,err,"Prescribing returned an error.",t);
+    test.BasicTest(p.loading.RoundDownKg(res.Weight),res.Weight,
+        "The prescribed weight was not loadable.",t,
+    );
+    test.BasicTest(true,res.Effort>=7 && res.Effort<=9,
+        "The prescribed effort was not in the target range.",t,
+    );
+    test.BasicTest(true,res.Sets>=1 && res.Sets<=5,
+        "The prescribed sets were not in the target range.",t,
+    );
+    test.BasicTest(true,res.Reps>=1 && res.Reps<=8,
+        "The prescribed reps were not in the target range.",t,
+    );
+    tl:=db.TrainingLog{
+        Sets: res.Sets, Reps: res.Reps, Effort: res.Effort,
+    };
+    test.BasicTest(true,
+        e.calc.Intensity(&e.ms,&tl)*e.max-res.Weight<2*p.loading.Plates()[len(p.loading.Plates())-1].Weight,
+        "The prescribed weight did not match the predicted intensity.",t,
+    );
+}
+
+func TestInClientUnit(t *testing.T){
+    l,_:=StandardLoading(db.Pounds);
+    p,_:=NewPrescriber(
+        stateGen.SlidingWindowStateGenId,potSurf.BasicSurfaceId,l,
+    );
+    w:=db.PlannedWorkout{Weight: db.Pounds.ToKg(317)};
+    p.inClientUnit(&db.Client{Unit: int(db.Pounds)},&w);
+    test.BasicTest(int(db.Pounds),w.Unit,"The unit was not set.",t);
+    test.BasicTest(315.0,w.Weight,
+        "The weight was not rounded to the plate increment.",t,
+    );
+    w=db.PlannedWorkout{Weight: db.Pounds.ToKg(315)};
+    p.inClientUnit(&db.Client{Unit: int(db.Kilograms)},&w);
+    test.BasicTest(int(db.Kilograms),w.Unit,"The unit was not set.",t);
+    test.BasicTest(true,stdMath.Abs(w.Weight-db.Pounds.ToKg(315))<1e-9,
+        "The weight was not converted to the clients unit.",t,
+    );
+}
+
+func TestPrescribeFatigue(t *testing.T){
+    p:=testPrescriber();
+    e:=testExerciseState();
+    e.target.Sets=dataStruct.Pair[int,int]{A: 3, B: 3};
+    e.target.Reps=dataStruct.Pair[int,int]{A: 5, B: 5};
+    fresh,err:=p.prescribe(&e,e.calc,0,0);
+    test.BasicTest(nil,err,"Prescribing returned an error.",t);
+    tired,err:=p.prescribe(&e,e.calc,10,20);
+    test.BasicTest(nil,err,"Prescribing returned an error.",t);
+    test.BasicTest(true,tired.Weight<fresh.Weight,
+        "Fatigue did not reduce the prescribed weight.",t,
+    );
+    test.BasicTest(10,tired.InterExerciseFatigue,
+        "Inter exercise fatigue was not recorded.",t,
+    );
+    test.BasicTest(20,tired.InterWorkoutFatigue,
+        "Inter workout fatigue was not recorded.",t,
+    );
+}
+
+func TestPrescribeInfeasible(t *testing.T){
+    p:=testPrescriber();
+    e:=testExerciseState();
+    e.ms.Eps=-1;
+    _,err:=p.prescribe(&e,e.calc,0,0);
+    if !IsNoFeasiblePrescription(err) {
+        test.FormatError(NoFeasiblePrescription(""),err,
+            "An infeasible target did not return an error.",t,
+        );
+    }
+}
+
+func TestPrescribeWeekNoTarget(t *testing.T){
+    p:=testPrescriber();
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    _,err:=p.PrescribeWeek(&testDB,&c,[]Target{},[]Session{
+        Session{Date: time.Now(), ExerciseIDs: []int{15}},
+    });
+    if !IsNoTargetForExercise(err) {
+        test.FormatError(NoTargetForExercise(""),err,
+            "A session exercise without a target did not return an error.",t,
+        );
+    }
+}
+
+func TestPrescribeWeek(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    sw.GenerateClientModelStates(&testDB,c,
+        time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC),surfs,
+    );
+    start:=time.Date(2022,time.Month(9),12,0,0,0,0,time.UTC);
+    target:=Target{
+        ExerciseID: 15,
+        Effort: dataStruct.Pair[float64,float64]{A: 6, B: 9},
+        Sets: dataStruct.Pair[int,int]{A: 1, B: 5},
+        Reps: dataStruct.Pair[int,int]{A: 1, B: 10},
+    };
+    p:=testPrescriber();
+    plan,err:=p.PrescribeWeek(&testDB,&c,[]Target{target},[]Session{
+        Session{Date: start.AddDate(0, 0, 2), ExerciseIDs: []int{15}},
+        Session{Date: start, ExerciseIDs: []int{15}},
+    });
+    if err!=nil && !IsNoFeasiblePrescription(err) {
+        test.FormatError(nil,err,"Prescribing a week returned an error.",t);
+    }
+    if err==nil {
+        test.BasicTest(2,len(plan),"Not every session was prescribed.",t);
+        test.BasicTest(true,plan[0].DatePlanned.Equal(start),
+            "Sessions were not prescribed in date order.",t,
+        );
+        test.BasicTest(int(plan[0].Sets),plan[1].InterWorkoutFatigue,
+            "Inter workout fatigue was not carried between sessions.",t,
+        );
+        ids,err:=Save(&testDB,plan);
+        test.BasicTest(nil,err,"Saving the plan returned an error.",t);
+        test.BasicTest(2,len(ids),"Not every planned workout was saved.",t);
+    }
+}
@@ -0,0 +1,151 @@
+package prescription
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The kinds of periodization a template can describe. The kind is only used to
+//describe the template, the generator treats every kind the same because each
+//week of the template is written out.
+const (
+    LinearTemplate string="linear"
+    BlockTemplate string="block"
+    UndulatingTemplate string="undulating"
+);
+
+//A periodization template. A template is made of mesocycles, which are made of
+//weeks, which are made of sessions, which are made of exercise slots. Every
+//week is 7 days long and the weeks of all the mesocycles are performed one
+//after another.
+//An example of the JSON format:
+//  {
+//    "name": "Example",
+//    "kind": "linear",
+//    "mesocycles": [{
+//      "name": "Accumulation",
+//      "weeks": [{
+//        "sessions": [{
+//          "day": 0,
+//          "slots": [
+//            {"exerciseID": 1, "sets": 5, "reps": 5, "intensity": 0.75},
+//            {"exerciseID": 2, "sets": 3, "reps": 8, "effort": 8}
+//          ]
+//        }]
+//      }]
+//    }]
+//  }
+type Template struct {
+    Name string `json:"name"`;
+    Kind string `json:"kind"`;
+    Mesocycles []Mesocycle `json:"mesocycles"`;
+};
+
+type Mesocycle struct {
+    Name string `json:"name"`;
+    Weeks []Week `json:"weeks"`;
+};
+
+type Week struct {
+    Sessions []TemplateSession `json:"sessions"`;
+};
+
+//A session of a template week. Day is the number of days after the start of
+//the week that the session is performed on, 0-6. The slots are performed in
+//the order they are given.
+type TemplateSession struct {
+    Day int `json:"day"`;
+    Slots []Slot `json:"slots"`;
+};
+
+//A single exercise in a session. The target is either relative to the clients
+//max (intensity, a fraction of the max) or an effort (RPE). Exactly one of the
+//two needs to be set.
+type Slot struct {
+    ExerciseID int `json:"exerciseID"`;
+    Sets int `json:"sets"`;
+    Reps int `json:"reps"`;
+    Intensity float64 `json:"intensity,omitempty"`;
+    Effort float64 `json:"effort,omitempty"`;
+};
+
+//Parses and validates a template from its JSON representation.
+func ParseTemplate(data []byte) (Template,error) {
+    var rv Template;
+    if err:=json.Unmarshal(data,&rv); err!=nil {
+        return rv,err;
+    }
+    return rv,rv.Validate();
+}
+
+//Reads, parses, and validates a template from a JSON file.
+func ReadTemplate(file string) (Template,error) {
+    data,err:=os.ReadFile(file);
+    if err!=nil {
+        return Template{},err;
+    }
+    return ParseTemplate(data);
+}
+
+//Returns the JSON representation of the template.
+func (t Template)JSON() (string,error) {
+    rv,err:=json.Marshal(t);
+    return string(rv),err;
+}
+
+//Returns the total number of weeks in the template.
+func (t Template)NumWeeks() int {
+    rv:=0;
+    for _,m:=range(t.Mesocycles) {
+        rv+=len(m.Weeks);
+    }
+    return rv;
+}
+
+func (t Template)Validate() error {
+    if t.Name=="" {
+        return customerr.InvalidValue("a template needs a name");
+    } else if t.Kind!=LinearTemplate && t.Kind!=BlockTemplate &&
+        t.Kind!=UndulatingTemplate {
+        return InvalidTemplate(fmt.Sprintf("Kind: '%s'",t.Kind));
+    } else if t.NumWeeks()==0 {
+        return InvalidTemplate("the template does not have any weeks");
+    }
+    for i,m:=range(t.Mesocycles) {
+        for j,w:=range(m.Weeks) {
+            for k,s:=range(w.Sessions) {
+                if msg:=s.validate(); msg!="" {
+                    return InvalidTemplate(fmt.Sprintf(
+                        "Mesocycle: %d Week: %d Session: %d | %s",i,j,k,msg,
+                    ));
+                }
+            }
+        }
+    }
+    return nil;
+}
+
+//Returns a description of the first problem with the session, or an empty
+//string if the session is valid.
+func (s TemplateSession)validate() string {
+    if s.Day<0 || s.Day>6 {
+        return fmt.Sprintf("day must be in the range [0,6], got %d",s.Day);
+    }
+    for i,v:=range(s.Slots) {
+        if v.Sets<1 || v.Reps<1 {
+            return fmt.Sprintf("slot %d: sets and reps must be >=1",i);
+        } else if (v.Intensity>0)==(v.Effort>0) {
+            return fmt.Sprintf(
+                "slot %d: exactly one of intensity and effort must be >0",i,
+            );
+        } else if v.Intensity<0 || v.Intensity>1 {
+            return fmt.Sprintf("slot %d: intensity must be in the range (0,1]",i);
+        } else if v.Effort<0 || v.Effort>10 {
+            return fmt.Sprintf("slot %d: effort must be in the range (0,10]",i);
+        }
+    }
+    return "";
+}
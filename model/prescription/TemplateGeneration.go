@@ -0,0 +1,286 @@
+package prescription
+
+import (
+	"database/sql"
+	"fmt"
+	stdMath "math"
+	"sort"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo"
+	"github.com/barbell-math/engine/model/oneRepMax"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+)
+
+//A planned workout along with the slot of the template it was created from.
+//The mesocycle, week, session, and slot are indexes into the template.
+type TemplateWorkout struct {
+    Mesocycle int;
+    Week int;
+    Session int;
+    Slot int;
+    Workout db.PlannedWorkout;
+};
+
+//Holds everything that is needed to load a templates slots that does not
+//change between sessions. The model state is nil if the exercise does not have
+//one.
+type templateExercise struct {
+    ms *db.ModelState;
+    calc potSurf.Calculations;
+    max float64;
+};
+
+//Expands the template into a planned workout for every slot, starting on the
+//given date. The first week of the template starts on the start date and every
+//following week starts 7 days after the previous one.
+//The loads are based on the clients max for each exercise on the start date
+//(see PrescribeWeek) and the model state from the state generator and surface
+//that is closest to (but before) the start date.
+//  - Intensity slots: the load is the intensity multiplied by the max, rounded
+//    down to a loadable weight. If the exercise has a model state the effort is
+//    predicted from it, otherwise the effort is NaN.
+//  - Effort slots: the intensity that is predicted to reach the effort is found
+//    from the model state and then rounded down to a loadable weight. The
+//    effort is the predicted effort at the rounded weight.
+//Fatigue is accumulated in the same way as PrescribeWeek, where the sessions
+//of each week of the template make up the plan. Sessions in the same week are
+//ordered by day. The weights of the planned workouts are in the clients unit,
+//the same as PrescribeWeek.
+func (p Prescriber)Generate(
+        d *db.DB,
+        c *db.Client,
+        t Template,
+        start time.Time) ([]TemplateWorkout,error) {
+    rv:=[]TemplateWorkout{};
+    if err:=t.Validate(); err!=nil {
+        return rv,err;
+    }
+    exercises,err:=p.templateExercises(d,c,t,start);
+    if err!=nil {
+        return rv,err;
+    }
+    weekStart:=start;
+    for i,m:=range(t.Mesocycles) {
+        for j,w:=range(m.Weeks) {
+            order:=make([]int,len(w.Sessions));
+            for k,_:=range(order) {
+                order[k]=k;
+            }
+            sort.SliceStable(order,func(a int, b int) bool {
+                return w.Sessions[order[a]].Day<w.Sessions[order[b]].Day;
+            });
+            prevSessionSets:=0;
+            for _,k:=range(order) {
+                s:=w.Sessions[k];
+                date:=weekStart.AddDate(0,0,s.Day);
+                sessionSets:=0;
+                for l,slot:=range(s.Slots) {
+                    e:=exercises[slot.ExerciseID];
+                    iterRv,err:=p.loadSlot(d,c,&e,slot,date,sessionSets,prevSessionSets);
+                    if err!=nil {
+                        return rv,err;
+                    }
+                    rv=append(rv,TemplateWorkout{
+                        Mesocycle: i, Week: j, Session: k, Slot: l,
+                        Workout: iterRv,
+                    });
+                    sessionSets+=slot.Sets;
+                }
+                prevSessionSets+=sessionSets;
+            }
+            weekStart=weekStart.AddDate(0,0,7);
+        }
+    }
+    return rv,nil;
+}
+
+func (p Prescriber)templateExercises(
+        d *db.DB,
+        c *db.Client,
+        t Template,
+        start time.Time) (map[int]templateExercise,error) {
+    rv:=map[int]templateExercise{};
+    calc,err:=potSurf.CalculationsFromSurfaceId(p.surf);
+    if err!=nil {
+        return rv,err;
+    }
+    for _,m:=range(t.Mesocycles) {
+        for _,w:=range(m.Weeks) {
+            for _,s:=range(w.Sessions) {
+                for _,slot:=range(s.Slots) {
+                    if _,ok:=rv[slot.ExerciseID]; ok {
+                        continue;
+                    }
+                    e:=templateExercise{calc: calc};
+                    if e.max,err=oneRepMax.MaxOnDate(
+                        d,c.Id,slot.ExerciseID,start,
+                    ); err!=nil {
+                        return rv,err;
+                    }
+                    ms,err:=db.GetModelStateBeforeDate(
+                        d,c.Id,slot.ExerciseID,int(p.sg),int(p.surf),start,
+                    );
+                    if err==nil {
+                        e.ms=&ms;
+                    } else if err!=sql.ErrNoRows {
+                        return rv,err;
+                    }
+                    rv[slot.ExerciseID]=e;
+                }
+            }
+        }
+    }
+    return rv,nil;
+}
+
+func (p Prescriber)loadSlot(
+        d *db.DB,
+        c *db.Client,
+        e *templateExercise,
+        slot Slot,
+        date time.Time,
+        interExerciseFatigue int,
+        interWorkoutFatigue int) (db.PlannedWorkout,error) {
+    calc:=e.calc;
+    if e.ms!=nil {
+        var err error;
+        calc,err=potSurf.CalculationsWithHistory(d,e.calc,&db.TrainingLog{
+            ClientID: c.Id, ExerciseID: slot.ExerciseID, DatePerformed: date,
+        });
+        if err!=nil {
+            return db.PlannedWorkout{},err;
+        }
+    }
+    rv,err:=p.slotWorkout(e,calc,slot,interExerciseFatigue,interWorkoutFatigue);
+    rv.ClientID=c.Id;
+    rv.DatePlanned=date;
+    p.inClientUnit(c,&rv);
+    return rv,err;
+}
+
+func (p Prescriber)slotWorkout(
+        e *templateExercise,
+        calc potSurf.Calculations,
+        slot Slot,
+        interExerciseFatigue int,
+        interWorkoutFatigue int) (db.PlannedWorkout,error) {
+    rv:=db.PlannedWorkout{
+        ExerciseID: slot.ExerciseID,
+        StateGeneratorID: int(p.sg),
+        PotentialSurfaceID: int(p.surf),
+        Sets: float64(slot.Sets),
+        Reps: float64(slot.Reps),
+        Effort: stdMath.NaN(),
+        InterExerciseFatigue: interExerciseFatigue,
+        InterWorkoutFatigue: interWorkoutFatigue,
+    };
+    tl:=db.TrainingLog{
+        Sets: rv.Sets,
+        Reps: rv.Reps,
+        Effort: slot.Effort,
+        InterExerciseFatigue: interExerciseFatigue,
+        InterWorkoutFatigue: interWorkoutFatigue,
+    };
+    intensity:=slot.Intensity;
+    if slot.Effort>0 {
+        if e.ms==nil {
+            return rv,NoModelStateForExercise(
+                fmt.Sprintf("Exercise: %d",slot.ExerciseID),
+            );
+        }
+        intensity=calc.Intensity(e.ms,&tl);
+        if stdMath.IsNaN(intensity) || intensity<=0 {
+            return rv,NoFeasiblePrescription(fmt.Sprintf(
+                "Exercise: %d Effort: %v",slot.ExerciseID,slot.Effort,
+            ));
+        }
+    }
+    rv.Weight=p.loading.RoundDownKg(intensity*e.max);
+    rv.Intensity=rv.Weight/e.max;
+    if e.ms!=nil {
+        tl.Weight,tl.Intensity=rv.Weight,rv.Intensity;
+        rv.Effort=calc.Effort(e.ms,&tl);
+    }
+    return rv,nil;
+}
+
+//Saves the template, replacing the saved template with the same name if one
+//exists. The id of the saved template is returned.
+func SaveTemplate(d *db.DB, t Template) (int,error) {
+    if err:=t.Validate(); err!=nil {
+        return 0,err;
+    }
+    data,err:=t.JSON();
+    if err!=nil {
+        return 0,err;
+    }
+    ids,err:=db.Upsert(d,[]string{"Name"},db.ProgramTemplate{
+        Name: t.Name, Kind: t.Kind, Template: data,
+    });
+    if err!=nil {
+        return 0,err;
+    }
+    return ids[0],nil;
+}
+
+//Returns the saved template with the given name along with its id.
+func GetTemplate(d *db.DB, name string) (Template,int,error) {
+    row,err,found:=db.Read(d,db.ProgramTemplate{Name: name},
+        algo.GenFilter(false,"Name"),
+    ).Nth(0);
+    if err==nil && !found {
+        err=sql.ErrNoRows;
+    }
+    if err!=nil {
+        return Template{},0,err;
+    }
+    rv,err:=ParseTemplate([]byte(row.Template));
+    return rv,row.Id,err;
+}
+
+//Saves a generated program. A program instance is created for the client
+//along with the planned workouts, and each planned workout is linked to the
+//slot of the template it was created from. The id of the program instance is
+//returned.
+func (p Prescriber)SaveProgram(
+        d *db.DB,
+        c *db.Client,
+        templateID int,
+        start time.Time,
+        program []TemplateWorkout) (int,error) {
+    ids,err:=db.Create(d,db.ProgramInstance{
+        ClientID: c.Id,
+        ProgramTemplateID: templateID,
+        StateGeneratorID: int(p.sg),
+        PotentialSurfaceID: int(p.surf),
+        StartDate: start,
+    });
+    if err!=nil {
+        return 0,err;
+    } else if len(program)==0 {
+        return ids[0],nil;
+    }
+    plan:=make([]db.PlannedWorkout,len(program));
+    for i,v:=range(program) {
+        plan[i]=v.Workout;
+    }
+    workoutIds,err:=Save(d,plan);
+    if err!=nil {
+        return ids[0],err;
+    }
+    links:=make([]db.ProgramInstanceWorkout,len(program));
+    for i,v:=range(program) {
+        links[i]=db.ProgramInstanceWorkout{
+            ProgramInstanceID: ids[0],
+            PlannedWorkoutID: workoutIds[i],
+            Mesocycle: v.Mesocycle,
+            Week: v.Week,
+            Session: v.Session,
+            Slot: v.Slot,
+        };
+    }
+    _,err=db.Create(d,links...);
+    return ids[0],err;
+}
@@ -0,0 +1,155 @@
+package prescription
+
+import (
+	stdMath "math"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestReadTemplate(t *testing.T){
+    tmpl,err:=ReadTemplate("testData/linearTemplate.json");
+    test.BasicTest(nil,err,"Reading a valid template returned an error.",t);
+    test.BasicTest("Linear Test",tmpl.Name,"The name was not parsed.",t);
+    test.BasicTest(LinearTemplate,tmpl.Kind,"The kind was not parsed.",t);
+    test.BasicTest(2,len(tmpl.Mesocycles),"The mesocycles were not parsed.",t);
+    test.BasicTest(3,tmpl.NumWeeks(),"The weeks were not parsed.",t);
+    slot:=tmpl.Mesocycles[0].Weeks[0].Sessions[1].Slots[0];
+    test.BasicTest(5,slot.Sets,"The sets were not parsed.",t);
+    test.BasicTest(0.7,slot.Intensity,"The intensity was not parsed.",t);
+    data,err:=tmpl.JSON();
+    test.BasicTest(nil,err,"Converting a template to JSON returned an error.",t);
+    again,err:=ParseTemplate([]byte(data));
+    test.BasicTest(nil,err,"Parsing a templates JSON returned an error.",t);
+    test.BasicTest(tmpl.NumWeeks(),again.NumWeeks(),
+        "The template did not survive a round trip through JSON.",t,
+    );
+}
+
+func TestTemplateValidate(t *testing.T){
+    valid,_:=ReadTemplate("testData/linearTemplate.json");
+    data,_:=valid.JSON();
+    if err:=(Template{Kind: LinearTemplate}).Validate(); !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A template without a name did not return an error.",t,
+        );
+    }
+    for _,f:=range([]func(t *Template){
+        func(t *Template){ t.Kind="conjugate"; },
+        func(t *Template){ t.Mesocycles=[]Mesocycle{}; },
+        func(t *Template){ t.Mesocycles[0].Weeks[0].Sessions[0].Day=7; },
+        func(t *Template){ t.Mesocycles[0].Weeks[0].Sessions[0].Slots[0].Sets=0; },
+        func(t *Template){ t.Mesocycles[0].Weeks[0].Sessions[0].Slots[0].Intensity=0.5; },
+        func(t *Template){ t.Mesocycles[0].Weeks[0].Sessions[0].Slots[0].Effort=0; },
+        func(t *Template){ t.Mesocycles[0].Weeks[0].Sessions[0].Slots[0].Effort=11; },
+        func(t *Template){ t.Mesocycles[0].Weeks[0].Sessions[1].Slots[0].Intensity=1.1; },
+    }) {
+        tmp,_:=ParseTemplate([]byte(data));
+        f(&tmp);
+        if err:=tmp.Validate(); !IsInvalidTemplate(err) {
+            test.FormatError(InvalidTemplate(""),err,
+                "An invalid template did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestSlotWorkout(t *testing.T){
+    p:=testPrescriber();
+    es:=testExerciseState();
+    e:=templateExercise{ms: &es.ms, calc: es.calc, max: es.max};
+    res,err:=p.slotWorkout(&e,e.calc,Slot{
+        ExerciseID: 1, Sets: 3, Reps: 5, Intensity: 0.72,
+    },2,4);
+    test.BasicTest(nil,err,"Loading an intensity slot returned an error.",t);
+    test.BasicTest(142.5,res.Weight,"The load was not rounded down.",t);
+    test.BasicTest(142.5/200,res.Intensity,"The intensity was not updated.",t);
+    test.BasicTest(false,stdMath.IsNaN(res.Effort),"The effort was not predicted.",t);
+    test.BasicTest(2,res.InterExerciseFatigue,"Fatigue was not recorded.",t);
+    test.BasicTest(4,res.InterWorkoutFatigue,"Fatigue was not recorded.",t);
+
+    res,err=p.slotWorkout(&e,e.calc,Slot{
+        ExerciseID: 1, Sets: 3, Reps: 5, Effort: 8,
+    },0,0);
+    test.BasicTest(nil,err,"Loading an effort slot returned an error.",t);
+    test.BasicTest(p.loading.RoundDownKg(res.Weight),res.Weight,
+        "The load was not loadable.",t,
+    );
+    test.BasicTest(true,res.Effort<=8+1e-9,
+        "Rounding the load down increased the effort.",t,
+    );
+
+    e.ms=nil;
+    res,err=p.slotWorkout(&e,e.calc,Slot{
+        ExerciseID: 1, Sets: 3, Reps: 5, Intensity: 0.5,
+    },0,0);
+    test.BasicTest(nil,err,"An intensity slot without a model state errored.",t);
+    test.BasicTest(true,stdMath.IsNaN(res.Effort),
+        "The effort was predicted without a model state.",t,
+    );
+    _,err=p.slotWorkout(&e,e.calc,Slot{
+        ExerciseID: 1, Sets: 3, Reps: 5, Effort: 8,
+    },0,0);
+    if !IsNoModelStateForExercise(err) {
+        test.FormatError(NoModelStateForExercise(""),err,
+            "An effort slot without a model state did not return an error.",t,
+        );
+    }
+}
+
+func TestGenerateAndSaveProgram(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    sw.GenerateClientModelStates(&testDB,c,
+        time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC),surfs,
+    );
+    tmpl,_:=ReadTemplate("testData/linearTemplate.json");
+    id,err:=SaveTemplate(&testDB,tmpl);
+    test.BasicTest(nil,err,"Saving a template returned an error.",t);
+    saved,savedId,err:=GetTemplate(&testDB,tmpl.Name);
+    test.BasicTest(nil,err,"Reading a saved template returned an error.",t);
+    test.BasicTest(id,savedId,"The wrong template was read.",t);
+    test.BasicTest(tmpl.NumWeeks(),saved.NumWeeks(),"The template was not saved.",t);
+
+    start:=time.Date(2022,time.Month(9),12,0,0,0,0,time.UTC);
+    p:=testPrescriber();
+    program,err:=p.Generate(&testDB,&c,saved,start);
+    if err!=nil && !IsNoFeasiblePrescription(err) {
+        test.FormatError(nil,err,"Generating a program returned an error.",t);
+    }
+    if err!=nil {
+        return;
+    }
+    test.BasicTest(5,len(program),"Not every slot was planned.",t);
+    test.BasicTest(true,program[0].Workout.DatePlanned.Equal(start),
+        "Sessions were not ordered by day.",t,
+    );
+    test.BasicTest(5,program[1].Workout.InterExerciseFatigue,
+        "Inter exercise fatigue was not accumulated.",t,
+    );
+    test.BasicTest(8,program[2].Workout.InterWorkoutFatigue,
+        "Inter workout fatigue was not accumulated.",t,
+    );
+    test.BasicTest(true,program[3].Workout.DatePlanned.Equal(start.AddDate(0,0,7)),
+        "The second week did not start 7 days later.",t,
+    );
+    test.BasicTest(0,program[3].Workout.InterWorkoutFatigue,
+        "Inter workout fatigue was carried between weeks.",t,
+    );
+    test.BasicTest(true,program[4].Workout.DatePlanned.Equal(start.AddDate(0,0,15)),
+        "The second mesocycle was not planned after the first.",t,
+    );
+    instanceId,err:=p.SaveProgram(&testDB,&c,id,start,program);
+    test.BasicTest(nil,err,"Saving a program returned an error.",t);
+    test.BasicTest(true,instanceId>0,"The program instance was not created.",t);
+}
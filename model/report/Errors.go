@@ -0,0 +1,9 @@
+package report;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var InvalidFormat,IsInvalidFormat=customerr.ErrorFactory(
+    "The report format is not supported.",
+);
@@ -0,0 +1,58 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/barbell-math/engine/model/load"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//The tolerance used to decide if a prediction is a hit when none is given.
+const DefaultTolerance float64=0.05;
+
+//The options used to generate a report.
+//  - StateGeneratorID and PotentialSurfaceID: the model states that are used
+//    for the constant trends and prediction accuracy
+//  - ExerciseIDs: the exercises that are reported on, empty reports on every
+//    exercise that was trained or had a max recorded in the date range
+//  - Tolerance: a prediction within the tolerance of the actual intensity is a
+//    hit, zero uses the default
+//  - Measure and Load: the measure and options used for the load metrics
+type Options struct {
+    StateGeneratorID stateGen.StateGeneratorId;
+    PotentialSurfaceID potSurf.PotentialSurfaceId;
+    ExerciseIDs []int;
+    Tolerance float64;
+    Measure load.Measure;
+    Load load.Options;
+};
+
+func (o *Options)fillDefaults() {
+    if o.Tolerance==0 {
+        o.Tolerance=DefaultTolerance;
+    }
+}
+
+func (o *Options)validate() error {
+    if o.Tolerance<0 {
+        return customerr.InvalidValue("tolerance < 0, should be >=0");
+    } else if o.Measure<load.Tonnage || o.Measure>load.SessionRPE {
+        return load.InvalidMeasure(fmt.Sprintf("Measure: %d",o.Measure));
+    }
+    _,err:=potSurf.Lookup(o.PotentialSurfaceID);
+    return err;
+}
+
+func (o *Options)includes(exerciseID int) bool {
+    if len(o.ExerciseIDs)==0 {
+        return true;
+    }
+    for _,id:=range(o.ExerciseIDs) {
+        if id==exerciseID {
+            return true;
+        }
+    }
+    return false;
+}
@@ -0,0 +1,46 @@
+package report
+
+func trainingLogsBeforeDateQuery() string {
+    return `SELECT *
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.DatePerformed<=$2
+        ORDER BY
+            DatePerformed ASC,
+            Id ASC;`;
+}
+
+func exerciseMaxesBetweenDatesQuery() string {
+    return `SELECT *
+        FROM ExerciseMax
+        WHERE ExerciseMax.ClientID=$1
+            AND ExerciseMax.Date>=$2
+            AND ExerciseMax.Date<=$3
+        ORDER BY
+            Date ASC,
+            Id ASC;`;
+}
+
+func modelStatesBetweenDatesQuery() string {
+    return `SELECT *
+        FROM ModelState
+        WHERE ModelState.ClientID=$1
+            AND ModelState.StateGeneratorID=$2
+            AND ModelState.PotentialSurfaceID=$3
+            AND ModelState.Date>=$4
+            AND ModelState.Date<=$5
+        ORDER BY
+            Date ASC,
+            Id ASC;`;
+}
+
+func bodyWeightBetweenDatesQuery() string {
+    return `SELECT *
+        FROM BodyWeight
+        WHERE BodyWeight.ClientID=$1
+            AND BodyWeight.Date>=$2
+            AND BodyWeight.Date<=$3
+        ORDER BY
+            Date ASC,
+            Id ASC;`;
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/base64"
+	"fmt"
+	htmlTemplate "html/template"
+	"io"
+	stdMath "math"
+	"os"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+)
+
+//The formats a report can be rendered in.
+type Format int;
+const (
+    HTML Format=iota
+    Markdown
+);
+
+func (f Format)String() string {
+    switch f {
+        case HTML: return "HTML";
+        case Markdown: return "Markdown";
+        default: return "unknown";
+    }
+}
+
+//The functions that are shared by both templates. The svg function is added
+//separately because each format embeds charts differently.
+var templateFuncs=map[string]any{
+    "date": func(t time.Time) string { return t.Format("01/02/2006"); },
+    "num": formatNum,
+    "pct": formatPct,
+    "source": func(s int) string { return db.MaxSource(s).String(); },
+};
+
+//Renders the report as a self contained HTML page. Charts are inline SVG
+//elements so the page does not reference any other files.
+func (r Report)HTML(w io.Writer) error {
+    funcs:=htmlTemplate.FuncMap{
+        "svg": func(c Chart) htmlTemplate.HTML {
+            return htmlTemplate.HTML(c.SVG());
+        },
+    };
+    for k,v:=range(templateFuncs) {
+        funcs[k]=v;
+    }
+    t,err:=htmlTemplate.New("report").Funcs(funcs).Parse(htmlReportTemplate);
+    if err!=nil {
+        return err;
+    }
+    return t.Execute(w,r);
+}
+
+//Renders the report as Markdown. Charts are embedded as images with a data
+//URI that contains the SVG, so the file does not reference any other files.
+func (r Report)Markdown(w io.Writer) error {
+    funcs:=textTemplate.FuncMap{
+        "svg": func(c Chart) string {
+            return fmt.Sprintf("![%s](data:image/svg+xml;base64,%s)",
+                markdownEscape(c.Title),
+                base64.StdEncoding.EncodeToString([]byte(c.SVG())),
+            );
+        },
+        "md": markdownEscape,
+    };
+    for k,v:=range(templateFuncs) {
+        funcs[k]=v;
+    }
+    t,err:=textTemplate.New("report").Funcs(funcs).Parse(markdownReportTemplate);
+    if err!=nil {
+        return err;
+    }
+    return t.Execute(w,r);
+}
+
+//Renders the report in the given format and writes it to the file, replacing
+//the file if it exists.
+func (r Report)ToFile(file string, f Format) error {
+    out,err:=os.Create(file);
+    if err!=nil {
+        return err;
+    }
+    switch f {
+        case HTML: err=r.HTML(out);
+        case Markdown: err=r.Markdown(out);
+        default: err=InvalidFormat(fmt.Sprintf("Format: %d",f));
+    }
+    if closeErr:=out.Close(); err==nil {
+        err=closeErr;
+    }
+    return err;
+}
+
+//Formats a fraction as a percent. NaN values are shown as a dash.
+func formatPct(v float64) string {
+    if stdMath.IsNaN(v) || stdMath.IsInf(v,0) {
+        return "-";
+    }
+    return fmt.Sprintf("%.1f%%",v*100);
+}
+
+//Escapes the characters that would otherwise change the structure of a
+//Markdown table or image.
+func markdownEscape(s string) string {
+    return strings.NewReplacer(
+        `\`,`\\`,"|",`\|`,"[",`\[`,"]",`\]`,"*",`\*`,"_",`\_`,
+    ).Replace(s);
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model/evaluation"
+	"github.com/barbell-math/engine/model/load"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func testReport() Report {
+    return Report{
+        Client: db.Client{FirstName: "Test", LastName: "<Client>", Unit: int(db.Pounds)},
+        Start: testDate(1),
+        End: testDate(31),
+        Unit: db.Pounds,
+        Accuracy: evaluation.Metrics{NumPredictions: 4, Mae: 0.02, HitRate: 0.75},
+        Load: load.Series{
+            Measure: load.Tonnage,
+            Points: []load.Point{
+                {Date: testDate(1), Load: 1000, Acute: 900, Chronic: 800},
+                {Date: testDate(2), Load: 0, Acute: 850, Chronic: 800},
+            },
+            Warnings: []load.Warning{
+                {Date: testDate(2), Kind: load.AcwrRollingHigh, Value: 1.6, Threshold: 1.5},
+            },
+        },
+        BodyWeight: []ChartPoint{{Date: testDate(1), Val: 200},{Date: testDate(8), Val: 199}},
+        Exercises: []ExerciseReport{{
+            ExerciseID: 1,
+            Name: "Squat",
+            Maxes: []db.ExerciseMax{
+                {Date: testDate(1), Weight: 405, Source: int(db.TestedMax)},
+            },
+            Constants: []ConstantTrend{{Name: "Eps", Points: []ChartPoint{
+                {Date: testDate(1), Val: 0.5},{Date: testDate(8), Val: 0.75},
+            }}},
+            PRs: []PR{{Date: testDate(8), Weight: 315, Reps: 5, Previous: 305}},
+        }},
+    };
+}
+
+func TestHTML(t *testing.T){
+    var b bytes.Buffer;
+    err:=testReport().HTML(&b);
+    test.BasicTest(nil,err,"Rendering HTML returned an error.",t);
+    out:=b.String();
+    test.BasicTest(true,strings.HasPrefix(out,"<!DOCTYPE html>"),
+        "The HTML was not a full page.",t,
+    );
+    test.BasicTest(true,strings.Contains(out,"Test &lt;Client&gt;"),
+        "The clients name was not escaped.",t,
+    );
+    test.BasicTest(5,strings.Count(out,"<svg "),"Not every chart was rendered.",t);
+    test.BasicTest(true,strings.Contains(out,"AcwrRollingHigh"),
+        "The load warnings were not rendered.",t,
+    );
+    test.BasicTest(true,strings.Contains(out,"<td>315.0</td>"),
+        "The PRs were not rendered.",t,
+    );
+    test.BasicTest(true,strings.Contains(out,"<td>Tested</td>"),
+        "The max source was not rendered.",t,
+    );
+    test.BasicTest(true,strings.Contains(out,"<td>0.2500</td>"),
+        "The constant change was not rendered.",t,
+    );
+    test.BasicTest(false,strings.Contains(out," src="),
+        "The HTML referenced another file.",t,
+    );
+}
+
+func TestMarkdown(t *testing.T){
+    var b bytes.Buffer;
+    err:=testReport().Markdown(&b);
+    test.BasicTest(nil,err,"Rendering Markdown returned an error.",t);
+    out:=b.String();
+    test.BasicTest(true,strings.HasPrefix(out,"# Progress Report: Test <Client>"),
+        "The title was not rendered.",t,
+    );
+    test.BasicTest(5,strings.Count(out,"(data:image/svg+xml;base64,"),
+        "Not every chart was embedded.",t,
+    );
+    test.BasicTest(true,strings.Contains(out,"| 01/08/2023 | 315.0 | 5 | 305.0 |"),
+        "The PRs were not rendered.",t,
+    );
+    test.BasicTest(true,strings.Contains(out,"## Squat"),
+        "The exercise was not rendered.",t,
+    );
+}
+
+func TestToFile(t *testing.T){
+    for _,f:=range([]Format{HTML,Markdown}) {
+        err:=testReport().ToFile("testData/report.out",f);
+        test.BasicTest(nil,err,"Writing a report returned an error.",t);
+        info,err:=os.Stat("testData/report.out");
+        test.BasicTest(nil,err,"The report file was not created.",t);
+        test.BasicTest(true,info.Size()>0,"The report file was empty.",t);
+    }
+    os.Remove("testData/report.out");
+    if err:=testReport().ToFile("testData/report.out",Format(5)); !IsInvalidFormat(err) {
+        test.FormatError(InvalidFormat(""),err,
+            "An invalid format did not return an error.",t,
+        );
+    }
+    os.Remove("testData/report.out");
+}
+
+func TestMarkdownEscape(t *testing.T){
+    test.BasicTest(`a\|b \[c\] \*d\*`,markdownEscape("a|b [c] *d*"),
+        "Markdown characters were not escaped.",t,
+    );
+}
@@ -0,0 +1,363 @@
+package report
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model/evaluation"
+	"github.com/barbell-math/engine/model/load"
+	"github.com/barbell-math/engine/util/algo/iter"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//A training log that was heavier than any earlier training log of the same
+//exercise with at least as many reps. The previous weight is the heaviest of
+//those earlier training logs.
+type PR struct {
+    Date time.Time;
+    Weight float64;
+    Reps float64;
+    Previous float64;
+};
+
+//The values of a single model state constant over time.
+type ConstantTrend struct {
+    Name string;
+    Points []ChartPoint;
+};
+
+func (c ConstantTrend)First() float64 { return c.Points[0].Val; }
+func (c ConstantTrend)Last() float64 { return c.Points[len(c.Points)-1].Val; }
+func (c ConstantTrend)Change() float64 { return c.Last()-c.First(); }
+
+//Everything in a report that is about a single exercise. The accuracy metrics
+//have zero predictions if no predictions could be made for the exercise.
+type ExerciseReport struct {
+    ExerciseID int;
+    Name string;
+    Maxes []db.ExerciseMax;
+    Constants []ConstantTrend;
+    Accuracy evaluation.Metrics;
+    PRs []PR;
+};
+
+//A clients progress over a date range. Every weight is in the reports unit,
+//which is the unit the client prefers. The load metrics use the measure from
+//the options the report was generated with.
+type Report struct {
+    Client db.Client;
+    Start time.Time;
+    End time.Time;
+    Unit db.Unit;
+    Exercises []ExerciseReport;
+    Accuracy evaluation.Metrics;
+    Load load.Series;
+    BodyWeight []ChartPoint;
+};
+
+//Generates a progress report for the client between the start and end dates
+//(inclusive).
+//  - Maxes: every max recorded for the exercise in the range
+//  - Constants: the model state constants that are not always zero, from the
+//    model states created by the state generator and surface in the options
+//  - Accuracy: the accuracy of out of sample predictions made for training logs
+//    in the range, see evaluation.BacktestModelStates
+//  - PRs: training logs in the range that beat the clients earlier training
+//    logs, see PR
+//  - Load: the load metrics for every day in the range, see load.Monitor
+//  - BodyWeight: every body weight recorded in the range
+func Generate(
+        d *db.DB,
+        c *db.Client,
+        start time.Time,
+        end time.Time,
+        o Options) (Report,error) {
+    u:=db.Unit(c.Unit);
+    rv:=Report{Client: *c, Start: start, End: end, Unit: u};
+    o.fillDefaults();
+    if err:=o.validate(); err!=nil {
+        return rv,err;
+    } else if end.Before(start) {
+        return rv,customerr.InvalidValue("end date is before start date");
+    }
+    logs,err:=db.CustomReadQuery[db.TrainingLog](d,
+        trainingLogsBeforeDateQuery(),[]any{c.Id,end},
+    ).Collect();
+    if err!=nil && err!=sql.ErrNoRows {
+        return rv,err;
+    }
+    maxes,err:=db.CustomReadQuery[db.ExerciseMax](d,
+        exerciseMaxesBetweenDatesQuery(),[]any{c.Id,start,end},
+    ).Collect();
+    if err!=nil && err!=sql.ErrNoRows {
+        return rv,err;
+    }
+    states,err:=db.CustomReadQuery[db.ModelState](d,
+        modelStatesBetweenDatesQuery(),[]any{
+            c.Id,int(o.StateGeneratorID),int(o.PotentialSurfaceID),start,end,
+    }).Collect();
+    if err!=nil && err!=sql.ErrNoRows {
+        return rv,err;
+    }
+    accuracy,err:=evaluation.BacktestModelStates(d,[]db.Client{*c},start,end,
+        o.StateGeneratorID,o.PotentialSurfaceID,o.Tolerance,
+    );
+    if err!=nil {
+        return rv,err;
+    }
+    rv.Accuracy=accuracy.Overall;
+    if rv.Exercises,err=exerciseReports(
+        d,reportExerciseIDs(logs,maxes,start,&o),u,logs,maxes,states,
+        accuracy,start,
+    ); err!=nil {
+        return rv,err;
+    }
+    if rv.Load,err=load.Monitor(d,c,o.Measure,start,end,o.Load); err!=nil {
+        return rv,err;
+    }
+    if o.Measure==load.Tonnage {
+        tonnageInUnit(&rv.Load,u);
+    }
+    err=db.CustomReadQuery[db.BodyWeight](d,
+        bodyWeightBetweenDatesQuery(),[]any{c.Id,start,end},
+    ).ForEach(func(index int, val *db.BodyWeight) (iter.IteratorFeedback,error) {
+        rv.BodyWeight=append(rv.BodyWeight,ChartPoint{
+            Date: val.Date, Val: u.FromKg(float64(val.Weight)),
+        });
+        return iter.Continue,nil;
+    });
+    if err!=nil && err!=sql.ErrNoRows {
+        return rv,err;
+    }
+    return rv,nil;
+}
+
+//Returns the exercises that were trained or had a max recorded in the range
+//and are included by the options, in id order.
+func reportExerciseIDs(
+        logs []*db.TrainingLog,
+        maxes []*db.ExerciseMax,
+        start time.Time,
+        o *Options) []int {
+    ids:=map[int]struct{}{};
+    for _,tl:=range(logs) {
+        if !tl.DatePerformed.Before(start) && o.includes(tl.ExerciseID) {
+            ids[tl.ExerciseID]=struct{}{};
+        }
+    }
+    for _,m:=range(maxes) {
+        if o.includes(m.ExerciseID) {
+            ids[m.ExerciseID]=struct{}{};
+        }
+    }
+    rv:=make([]int,0,len(ids));
+    for id,_:=range(ids) {
+        rv=append(rv,id);
+    }
+    sort.Ints(rv);
+    return rv;
+}
+
+func exerciseReports(
+        d *db.DB,
+        ids []int,
+        u db.Unit,
+        logs []*db.TrainingLog,
+        maxes []*db.ExerciseMax,
+        states []*db.ModelState,
+        accuracy evaluation.Report,
+        start time.Time) ([]ExerciseReport,error) {
+    rv:=make([]ExerciseReport,len(ids));
+    for i,id:=range(ids) {
+        e,err,found:=db.Read(d,db.Exercise{Id: id},db.OnlyIDFilter).Nth(0);
+        if err==nil && !found {
+            err=sql.ErrNoRows;
+        }
+        if err!=nil {
+            return rv,err;
+        }
+        rv[i]=ExerciseReport{
+            ExerciseID: id,
+            Name: e.Name,
+            Maxes: []db.ExerciseMax{},
+            Accuracy: evaluation.Metrics{
+                StateGeneratorID: accuracy.StateGeneratorID,
+                PotentialSurfaceID: accuracy.PotentialSurfaceID,
+                ExerciseID: id,
+            },
+        };
+        for _,m:=range(maxes) {
+            if m.ExerciseID==id {
+                tmp:=*m;
+                tmp.Weight=u.FromKg(tmp.Weight);
+                rv[i].Maxes=append(rv[i].Maxes,tmp);
+            }
+        }
+        for _,m:=range(accuracy.ByClientExercise) {
+            if m.ExerciseID==id {
+                rv[i].Accuracy=m;
+            }
+        }
+        rv[i].Constants=constantTrends(states,id);
+        rv[i].PRs=prs(logs,id,start,u);
+    }
+    return rv,nil;
+}
+
+//Returns the trend of every model state constant of the exercise that is not
+//zero in every model state.
+func constantTrends(states []*db.ModelState, exerciseID int) []ConstantTrend {
+    names:=[]string{"Eps","Eps1","Eps2","Eps3","Eps4","Eps5","Eps6","Eps7"};
+    trends:=make([]ConstantTrend,len(names));
+    used:=make([]bool,len(names));
+    for i,n:=range(names) {
+        trends[i]=ConstantTrend{Name: n, Points: []ChartPoint{}};
+    }
+    for _,ms:=range(states) {
+        if ms.ExerciseID!=exerciseID {
+            continue;
+        }
+        for i,v:=range([]float64{
+            ms.Eps,ms.Eps1,ms.Eps2,ms.Eps3,ms.Eps4,ms.Eps5,ms.Eps6,ms.Eps7,
+        }) {
+            trends[i].Points=append(trends[i].Points,ChartPoint{
+                Date: ms.Date, Val: v,
+            });
+            used[i]=used[i] || v!=0;
+        }
+    }
+    rv:=[]ConstantTrend{};
+    for i,t:=range(trends) {
+        if used[i] {
+            rv=append(rv,t);
+        }
+    }
+    return rv;
+}
+
+//Returns the PRs of the exercise that were set on or after the start date. The
+//training logs are expected to be in date order and the training logs before
+//the start date are used as the clients history. A training log is not a PR
+//if there are no earlier training logs of the exercise.
+func prs(
+        logs []*db.TrainingLog,
+        exerciseID int,
+        start time.Time,
+        u db.Unit) []PR {
+    rv:=[]PR{};
+    history:=[]*db.TrainingLog{};
+    for _,tl:=range(logs) {
+        if tl.ExerciseID!=exerciseID {
+            continue;
+        }
+        best:=0.0;
+        for _,h:=range(history) {
+            if h.Reps>=tl.Reps && h.Weight>best {
+                best=h.Weight;
+            }
+        }
+        if len(history)>0 && tl.Weight>best+1e-9 && !tl.DatePerformed.Before(start) {
+            rv=append(rv,PR{
+                Date: tl.DatePerformed,
+                Weight: u.FromKg(tl.Weight),
+                Reps: tl.Reps,
+                Previous: u.FromKg(best),
+            });
+        }
+        history=append(history,tl);
+    }
+    return rv;
+}
+
+//Converts the load values of a tonnage series from kilograms to the unit. The
+//ratios and monotony do not have a unit so they are left unchanged.
+func tonnageInUnit(s *load.Series, u db.Unit) {
+    for i,_:=range(s.Points) {
+        p:=&s.Points[i];
+        p.Load=u.FromKg(p.Load);
+        p.Acute=u.FromKg(p.Acute);
+        p.Chronic=u.FromKg(p.Chronic);
+        p.AcuteEwma=u.FromKg(p.AcuteEwma);
+        p.ChronicEwma=u.FromKg(p.ChronicEwma);
+        p.Strain=u.FromKg(p.Strain);
+    }
+    for i,_:=range(s.Warnings) {
+        if w:=&s.Warnings[i]; w.Kind==load.StrainHigh {
+            w.Value=u.FromKg(w.Value);
+            w.Threshold=u.FromKg(w.Threshold);
+        }
+    }
+}
+
+//Returns a chart of the exercises maxes, with tested and estimated maxes as
+//separate series.
+func (e ExerciseReport)MaxChart(u db.Unit) Chart {
+    bySource:=map[int]*ChartSeries{};
+    order:=[]int{};
+    for _,m:=range(e.Maxes) {
+        if _,ok:=bySource[m.Source]; !ok {
+            bySource[m.Source]=&ChartSeries{Name: db.MaxSource(m.Source).String()};
+            order=append(order,m.Source);
+        }
+        s:=bySource[m.Source];
+        s.Points=append(s.Points,ChartPoint{Date: m.Date, Val: m.Weight});
+    }
+    sort.Ints(order);
+    rv:=Chart{
+        Title: fmt.Sprintf("%s Maxes",e.Name),
+        YLabel: u.String(),
+        Series: make([]ChartSeries,len(order)),
+    };
+    for i,src:=range(order) {
+        rv.Series[i]=*bySource[src];
+    }
+    return rv;
+}
+
+//Returns a chart of a single model state constant.
+func (e ExerciseReport)ConstantChart(c ConstantTrend) Chart {
+    return Chart{
+        Title: fmt.Sprintf("%s %s",e.Name,c.Name),
+        Series: []ChartSeries{{Name: c.Name, Points: c.Points}},
+    };
+}
+
+//Returns a chart of the daily load along with the acute and chronic averages.
+func (r Report)LoadChart() Chart {
+    series:=[]ChartSeries{
+        {Name: "Load"},{Name: "Acute"},{Name: "Chronic"},
+    };
+    for _,p:=range(r.Load.Points) {
+        series[0].Points=append(series[0].Points,ChartPoint{Date: p.Date, Val: p.Load});
+        series[1].Points=append(series[1].Points,ChartPoint{Date: p.Date, Val: p.Acute});
+        series[2].Points=append(series[2].Points,ChartPoint{Date: p.Date, Val: p.Chronic});
+    }
+    rv:=Chart{Title: fmt.Sprintf("%s Load",r.Load.Measure),Series: series};
+    if r.Load.Measure==load.Tonnage {
+        rv.YLabel=r.Unit.String();
+    }
+    return rv;
+}
+
+//Returns a chart of the acute to chronic workload ratios.
+func (r Report)AcwrChart() Chart {
+    series:=[]ChartSeries{{Name: "Rolling"},{Name: "EWMA"}};
+    for _,p:=range(r.Load.Points) {
+        series[0].Points=append(series[0].Points,ChartPoint{Date: p.Date, Val: p.AcwrRolling});
+        series[1].Points=append(series[1].Points,ChartPoint{Date: p.Date, Val: p.AcwrEwma});
+    }
+    return Chart{Title: "Acute:Chronic Workload Ratio",Series: series};
+}
+
+//Returns a chart of the clients body weight.
+func (r Report)BodyWeightChart() Chart {
+    return Chart{
+        Title: "Body Weight",
+        YLabel: r.Unit.String(),
+        Series: []ChartSeries{{Name: "Body Weight", Points: r.BodyWeight}},
+    };
+}
+
@@ -0,0 +1,139 @@
+package report
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/model/load"
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	potSurf "github.com/barbell-math/engine/model/potentialSurface"
+	stateGen "github.com/barbell-math/engine/model/stateGenerator"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestPRs(t *testing.T){
+    logs:=[]*db.TrainingLog{
+        {ExerciseID: 1, DatePerformed: testDate(1), Weight: 100, Reps: 5},
+        {ExerciseID: 2, DatePerformed: testDate(2), Weight: 300, Reps: 5},
+        {ExerciseID: 1, DatePerformed: testDate(3), Weight: 110, Reps: 3},
+        {ExerciseID: 1, DatePerformed: testDate(10), Weight: 105, Reps: 5},
+        {ExerciseID: 1, DatePerformed: testDate(11), Weight: 105, Reps: 5},
+        {ExerciseID: 1, DatePerformed: testDate(12), Weight: 108, Reps: 3},
+        {ExerciseID: 1, DatePerformed: testDate(13), Weight: 115, Reps: 1},
+    };
+    res:=prs(logs,1,testDate(10),db.Kilograms);
+    test.BasicTest(2,len(res),"The wrong number of PRs was found.",t);
+    test.BasicTest(105.0,res[0].Weight,"A rep PR was not found.",t);
+    test.BasicTest(100.0,res[0].Previous,"The previous best was not correct.",t);
+    test.BasicTest(115.0,res[1].Weight,"A heavier single was not a PR.",t);
+    test.BasicTest(110.0,res[1].Previous,
+        "The previous best did not include logs with more reps.",t,
+    );
+    test.BasicTest(0,len(prs(logs,2,testDate(1),db.Kilograms)),
+        "The first log of an exercise was a PR.",t,
+    );
+    res=prs(logs,1,testDate(10),db.Pounds);
+    test.BasicTest(db.Pounds.FromKg(105),res[0].Weight,
+        "The PR was not converted to the unit.",t,
+    );
+}
+
+func TestConstantTrends(t *testing.T){
+    res:=constantTrends([]*db.ModelState{
+        {ExerciseID: 1, Date: testDate(1), Eps: 1, Eps2: 0.5},
+        {ExerciseID: 2, Date: testDate(1), Eps: 9, Eps3: 9},
+        {ExerciseID: 1, Date: testDate(8), Eps: 2, Eps2: 0},
+    },1);
+    test.BasicTest(2,len(res),"Constants that were always zero were kept.",t);
+    test.BasicTest("Eps",res[0].Name,"The constants were not in order.",t);
+    test.BasicTest(1.0,res[0].Change(),"The change was not correct.",t);
+    test.BasicTest("Eps2",res[1].Name,"A constant that became zero was dropped.",t);
+    test.BasicTest(-0.5,res[1].Change(),"The change was not correct.",t);
+}
+
+func TestReportExerciseIDs(t *testing.T){
+    logs:=[]*db.TrainingLog{
+        {ExerciseID: 3, DatePerformed: testDate(1)},
+        {ExerciseID: 2, DatePerformed: testDate(5)},
+        {ExerciseID: 1, DatePerformed: testDate(6)},
+    };
+    maxes:=[]*db.ExerciseMax{{ExerciseID: 4},{ExerciseID: 2}};
+    test.SlicesMatch[int]([]int{1,2,4},
+        reportExerciseIDs(logs,maxes,testDate(5),&Options{}),t,
+    );
+    test.SlicesMatch[int]([]int{2},
+        reportExerciseIDs(logs,maxes,testDate(5),&Options{ExerciseIDs: []int{2,3}}),t,
+    );
+}
+
+func TestTonnageInUnit(t *testing.T){
+    s:=load.Series{
+        Points: []load.Point{{Load: 100, AcwrRolling: 1.2, Strain: 50}},
+        Warnings: []load.Warning{
+            {Kind: load.StrainHigh, Value: 50, Threshold: 40},
+            {Kind: load.MonotonyHigh, Value: 2.5, Threshold: 2},
+        },
+    };
+    tonnageInUnit(&s,db.Pounds);
+    test.BasicTest(db.Pounds.FromKg(100),s.Points[0].Load,"The load was not converted.",t);
+    test.BasicTest(1.2,s.Points[0].AcwrRolling,"A ratio was converted.",t);
+    test.BasicTest(db.Pounds.FromKg(40),s.Warnings[0].Threshold,
+        "The strain threshold was not converted.",t,
+    );
+    test.BasicTest(2.0,s.Warnings[1].Threshold,"The monotony was converted.",t);
+}
+
+func TestGenerateInvalid(t *testing.T){
+    c:=db.Client{Id: 1};
+    _,err:=Generate(&testDB,&c,testDate(2),testDate(1),Options{
+        PotentialSurfaceID: potSurf.BasicSurfaceId,
+    });
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "An end date before the start date did not return an error.",t,
+        );
+    }
+    _,err=Generate(&testDB,&c,testDate(1),testDate(2),Options{
+        PotentialSurfaceID: potSurf.BasicSurfaceId, Tolerance: -1,
+    });
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A negative tolerance did not return an error.",t,
+        );
+    }
+}
+
+func TestGenerate(t *testing.T){
+    sw,_:=stateGen.NewSlidingWindowStateGen(
+        dataStruct.Pair[int,int]{A: 1, B: 500},
+        dataStruct.Pair[int,int]{A: 1, B: 30},1,
+    );
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    surfs,_:=potSurf.SurfaceFactory(potSurf.BasicSurfaceId);
+    sw.GenerateClientModelStates(&testDB,c,
+        time.Date(2022,time.Month(1),1,0,0,0,0,time.UTC),surfs,
+    );
+    start:=time.Date(2022,time.Month(6),1,0,0,0,0,time.UTC);
+    end:=time.Date(2022,time.Month(8),1,0,0,0,0,time.UTC);
+    r,err:=Generate(&testDB,&c,start,end,Options{
+        StateGeneratorID: stateGen.SlidingWindowStateGenId,
+        PotentialSurfaceID: potSurf.BasicSurfaceId,
+    });
+    test.BasicTest(nil,err,"Generating a report returned an error.",t);
+    test.BasicTest(true,len(r.Exercises)>0,"No exercises were reported.",t);
+    test.BasicTest(62,len(r.Load.Points),"The load did not cover the range.",t);
+    for _,e:=range(r.Exercises) {
+        test.BasicTest(true,e.Name!="","The exercise name was not read.",t);
+        for _,p:=range(e.PRs) {
+            test.BasicTest(true,!p.Date.Before(start) && !p.Date.After(end),
+                "A PR outside of the range was reported.",t,
+            );
+        }
+    }
+    err=r.ToFile("testData/report.html",HTML);
+    test.BasicTest(nil,err,"Writing the report returned an error.",t);
+    os.Remove("testData/report.html");
+}
@@ -0,0 +1,191 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	stdMath "math"
+	"strings"
+	"time"
+)
+
+//The size of a rendered chart in pixels, along with the space that is left
+//around the plot area for the axis labels and legend.
+const (
+    ChartWidth int=640
+    ChartHeight int=240
+    chartMarginLeft int=60
+    chartMarginRight int=20
+    chartMarginTop int=30
+    chartMarginBottom int=50
+);
+
+//The colors of the series in a chart, in the order the series are given.
+var chartColors=[]string{
+    "#1f77b4","#d62728","#2ca02c","#ff7f0e","#9467bd","#8c564b","#e377c2",
+    "#7f7f7f",
+};
+
+type ChartPoint struct {
+    Date time.Time;
+    Val float64;
+};
+
+//A named line in a chart. Points with a NaN value are skipped and break the
+//line in two.
+type ChartSeries struct {
+    Name string;
+    Points []ChartPoint;
+};
+
+//A line chart with dates along the x axis. The y axis is scaled to fit every
+//series.
+type Chart struct {
+    Title string;
+    YLabel string;
+    Series []ChartSeries;
+};
+
+//Returns the bounds of all of the points in the chart. The last value is false
+//if the chart does not have any points.
+func (c Chart)bounds() (time.Time,time.Time,float64,float64,bool) {
+    var minX,maxX time.Time;
+    minY,maxY:=stdMath.Inf(1),stdMath.Inf(-1);
+    found:=false;
+    for _,s:=range(c.Series) {
+        for _,p:=range(s.Points) {
+            if stdMath.IsNaN(p.Val) || stdMath.IsInf(p.Val,0) {
+                continue;
+            }
+            if !found || p.Date.Before(minX) {
+                minX=p.Date;
+            }
+            if !found || p.Date.After(maxX) {
+                maxX=p.Date;
+            }
+            minY=stdMath.Min(minY,p.Val);
+            maxY=stdMath.Max(maxY,p.Val);
+            found=true;
+        }
+    }
+    if found && maxY-minY<1e-9 {
+        minY,maxY=minY-1,maxY+1;
+    } else if found {
+        pad:=(maxY-minY)*0.05;
+        minY,maxY=minY-pad,maxY+pad;
+    }
+    return minX,maxX,minY,maxY,found;
+}
+
+//Renders the chart as a self contained SVG element. The output is the same
+//every time the same chart is rendered.
+func (c Chart)SVG() string {
+    var sb strings.Builder;
+    w,h:=float64(ChartWidth),float64(ChartHeight);
+    left,right:=float64(chartMarginLeft),w-float64(chartMarginRight);
+    top,bottom:=float64(chartMarginTop),h-float64(chartMarginBottom);
+    fmt.Fprintf(&sb,
+        `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+        ChartWidth,ChartHeight,ChartWidth,ChartHeight,
+    );
+    fmt.Fprintf(&sb,`<rect width="%d" height="%d" fill="#ffffff"/>`,ChartWidth,ChartHeight);
+    fmt.Fprintf(&sb,`<text x="%.1f" y="18" font-size="13" font-weight="bold">%s</text>`,
+        left,html.EscapeString(c.Title),
+    );
+    minX,maxX,minY,maxY,found:=c.bounds();
+    if !found {
+        fmt.Fprintf(&sb,`<text x="%.1f" y="%.1f" text-anchor="middle" fill="#7f7f7f">No data</text></svg>`,
+            w/2,h/2,
+        );
+        return sb.String();
+    }
+    span:=maxX.Sub(minX).Seconds();
+    xPos:=func(t time.Time) float64 {
+        if span==0 {
+            return (left+right)/2;
+        }
+        return left+(right-left)*t.Sub(minX).Seconds()/span;
+    };
+    yPos:=func(v float64) float64 {
+        return bottom-(bottom-top)*(v-minY)/(maxY-minY);
+    };
+    fmt.Fprintf(&sb,
+        `<path d="M%.1f %.1fV%.1fH%.1f" fill="none" stroke="#333333"/>`,
+        left,top,bottom,right,
+    );
+    for _,v:=range([]float64{minY,(minY+maxY)/2,maxY}) {
+        fmt.Fprintf(&sb,
+            `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`,
+            left-4,yPos(v),formatNum(v),
+        );
+    }
+    fmt.Fprintf(&sb,`<text x="%.1f" y="%.1f">%s</text>`,
+        left,bottom+14,minX.Format("01/02/2006"),
+    );
+    fmt.Fprintf(&sb,`<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`,
+        right,bottom+14,maxX.Format("01/02/2006"),
+    );
+    if c.YLabel!="" {
+        fmt.Fprintf(&sb,
+            `<text x="12" y="%.1f" text-anchor="middle" transform="rotate(-90 12 %.1f)">%s</text>`,
+            (top+bottom)/2,(top+bottom)/2,html.EscapeString(c.YLabel),
+        );
+    }
+    legendX:=left;
+    for i,s:=range(c.Series) {
+        color:=chartColors[i%len(chartColors)];
+        for _,line:=range(s.lines()) {
+            if len(line)==1 {
+                fmt.Fprintf(&sb,`<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s"/>`,
+                    xPos(line[0].Date),yPos(line[0].Val),color,
+                );
+                continue;
+            }
+            pts:=make([]string,len(line));
+            for j,p:=range(line) {
+                pts[j]=fmt.Sprintf("%.1f,%.1f",xPos(p.Date),yPos(p.Val));
+            }
+            fmt.Fprintf(&sb,
+                `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`,
+                strings.Join(pts," "),color,
+            );
+        }
+        fmt.Fprintf(&sb,
+            `<rect x="%.1f" y="%.1f" width="10" height="10" fill="%s"/><text x="%.1f" y="%.1f">%s</text>`,
+            legendX,h-16,color,legendX+14,h-7,html.EscapeString(s.Name),
+        );
+        legendX+=24+7*float64(len(s.Name));
+    }
+    sb.WriteString("</svg>");
+    return sb.String();
+}
+
+//Splits the series into the runs of points that do not contain a NaN value.
+func (s ChartSeries)lines() [][]ChartPoint {
+    rv:=[][]ChartPoint{};
+    cur:=[]ChartPoint{};
+    for _,p:=range(s.Points) {
+        if stdMath.IsNaN(p.Val) || stdMath.IsInf(p.Val,0) {
+            if len(cur)>0 {
+                rv=append(rv,cur);
+            }
+            cur=[]ChartPoint{};
+            continue;
+        }
+        cur=append(cur,p);
+    }
+    if len(cur)>0 {
+        rv=append(rv,cur);
+    }
+    return rv;
+}
+
+//Formats a number for display, using fewer decimal places for larger numbers.
+//NaN values are shown as a dash.
+func formatNum(v float64) string {
+    switch {
+        case stdMath.IsNaN(v) || stdMath.IsInf(v,0): return "-";
+        case stdMath.Abs(v)>=100: return fmt.Sprintf("%.1f",v);
+        case stdMath.Abs(v)>=1: return fmt.Sprintf("%.2f",v);
+        default: return fmt.Sprintf("%.4f",v);
+    }
+}
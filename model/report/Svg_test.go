@@ -0,0 +1,63 @@
+package report
+
+import (
+	stdMath "math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/util/test"
+)
+
+func testDate(day int) time.Time {
+    return time.Date(2023,time.Month(1),day,0,0,0,0,time.UTC);
+}
+
+func TestChartSVG(t *testing.T){
+    c:=Chart{Title: "Squat <Maxes>", YLabel: "kg", Series: []ChartSeries{
+        {Name: "A", Points: []ChartPoint{
+            {Date: testDate(1), Val: 100},
+            {Date: testDate(2), Val: 110},
+            {Date: testDate(3), Val: stdMath.NaN()},
+            {Date: testDate(4), Val: 120},
+        }},
+        {Name: "B", Points: []ChartPoint{
+            {Date: testDate(1), Val: 90},{Date: testDate(4), Val: 95},
+        }},
+    }};
+    svg:=c.SVG();
+    test.BasicTest(true,strings.HasPrefix(svg,"<svg "),"The chart was not an SVG.",t);
+    test.BasicTest(true,strings.HasSuffix(svg,"</svg>"),"The SVG was not closed.",t);
+    test.BasicTest(2,strings.Count(svg,"<polyline"),
+        "The lines were not split at the NaN value.",t,
+    );
+    test.BasicTest(1,strings.Count(svg,"<circle"),
+        "A single point was not drawn as a circle.",t,
+    );
+    test.BasicTest(true,strings.Contains(svg,"Squat &lt;Maxes&gt;"),
+        "The title was not escaped.",t,
+    );
+    test.BasicTest(true,strings.Contains(svg,"01/04/2023"),
+        "The end date was not labeled.",t,
+    );
+    test.BasicTest(svg,c.SVG(),"Rendering a chart was not deterministic.",t);
+}
+
+func TestChartSVGNoData(t *testing.T){
+    svg:=Chart{Title: "Empty", Series: []ChartSeries{
+        {Name: "A", Points: []ChartPoint{{Date: testDate(1), Val: stdMath.NaN()}}},
+    }}.SVG();
+    test.BasicTest(true,strings.Contains(svg,"No data"),
+        "A chart without data was not labeled.",t,
+    );
+    test.BasicTest(0,strings.Count(svg,"<polyline"),"An empty chart had lines.",t);
+}
+
+func TestFormatNum(t *testing.T){
+    for v,exp:=range(map[float64]string{
+        123.456: "123.5", 12.345: "12.35", 0.012345: "0.0123",
+    }) {
+        test.BasicTest(exp,formatNum(v),"The number was not formatted correctly.",t);
+    }
+    test.BasicTest("-",formatNum(stdMath.NaN()),"NaN was not formatted as a dash.",t);
+}
@@ -0,0 +1,170 @@
+package report
+
+//The template used to render a report as HTML. The data is a Report.
+const htmlReportTemplate=`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Progress Report: {{.Client.FirstName}} {{.Client.LastName}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 700px; color: #222222; }
+table { border-collapse: collapse; margin: 0.5em 0 1em 0; }
+th, td { border: 1px solid #cccccc; padding: 0.25em 0.6em; text-align: right; }
+th { background: #f0f0f0; }
+td:first-child, th:first-child { text-align: left; }
+svg { display: block; margin: 0.5em 0; }
+</style>
+</head>
+<body>
+<h1>Progress Report: {{.Client.FirstName}} {{.Client.LastName}}</h1>
+<p>{{date .Start}} to {{date .End}}. Weights are in {{.Unit}}.</p>
+{{- $u:=.Unit}}
+
+<h2>Prediction Accuracy</h2>
+<table>
+<tr><th>Predictions</th><th>Skipped</th><th>MAE</th><th>RMSE</th><th>Bias</th><th>Hit Rate</th></tr>
+<tr><td>{{.Accuracy.NumPredictions}}</td><td>{{.Accuracy.NumSkipped}}</td><td>{{num .Accuracy.Mae}}</td><td>{{num .Accuracy.Rmse}}</td><td>{{num .Accuracy.Bias}}</td><td>{{pct .Accuracy.HitRate}}</td></tr>
+</table>
+
+<h2>Body Weight</h2>
+{{svg .BodyWeightChart}}
+
+<h2>Training Load</h2>
+{{svg .LoadChart}}
+{{svg .AcwrChart}}
+{{- if .Load.Warnings}}
+<table>
+<tr><th>Date</th><th>Warning</th><th>Value</th><th>Threshold</th></tr>
+{{- range .Load.Warnings}}
+<tr><td>{{date .Date}}</td><td>{{.Kind}}</td><td>{{num .Value}}</td><td>{{num .Threshold}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No load warnings.</p>
+{{- end}}
+
+{{- range .Exercises}}
+
+<h2>{{.Name}}</h2>
+<h3>Estimated Maxes</h3>
+{{svg (.MaxChart $u)}}
+{{- if .Maxes}}
+<table>
+<tr><th>Date</th><th>Max</th><th>Source</th></tr>
+{{- range .Maxes}}
+<tr><td>{{date .Date}}</td><td>{{num .Weight}}</td><td>{{source .Source}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+<h3>PRs</h3>
+{{- if .PRs}}
+<table>
+<tr><th>Date</th><th>Weight</th><th>Reps</th><th>Previous Best</th></tr>
+{{- range .PRs}}
+<tr><td>{{date .Date}}</td><td>{{num .Weight}}</td><td>{{.Reps}}</td><td>{{num .Previous}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No PRs.</p>
+{{- end}}
+<h3>Prediction Accuracy</h3>
+<table>
+<tr><th>Predictions</th><th>Skipped</th><th>MAE</th><th>RMSE</th><th>Bias</th><th>Hit Rate</th></tr>
+<tr><td>{{.Accuracy.NumPredictions}}</td><td>{{.Accuracy.NumSkipped}}</td><td>{{num .Accuracy.Mae}}</td><td>{{num .Accuracy.Rmse}}</td><td>{{num .Accuracy.Bias}}</td><td>{{pct .Accuracy.HitRate}}</td></tr>
+</table>
+<h3>Model State Constants</h3>
+{{- $e:=.}}
+{{- if .Constants}}
+<table>
+<tr><th>Constant</th><th>First</th><th>Last</th><th>Change</th></tr>
+{{- range .Constants}}
+<tr><td>{{.Name}}</td><td>{{num .First}}</td><td>{{num .Last}}</td><td>{{num .Change}}</td></tr>
+{{- end}}
+</table>
+{{- range .Constants}}
+{{svg ($e.ConstantChart .)}}
+{{- end}}
+{{- else}}
+<p>No model states.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`;
+
+//The template used to render a report as Markdown. The data is a Report.
+const markdownReportTemplate=`# Progress Report: {{md .Client.FirstName}} {{md .Client.LastName}}
+
+{{date .Start}} to {{date .End}}. Weights are in {{.Unit}}.
+{{- $u:=.Unit}}
+
+## Prediction Accuracy
+
+| Predictions | Skipped | MAE | RMSE | Bias | Hit Rate |
+|---|---|---|---|---|---|
+| {{.Accuracy.NumPredictions}} | {{.Accuracy.NumSkipped}} | {{num .Accuracy.Mae}} | {{num .Accuracy.Rmse}} | {{num .Accuracy.Bias}} | {{pct .Accuracy.HitRate}} |
+
+## Body Weight
+
+{{svg .BodyWeightChart}}
+
+## Training Load
+
+{{svg .LoadChart}}
+
+{{svg .AcwrChart}}
+{{if .Load.Warnings}}
+| Date | Warning | Value | Threshold |
+|---|---|---|---|
+{{- range .Load.Warnings}}
+| {{date .Date}} | {{.Kind}} | {{num .Value}} | {{num .Threshold}} |
+{{- end}}
+{{- else}}
+No load warnings.
+{{- end}}
+{{range .Exercises}}
+## {{md .Name}}
+
+### Estimated Maxes
+
+{{svg (.MaxChart $u)}}
+{{if .Maxes}}
+| Date | Max | Source |
+|---|---|---|
+{{- range .Maxes}}
+| {{date .Date}} | {{num .Weight}} | {{source .Source}} |
+{{- end}}
+{{end}}
+### PRs
+{{if .PRs}}
+| Date | Weight | Reps | Previous Best |
+|---|---|---|---|
+{{- range .PRs}}
+| {{date .Date}} | {{num .Weight}} | {{.Reps}} | {{num .Previous}} |
+{{- end}}
+{{- else}}
+No PRs.
+{{- end}}
+
+### Prediction Accuracy
+
+| Predictions | Skipped | MAE | RMSE | Bias | Hit Rate |
+|---|---|---|---|---|---|
+| {{.Accuracy.NumPredictions}} | {{.Accuracy.NumSkipped}} | {{num .Accuracy.Mae}} | {{num .Accuracy.Rmse}} | {{num .Accuracy.Bias}} | {{pct .Accuracy.HitRate}} |
+
+### Model State Constants
+{{$e:=.}}
+{{- if .Constants}}
+| Constant | First | Last | Change |
+|---|---|---|---|
+{{- range .Constants}}
+| {{.Name}} | {{num .First}} | {{num .Last}} | {{num .Change}} |
+{{- end}}
+{{range .Constants}}
+{{svg ($e.ConstantChart .)}}
+{{end}}
+{{- else}}
+No model states.
+{{end}}
+{{- end}}
+`;
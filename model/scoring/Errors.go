@@ -0,0 +1,17 @@
+package scoring;
+
+import (
+    customerr "github.com/barbell-math/engine/util/err"
+)
+
+var InvalidFormula,IsInvalidFormula=customerr.ErrorFactory(
+    "The formula is not a recognized scoring formula.",
+);
+
+var InvalidOptions,IsInvalidOptions=customerr.ErrorFactory(
+    "The sex, equipment, or event is not recognized.",
+);
+
+var NoBodyWeight,IsNoBodyWeight=customerr.ErrorFactory(
+    "The client does not have a body weight to score with.",
+);
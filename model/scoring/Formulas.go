@@ -0,0 +1,212 @@
+package scoring
+
+import (
+	"fmt"
+	stdMath "math"
+
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//All formulas expect the total and body weight to be in kilograms. Body
+//weights outside of the range each formula was fit on are clamped to the
+//nearest end of the range.
+type Formula int;
+const (
+    WilksOld Formula=iota
+    Wilks2020
+    Dots
+    IpfGl
+    Glossbrenner
+);
+
+func (f Formula)String() string {
+    switch f {
+        case WilksOld: return "Wilks";
+        case Wilks2020: return "Wilks 2020";
+        case Dots: return "DOTS";
+        case IpfGl: return "IPF GL";
+        case Glossbrenner: return "Glossbrenner";
+        default: return "unknown";
+    }
+}
+
+func (f Formula)Score(total float64, bodyWeight float64, o Options) (float64,error) {
+    switch f {
+        case WilksOld: return Wilks(total,bodyWeight,o);
+        case Wilks2020: return Wilks2(total,bodyWeight,o);
+        case Dots: return DOTS(total,bodyWeight,o);
+        case IpfGl: return IPFGL(total,bodyWeight,o);
+        case Glossbrenner: return GlossbrennerPoints(total,bodyWeight,o);
+        default: return 0,InvalidFormula(fmt.Sprintf("Formula: %d",f));
+    }
+}
+
+func validInputs(total float64, bodyWeight float64, o Options) error {
+    if total<0 {
+        return customerr.ValOutsideRange(fmt.Sprintf("Total: %f<0",total));
+    } else if bodyWeight<=0 {
+        return customerr.ValOutsideRange(fmt.Sprintf(
+            "Body weight: %f<=0",bodyWeight,
+        ));
+    }
+    return o.validate();
+}
+
+func clamp(v float64, low float64, high float64) float64 {
+    return stdMath.Min(stdMath.Max(v,low),high);
+}
+
+//Evaluates c[0]+c[1]x+c[2]x^2+...
+func poly(c []float64, x float64) float64 {
+    rv:=0.0;
+    for i:=len(c)-1; i>=0; i-- {
+        rv=rv*x+c[i];
+    }
+    return rv;
+}
+
+var (
+    wilksMen=[]float64{
+        -216.0475144,16.2606339,-0.002388645,
+        -0.00113732,7.01863e-06,-1.291e-08,
+    };
+    wilksWomen=[]float64{
+        594.31747775582,-27.23842536447,0.82112226871,
+        -0.00930733913,4.731582e-05,-9.054e-08,
+    };
+    wilks2Men=[]float64{
+        47.4617885411949,8.47206137941125,0.073694103462609,
+        -0.00139583381094385,7.07665973070743e-06,-1.20804336482315e-08,
+    };
+    wilks2Women=[]float64{
+        -125.425539779509,13.7121941940668,-0.0330725063103405,
+        -0.0010504000506583,9.38773881462799e-06,-2.3334613884954e-08,
+    };
+    dotsMen=[]float64{
+        -307.75076,24.0900756,-0.1918759221,0.0007391293,-0.000001093,
+    };
+    dotsWomen=[]float64{
+        -57.96288,13.6175032,-0.1126655495,0.0005158568,-0.0000010706,
+    };
+)
+
+func wilksCoefficient(bodyWeight float64, s Sex) float64 {
+    if s==Male {
+        return 500/poly(wilksMen,clamp(bodyWeight,40,201.9));
+    }
+    return 500/poly(wilksWomen,clamp(bodyWeight,26.51,154.53));
+}
+
+//The original Wilks formula.
+func Wilks(total float64, bodyWeight float64, o Options) (float64,error) {
+    if err:=validInputs(total,bodyWeight,o); err!=nil {
+        return 0,err;
+    }
+    return total*wilksCoefficient(bodyWeight,o.Sex),nil;
+}
+
+//The 2020 revision of the Wilks formula.
+func Wilks2(total float64, bodyWeight float64, o Options) (float64,error) {
+    if err:=validInputs(total,bodyWeight,o); err!=nil {
+        return 0,err;
+    }
+    if o.Sex==Male {
+        return total*600/poly(wilks2Men,clamp(bodyWeight,40,200.95)),nil;
+    }
+    return total*600/poly(wilks2Women,clamp(bodyWeight,40,150.95)),nil;
+}
+
+func DOTS(total float64, bodyWeight float64, o Options) (float64,error) {
+    if err:=validInputs(total,bodyWeight,o); err!=nil {
+        return 0,err;
+    }
+    if o.Sex==Male {
+        return total*500/poly(dotsMen,clamp(bodyWeight,40,210)),nil;
+    }
+    return total*500/poly(dotsWomen,clamp(bodyWeight,40,150)),nil;
+}
+
+//The A, B, and C parameters of the IPF GL formula indexed by sex, equipment,
+//and event.
+var ipfGlParams=[2][2][2][3]float64{
+    Male: {
+        Raw: {
+            FullPower: {1199.72839,1025.18162,0.00921},
+            BenchOnly: {320.98041,281.40258,0.01008},
+        },
+        Equipped: {
+            FullPower: {1236.25115,1449.21864,0.01644},
+            BenchOnly: {381.22073,733.79378,0.02398},
+        },
+    },
+    Female: {
+        Raw: {
+            FullPower: {610.32796,1045.59282,0.03048},
+            BenchOnly: {142.40398,442.52671,0.04724},
+        },
+        Equipped: {
+            FullPower: {758.63878,949.31382,0.02435},
+            BenchOnly: {221.82209,357.00377,0.02937},
+        },
+    },
+};
+
+//IPF GL points. The IPF does not score lifters under 35kg so a score of 0 is
+//returned for them.
+func IPFGL(total float64, bodyWeight float64, o Options) (float64,error) {
+    if err:=validInputs(total,bodyWeight,o); err!=nil {
+        return 0,err;
+    }
+    if bodyWeight<35 {
+        return 0,nil;
+    }
+    p:=ipfGlParams[o.Sex][o.Equipment][o.Event];
+    return total*100/(p[0]-p[1]*stdMath.Exp(-p[2]*bodyWeight)),nil;
+}
+
+func schwartzCoefficient(bodyWeight float64) float64 {
+    bw:=clamp(bodyWeight,40,166);
+    switch {
+        case bw<=126:
+            return 6.31926-0.262349*bw+0.51155e-2*stdMath.Pow(bw,2)-
+                0.519738e-4*stdMath.Pow(bw,3)+0.267626e-6*stdMath.Pow(bw,4)-
+                0.540132e-9*stdMath.Pow(bw,5)-0.728875e-13*stdMath.Pow(bw,6);
+        case bw<=136: return 0.5210-0.0012*(bw-125);
+        case bw<=146: return 0.5080-0.0011*(bw-135);
+        case bw<=156: return 0.4973-0.0010*(bw-145);
+        default: return 0.4862-0.0009*(bw-155);
+    }
+}
+
+func maloneCoefficient(bodyWeight float64) float64 {
+    return 106.011586323613*stdMath.Pow(stdMath.Max(bodyWeight,29.24),
+        -1.293027130579051)+0.322935585328304;
+}
+
+//Glossbrenner points, which average the Schwartz (men) or Malone (women)
+//coefficient with the Wilks coefficient. For heavy lifters the Wilks
+//coefficient is replaced with a linear fit.
+func GlossbrennerPoints(total float64, bodyWeight float64, o Options) (float64,error) {
+    if err:=validInputs(total,bodyWeight,o); err!=nil {
+        return 0,err;
+    }
+    var coeff float64;
+    if o.Sex==Male {
+        if bodyWeight<153.05 {
+            coeff=(schwartzCoefficient(bodyWeight)+
+                wilksCoefficient(bodyWeight,Male))/2;
+        } else {
+            coeff=(schwartzCoefficient(bodyWeight)-
+                0.000821668402557*bodyWeight+0.676940740094416)/2;
+        }
+    } else {
+        if bodyWeight<106.3 {
+            coeff=(maloneCoefficient(bodyWeight)+
+                wilksCoefficient(bodyWeight,Female))/2;
+        } else {
+            coeff=(maloneCoefficient(bodyWeight)-
+                0.000313738002024*bodyWeight+0.852664892884785)/2;
+        }
+    }
+    return total*coeff,nil;
+}
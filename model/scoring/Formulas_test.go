@@ -0,0 +1,105 @@
+package scoring
+
+import (
+	"testing"
+
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func scoreInRange(
+        f Formula,
+        total float64,
+        bw float64,
+        o Options,
+        low float64,
+        high float64,
+        t *testing.T) {
+    v,err:=f.Score(total,bw,o);
+    test.BasicTest(nil,err,"Scoring returned an error when it shouldn't have.",t);
+    if v<low || v>high {
+        test.FormatError([]float64{low,high},v,
+            f.String()+" score was not correct.",t,
+        );
+    }
+}
+
+func TestWilks(t *testing.T){
+    scoreInRange(WilksOld,1000,100,Options{},608.5,608.7,t);
+    scoreInRange(WilksOld,500,60,Options{Sex: Female},557.4,557.5,t);
+}
+
+func TestWilks2020(t *testing.T){
+    scoreInRange(Wilks2020,1000,100,Options{},729.3,729.4,t);
+    scoreInRange(Wilks2020,500,60,Options{Sex: Female},659.5,659.6,t);
+}
+
+func TestDots(t *testing.T){
+    scoreInRange(Dots,1000,100,Options{},615.5,615.6,t);
+    scoreInRange(Dots,500,60,Options{Sex: Female},554.2,554.3,t);
+}
+
+func TestIpfGl(t *testing.T){
+    scoreInRange(IpfGl,1000,100,Options{},126.3,126.4,t);
+    scoreInRange(IpfGl,500,60,Options{Sex: Female},113.0,113.1,t);
+    raw,_:=IpfGl.Score(1000,100,Options{});
+    equipped,_:=IpfGl.Score(1000,100,Options{Equipment: Equipped});
+    test.BasicTest(true,equipped<raw,
+        "Equipped lifters were not scored lower than raw lifters.",t,
+    );
+    bench,_:=IpfGl.Score(200,100,Options{Event: BenchOnly});
+    test.BasicTest(true,bench>raw/10,
+        "Bench only lifters were not scored with the bench only parameters.",t,
+    );
+    scoreInRange(IpfGl,100,30,Options{},0,0,t);
+}
+
+func TestGlossbrenner(t *testing.T){
+    scoreInRange(Glossbrenner,1000,100,Options{},581.2,581.3,t);
+    scoreInRange(Glossbrenner,500,60,Options{Sex: Female},492.5,492.6,t);
+    //Heavy lifters use the linear fit instead of the Wilks coefficient
+    scoreInRange(Glossbrenner,1000,160,Options{},513.5,513.6,t);
+}
+
+func TestScoreClampsBodyWeight(t *testing.T){
+    for _,f:=range([]Formula{WilksOld,Wilks2020,Dots}) {
+        v1,_:=f.Score(1000,250,Options{});
+        v2,_:=f.Score(1000,300,Options{});
+        test.BasicTest(v1,v2,f.String()+" did not clamp the body weight.",t);
+    }
+}
+
+func TestScoreInvalid(t *testing.T){
+    for _,f:=range([]Formula{WilksOld,Wilks2020,Dots,IpfGl,Glossbrenner}) {
+        if _,err:=f.Score(-1,100,Options{}); !customerr.IsValOutsideRange(err) {
+            test.FormatError(customerr.ValOutsideRange(""),err,
+                "A negative total did not return an error.",t,
+            );
+        }
+        if _,err:=f.Score(100,0,Options{}); !customerr.IsValOutsideRange(err) {
+            test.FormatError(customerr.ValOutsideRange(""),err,
+                "A body weight of 0 did not return an error.",t,
+            );
+        }
+        if _,err:=f.Score(100,100,Options{Sex: -1}); !IsInvalidOptions(err) {
+            test.FormatError(InvalidOptions(""),err,
+                "An invalid sex did not return an error.",t,
+            );
+        }
+    }
+    if _,err:=Formula(-1).Score(100,100,Options{}); !IsInvalidFormula(err) {
+        test.FormatError(InvalidFormula(""),err,
+            "An invalid formula did not return an error.",t,
+        );
+    }
+}
+
+func TestGlossbrennerContinuous(t *testing.T){
+    below,_:=Glossbrenner.Score(1000,153.04,Options{});
+    above,_:=Glossbrenner.Score(1000,153.06,Options{});
+    if d:=below-above; d>2 || d< -2 {
+        test.FormatError(below,above,
+            "Glossbrenner was not continuous across the linear fit boundary.",t,
+        );
+    }
+}
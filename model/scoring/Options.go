@@ -0,0 +1,66 @@
+package scoring
+
+import (
+	"fmt"
+)
+
+type Sex int;
+const (
+    Male Sex=iota
+    Female
+);
+
+type Equipment int;
+const (
+    Raw Equipment=iota
+    Equipped
+);
+
+type Event int;
+const (
+    FullPower Event=iota
+    BenchOnly
+);
+
+//The variant of a formula to use. Not every formula uses every option, the
+//unused options are ignored.
+type Options struct {
+    Sex Sex;
+    Equipment Equipment;
+    Event Event;
+};
+
+func (s Sex)String() string {
+    switch s {
+        case Male: return "Male";
+        case Female: return "Female";
+        default: return "unknown";
+    }
+}
+
+func (e Equipment)String() string {
+    switch e {
+        case Raw: return "Raw";
+        case Equipped: return "Equipped";
+        default: return "unknown";
+    }
+}
+
+func (e Event)String() string {
+    switch e {
+        case FullPower: return "Full Power";
+        case BenchOnly: return "Bench Only";
+        default: return "unknown";
+    }
+}
+
+func (o Options)validate() error {
+    if o.Sex!=Male && o.Sex!=Female {
+        return InvalidOptions(fmt.Sprintf("Sex: %d",o.Sex));
+    } else if o.Equipment!=Raw && o.Equipment!=Equipped {
+        return InvalidOptions(fmt.Sprintf("Equipment: %d",o.Equipment));
+    } else if o.Event!=FullPower && o.Event!=BenchOnly {
+        return InvalidOptions(fmt.Sprintf("Event: %d",o.Event));
+    }
+    return nil;
+}
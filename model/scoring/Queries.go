@@ -0,0 +1,20 @@
+package scoring
+
+func bestSingleQuery() string {
+    return `SELECT COALESCE(MAX(Weight),0)
+        FROM TrainingLog
+        WHERE TrainingLog.ClientID=$1
+            AND TrainingLog.ExerciseID=$2
+            AND TrainingLog.DatePerformed<=$3
+            AND TrainingLog.Reps=1
+            AND TrainingLog.Sets>=1;`;
+}
+
+func bodyWeightBetweenDatesQuery() string {
+    return `SELECT *
+        FROM BodyWeight
+        WHERE BodyWeight.ClientID=$1
+            AND BodyWeight.Date>=$2
+            AND BodyWeight.Date<=$3
+        ORDER BY Date ASC, Id ASC;`;
+}
@@ -0,0 +1,141 @@
+package scoring
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	"github.com/barbell-math/engine/util/algo/iter"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//Where the total that is scored comes from.
+//  - BestTotal: the sum of the heaviest single performed for each lift on or
+//    before the date.
+//  - EstimatedTotal: the sum of the maxes in effect for each lift on the date,
+//    taken from the clients max history.
+type TotalSource int;
+const (
+    BestTotal TotalSource=iota
+    EstimatedTotal
+);
+
+type Scorer struct {
+    formula Formula;
+    opts Options;
+    src TotalSource;
+    exerciseIDs []int;
+};
+
+//A single score along with the values it was calculated from.
+type Score struct {
+    Date time.Time;
+    BodyWeight float64;
+    Total float64;
+    Points float64;
+};
+
+//Note - THE ORDER OF THE STRUCT FIELDS MUST MATCH THE ORDER OF THE VALUES
+//IN THE QUERY.
+type bestSingle struct {
+    Weight float64;
+};
+
+//The exercise ids are the lifts that make up the total. The weights in the
+//database are expected to be in kilograms.
+func NewScorer(
+        f Formula,
+        o Options,
+        src TotalSource,
+        exerciseIDs ...int) (Scorer,error) {
+    rv:=Scorer{
+        formula: f,
+        opts: o,
+        src: src,
+        exerciseIDs: append([]int{},exerciseIDs...),
+    };
+    if f<WilksOld || f>Glossbrenner {
+        return rv,InvalidFormula(fmt.Sprintf("Formula: %d",f));
+    } else if err:=o.validate(); err!=nil {
+        return rv,err;
+    } else if src!=BestTotal && src!=EstimatedTotal {
+        return rv,customerr.InvalidValue(fmt.Sprintf("Total source: %d",src));
+    } else if len(exerciseIDs)==0 {
+        return rv,customerr.InvalidValue("at least one exercise is needed");
+    }
+    return rv,nil;
+}
+
+//Scores the client on the given date using the body weight entry that is
+//closest to the date.
+func (s Scorer)ScoreOnDate(d *db.DB, c *db.Client, date time.Time) (Score,error) {
+    bw,err:=db.GetBodyWeightNearDate(d,c.Id,date);
+    if err==sql.ErrNoRows {
+        return Score{},NoBodyWeight(fmt.Sprintf("Client: %d",c.Id));
+    } else if err!=nil {
+        return Score{},err;
+    }
+    return s.score(d,c,date,float64(bw.Weight));
+}
+
+//Scores the client on every date they have a body weight entry for between the
+//start and end dates (inclusive).
+func (s Scorer)History(
+        d *db.DB,
+        c *db.Client,
+        start time.Time,
+        end time.Time) ([]Score,error) {
+    rv:=[]Score{};
+    err:=db.CustomReadQuery[db.BodyWeight](d,
+        bodyWeightBetweenDatesQuery(),[]any{c.Id,start,end},
+    ).ForEach(func(index int, val *db.BodyWeight) (iter.IteratorFeedback,error) {
+        iterRv,err:=s.score(d,c,val.Date,float64(val.Weight));
+        rv=append(rv,iterRv);
+        return iter.Continue,err;
+    });
+    return rv,err;
+}
+
+func (s Scorer)score(
+        d *db.DB,
+        c *db.Client,
+        date time.Time,
+        bodyWeight float64) (Score,error) {
+    rv:=Score{Date: date, BodyWeight: bodyWeight};
+    for _,eId:=range(s.exerciseIDs) {
+        w,err:=s.liftTotal(d,c,eId,date);
+        if err!=nil {
+            return rv,err;
+        }
+        rv.Total+=w;
+    }
+    var err error;
+    rv.Points,err=s.formula.Score(rv.Total,bodyWeight,s.opts);
+    return rv,err;
+}
+
+func (s Scorer)liftTotal(
+        d *db.DB,
+        c *db.Client,
+        eId int,
+        date time.Time) (float64,error) {
+    switch s.src {
+        case EstimatedTotal:
+            m,err:=db.GetMaxOnDate(d,c.Id,eId,date);
+            if err==sql.ErrNoRows {
+                return 0,nil;
+            }
+            return m.Weight,err;
+        default:
+            b,err,found:=db.CustomReadQuery[bestSingle](d,
+                bestSingleQuery(),[]any{c.Id,eId,date},
+            ).Nth(0);
+            if err==sql.ErrNoRows || (err==nil && !found) {
+                return 0,nil;
+            } else if err!=nil {
+                return 0,err;
+            }
+            return b.Weight,nil;
+    }
+}
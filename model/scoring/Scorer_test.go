@@ -0,0 +1,78 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/barbell-math/engine/db"
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestNewScorerInvalid(t *testing.T){
+    if _,err:=NewScorer(-1,Options{},BestTotal,1); !IsInvalidFormula(err) {
+        test.FormatError(InvalidFormula(""),err,
+            "Creating a scorer with an invalid formula did not error.",t,
+        );
+    }
+    if _,err:=NewScorer(Dots,Options{Event: -1},BestTotal,1); !IsInvalidOptions(err) {
+        test.FormatError(InvalidOptions(""),err,
+            "Creating a scorer with invalid options did not error.",t,
+        );
+    }
+    if _,err:=NewScorer(Dots,Options{},-1,1); !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Creating a scorer with an invalid total source did not error.",t,
+        );
+    }
+    if _,err:=NewScorer(Dots,Options{},BestTotal); !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "Creating a scorer without exercises did not error.",t,
+        );
+    }
+}
+
+func TestScoreOnDate(t *testing.T){
+    c,_:=db.GetClientByEmail(&testDB,"one");
+    day:=time.Date(2030,time.January,10,0,0,0,0,time.UTC);
+    _,err:=db.Create(&testDB,
+        db.BodyWeight{ClientID: c.Id, Weight: 100, Date: day},
+        db.BodyWeight{ClientID: c.Id, Weight: 102, Date: day.AddDate(0,0,14)},
+    );
+    test.BasicTest(nil,err,"Database was not setup correctly to run test.",t);
+    _,err=db.Create(&testDB,
+        db.ExerciseMax{
+            ClientID: c.Id, ExerciseID: 1, Date: day,
+            Weight: 300, Source: int(db.TestedMax),
+        }, db.ExerciseMax{
+            ClientID: c.Id, ExerciseID: 2, Date: day,
+            Weight: 200, Source: int(db.TestedMax),
+        },
+    );
+    test.BasicTest(nil,err,"Database was not setup correctly to run test.",t);
+    s,_:=NewScorer(Dots,Options{},EstimatedTotal,1,2);
+    res,err:=s.ScoreOnDate(&testDB,&c,day.AddDate(0,0,2));
+    test.BasicTest(nil,err,"Scoring returned an error when it shouldn't have.",t);
+    test.BasicTest(float64(100),res.BodyWeight,
+        "The closest body weight was not used.",t,
+    );
+    test.BasicTest(float64(500),res.Total,"The estimated total was not correct.",t);
+    exp,_:=DOTS(500,100,Options{});
+    test.BasicTest(exp,res.Points,"The score was not correct.",t);
+    hist,err:=s.History(&testDB,&c,day,day.AddDate(0,0,14));
+    test.BasicTest(nil,err,"Scoring history returned an error.",t);
+    test.BasicTest(2,len(hist),"Not every body weight entry was scored.",t);
+    test.BasicTest(true,hist[1].Points<hist[0].Points,
+        "A heavier body weight with the same total did not lower the score.",t,
+    );
+}
+
+func TestScoreOnDateNoBodyWeight(t *testing.T){
+    s,_:=NewScorer(Dots,Options{},BestTotal,1);
+    _,err:=s.ScoreOnDate(&testDB,&db.Client{Id: -1},time.Now());
+    if !IsNoBodyWeight(err) {
+        test.FormatError(NoBodyWeight(""),err,
+            "Scoring a client without a body weight did not error.",t,
+        );
+    }
+}
@@ -8,6 +8,7 @@ import (
     "github.com/barbell-math/engine/util/algo/iter"
     "github.com/barbell-math/engine/util/io/csv"
     customerr "github.com/barbell-math/engine/util/err"
+    potSurf "github.com/barbell-math/engine/model/potentialSurface"
 )
 
 
@@ -71,11 +72,7 @@ func uploadTestData(testDB *db.DB, progressLineHeader string) error {
                 testDB,progressLineHeader,"StateGeneratorTestData",
             ));
         }, func(r ...any) (any,error) {
-            return nil,csv.CSVToStruct[db.PotentialSurface](csv.CSVFileSplitter(
-                settings.PotentialSurfaceInitData(),',','#',
-            ),"1/2/2006").ForEach(uploadFunc[db.PotentialSurface](
-                testDB,progressLineHeader,"PotentialSurfaceTestData",
-            ));
+            return nil,potSurf.SyncSurfaceTable(testDB);
         }, func(r ...any) (any,error) {
             return nil,csv.CSVToStruct[db.ExerciseType](csv.CSVFileSplitter(
                 settings.ExerciseTypeInitData(),',','#',
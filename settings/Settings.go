@@ -28,7 +28,6 @@ type SetupData struct {
     ExerciseInit string `json:"exerciseInit"`;
     ClientInit string `json:"clientInit"`;
     StateGeneratorInit string `json:"stateGeneratorInit"`;
-    PotentialSurfaceInit string `json:"potentialSurfaceInit"`;
     RotationInit string `json:"rotationInit"`;
     TrainingLogInit string `json:"trainingLogInit"`;
 };
@@ -105,9 +104,6 @@ func ClientInitData() string {
 func StateGeneratorInitData() string {
     return s.InitData.StateGeneratorInit;
 }
-func PotentialSurfaceInitData() string {
-    return s.InitData.PotentialSurfaceInit;
-}
 func RotationInitData() string {
     return s.InitData.RotationInit;
 }
@@ -151,11 +147,6 @@ func valid(set *Settings) (bool,error) {
             return rv,customerr.ErrorOnBool(
                 rv,SettingsFileNotFound(fmt.Sprintf("StateGeneratorInit | %v",err)),
             );
-        }, func(r ...any) (any,error) {
-            rv,err:=customIO.FileExists(set.InitData.PotentialSurfaceInit);
-            return rv,customerr.ErrorOnBool(
-                rv,SettingsFileNotFound(fmt.Sprintf("PotentialSurfaceInit | %v",err)),
-            );
         }, func(r ...any) (any,error) {
             rv,err:=customIO.FileExists(set.InitData.RotationInit);
             return rv,customerr.ErrorOnBool(
@@ -188,7 +179,6 @@ func _copy() Settings {
         ExerciseInit: s.InitData.ExerciseInit,
         ClientInit: s.InitData.ClientInit,
         StateGeneratorInit: s.InitData.StateGeneratorInit,
-        PotentialSurfaceInit: s.InitData.PotentialSurfaceInit,
         RotationInit: s.InitData.RotationInit,
         TrainingLogInit: s.InitData.TrainingLogInit,
     };
@@ -50,3 +50,34 @@ func Splitter(token string) bufio.SplitFunc {
         return 0, nil, nil;
     }
 }
+
+//The same as Splitter except that tokens between a pair of quotes are ignored.
+//This allows splitting a sql script on ';' without splitting function bodies
+//that are quoted with '$$'.
+func QuotedSplitter(token string, quote string) bufio.SplitFunc {
+    temp,q:=[]byte(token),[]byte(quote);
+    return func (data []byte, atEOF bool) (advance int, token []byte, err error){
+        if atEOF && len(data)==0 {
+            return 0, nil, nil;
+        }
+        for start:=0; start<len(data); {
+            i:=bytes.Index(data[start:],temp);
+            j:=bytes.Index(data[start:],q);
+            if i>=0 && (j<0 || i<j) {
+                return start+i+len(temp), data[0:start+i], nil;
+            } else if j<0 {
+                break;
+            }
+            //Skip to the end of the quoted section
+            k:=bytes.Index(data[start+j+len(q):],q);
+            if k<0 {
+                break;
+            }
+            start+=j+len(q)+k+len(q);
+        }
+        if atEOF {
+            return len(data),data,nil;
+        }
+        return 0, nil, nil;
+    }
+}
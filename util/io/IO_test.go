@@ -2,7 +2,9 @@ package io;
 
 import (
     "os"
+    "bufio"
     "errors"
+    "strings"
     "testing"
     "github.com/barbell-math/engine/util/test"
 )
@@ -27,3 +29,31 @@ func TestFileExistsDir(t *testing.T){
     test.BasicTest(nil,e,"An error was generated when it shouldn't have been.",t);
 }
 
+
+func TestQuotedSplitter(t *testing.T){
+    scanner:=bufio.NewScanner(strings.NewReader(
+        "a;b$$c;d$$e;$$f;$$;g",
+    ));
+    scanner.Split(QuotedSplitter(";","$$"));
+    exp:=[]string{"a","b$$c;d$$e","$$f;$$","g"};
+    i:=0;
+    for ; scanner.Scan(); i++ {
+        if i<len(exp) {
+            test.BasicTest(exp[i],scanner.Text(),"The split was not correct.",t);
+        }
+    }
+    test.BasicTest(len(exp),i,"The wrong number of tokens were returned.",t);
+}
+
+func TestQuotedSplitterUnterminatedQuote(t *testing.T){
+    scanner:=bufio.NewScanner(strings.NewReader("a;b$$c;d"));
+    scanner.Split(QuotedSplitter(";","$$"));
+    exp:=[]string{"a","b$$c;d"};
+    i:=0;
+    for ; scanner.Scan(); i++ {
+        if i<len(exp) {
+            test.BasicTest(exp[i],scanner.Text(),"The split was not correct.",t);
+        }
+    }
+    test.BasicTest(len(exp),i,"The wrong number of tokens were returned.",t);
+}
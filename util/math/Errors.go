@@ -27,3 +27,7 @@ var MatrixSingularToWorkingPrecision,IsMatrixSingularToWorkingPrecision=customer
 var MissingVariable,IsMissingVariable=customerr.ErrorFactory(
     "The requested independent variable is not present.",
 );
+
+var DidNotConverge,IsDidNotConverge=customerr.ErrorFactory(
+    "An iterative method did not converge.",
+);
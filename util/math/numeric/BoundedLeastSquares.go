@@ -0,0 +1,198 @@
+package numeric
+
+import (
+	"fmt"
+
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//Gradients smaller than this (relative to the right hand side) are treated as
+//zero so round off error does not free a variable that is already optimal.
+var boundedGradientTol float64=1e-10;
+
+//Checks that there is one bound per constant and that every lower bound is
+//less than or equal to its upper bound.
+func ValidBounds[N math.Number](
+        numConstants int,
+        bounds []dataStruct.Pair[N,N]) error {
+    if len(bounds)!=numConstants {
+        return customerr.DimensionsDoNotAgree(fmt.Sprintf(
+            "Need one bound per constant | Constants: %d Bounds: %d",
+            numConstants,len(bounds),
+        ));
+    }
+    for i,b:=range(bounds) {
+        if b.A>b.B {
+            return customerr.ValOutsideRange(fmt.Sprintf(
+                "Bound %d: lower bound > upper bound",i,
+            ));
+        }
+    }
+    return nil;
+}
+
+//Solves the bound constrained least squares problem given its normal
+//equations:
+//  min 1/2*x^T*A*x-b^T*x such that bounds[i].A<=x_i<=bounds[i].B
+//The bounded variable least squares active set method is used. Variables are
+//either free or held at one of their bounds. The free variables are solved for
+//with the held variables fixed, and if that solution leaves the bounds the
+//step is shortened until the first variable reaches its bound, which is then
+//held. Once the free solution is within its bounds the held variable whose
+//gradient points the most into the feasible region is freed. The method stops
+//when no held variable can improve the solution. The returned solution is
+//always within the bounds, an error is returned if a free sub-system was
+//singular or the solution did not converge in maxIter iterations.
+func BoundedLeastSquares[N math.Number](
+        a *Matrix[N],
+        b *Matrix[N],
+        bounds []dataStruct.Pair[N,N],
+        maxIter int) (Matrix[N],error) {
+    n:=a.Rows();
+    rv:=NewMatrix(n,1,ZeroFill[N]);
+    if err:=squareMatrixErrorCheck(a); err!=nil {
+        return rv,err;
+    } else if err:=matchingInnerDimensionsErrorCheck(a,b); err!=nil {
+        return rv,err;
+    } else if err:=ValidBounds(n,bounds); err!=nil {
+        return rv,err;
+    }
+    free:=make([]bool,n);
+    for i,_:=range(free) {
+        rv.V[i][0]=Constrain(N(0),bounds[i]);
+        free[i]=rv.V[i][0]>bounds[i].A && rv.V[i][0]<bounds[i].B;
+    }
+    for iter:=0; iter<maxIter; iter++ {
+        if err:=boundedFreeSolve(a,b,bounds,free,&rv,maxIter); err!=nil {
+            return rv,err;
+        }
+        j:=boundedFreeCandidate(a,b,bounds,free,&rv);
+        if j<0 {
+            return rv,nil;
+        }
+        free[j]=true;
+    }
+    return rv,math.DidNotConverge(fmt.Sprintf(
+        "Bounded least squares did not converge in %d iterations",maxIter,
+    ));
+}
+
+//Moves the free variables towards the solution of the free sub-system,
+//holding any variable that reaches its bound, until the free solution is
+//within its bounds.
+func boundedFreeSolve[N math.Number](
+        a *Matrix[N],
+        b *Matrix[N],
+        bounds []dataStruct.Pair[N,N],
+        free []bool,
+        x *Matrix[N],
+        maxIter int) error {
+    for iter:=0; iter<maxIter; iter++ {
+        idx:=[]int{};
+        for i,f:=range(free) {
+            if f {
+                idx=append(idx,i);
+            }
+        }
+        if len(idx)==0 {
+            return nil;
+        }
+        z,err:=boundedSubSystem(a,b,free,idx,x);
+        if err!=nil {
+            return err;
+        }
+        step,hit:=N(1),-1;
+        for i,j:=range(idx) {
+            diff:=z[i]-x.V[j][0];
+            var s N=step;
+            if z[i]<bounds[j].A && diff!=N(0) {
+                s=(bounds[j].A-x.V[j][0])/diff;
+            } else if z[i]>bounds[j].B && diff!=N(0) {
+                s=(bounds[j].B-x.V[j][0])/diff;
+            }
+            if s<step {
+                step,hit=s,j;
+            }
+        }
+        for i,j:=range(idx) {
+            x.V[j][0]=Constrain(x.V[j][0]+step*(z[i]-x.V[j][0]),bounds[j]);
+        }
+        if hit<0 {
+            return nil;
+        }
+        //Hold every free variable that is now at a bound
+        for _,j:=range(idx) {
+            if x.V[j][0]<=bounds[j].A || x.V[j][0]>=bounds[j].B {
+                free[j]=false;
+            }
+        }
+        free[hit]=false;
+    }
+    return math.DidNotConverge("Bounded least squares free solve did not converge");
+}
+
+//Solves A_FF*z=b_F-A_FH*x_H where F are the free and H the held variables.
+func boundedSubSystem[N math.Number](
+        a *Matrix[N],
+        b *Matrix[N],
+        free []bool,
+        idx []int,
+        x *Matrix[N]) ([]N,error) {
+    sub:=NewMatrix(len(idx),len(idx),func(r int, c int) N {
+        return a.V[idx[r]][idx[c]];
+    });
+    rhs:=NewMatrix(len(idx),1,func(r int, c int) N {
+        rv:=b.V[idx[r]][0];
+        for j,f:=range(free) {
+            if !f {
+                rv-=a.V[idx[r]][j]*x.V[j][0];
+            }
+        }
+        return rv;
+    });
+    if _,err:=sub.Inverse(); err!=nil {
+        return nil,err;
+    }
+    sub.Mul(&rhs);
+    rv:=make([]N,len(idx));
+    for i,_:=range(rv) {
+        rv[i]=sub.V[i][0];
+    }
+    return rv,nil;
+}
+
+//Returns the held variable whose gradient points the most into its feasible
+//region, or -1 if there is none. The gradient of the objective is A*x-b.
+func boundedFreeCandidate[N math.Number](
+        a *Matrix[N],
+        b *Matrix[N],
+        bounds []dataStruct.Pair[N,N],
+        free []bool,
+        x *Matrix[N]) int {
+    rv:=-1;
+    var best N=N(0);
+    tol:=N(boundedGradientTol);
+    for j,f:=range(free) {
+        if f || bounds[j].A==bounds[j].B {
+            continue;
+        }
+        g:=-b.V[j][0];
+        for k:=0; k<a.Cols(); k++ {
+            g+=a.V[j][k]*x.V[k][0];
+        }
+        //Decreasing the variable is only possible when it is at its upper
+        //bound, increasing it only when it is at its lower bound.
+        var improvement N=N(0);
+        if x.V[j][0]<=bounds[j].A && g<N(0) {
+            improvement=-g;
+        } else if x.V[j][0]>=bounds[j].B && g>N(0) {
+            improvement=g;
+        }
+        if improvement>best && improvement>tol*(1+Abs(b.V[j][0])) {
+            rv,best=j,improvement;
+        }
+    }
+    return rv;
+}
@@ -0,0 +1,167 @@
+package numeric
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func positiveBounds(n int) []dataStruct.Pair[float64,float64] {
+    rv:=make([]dataStruct.Pair[float64,float64],n);
+    for i,_:=range(rv) {
+        rv[i]=PositiveConstraint[float64]();
+    }
+    return rv;
+}
+
+//Builds the normal equations of a small, well conditioned, data set.
+func boundedTestSystem() (Matrix[float64],Matrix[float64]) {
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x","z"},"y"));
+    for i:=0; i<12; i++ {
+        x,z:=float64(i%4),float64((i*5)%7);
+        l.UpdateSummations(map[string]float64{
+            "x": x, "z": z, "y": 3*x-2*z-1+0.1*stdMath.Sin(float64(i)),
+        });
+    }
+    return l.a.Copy(),l.b.Copy();
+}
+
+//Checks the KKT conditions of the bound constrained problem.
+func checkBoundedKKT(
+        a Matrix[float64],
+        b Matrix[float64],
+        x Matrix[float64],
+        bounds []dataStruct.Pair[float64,float64],
+        t *testing.T){
+    for j,bound:=range(bounds) {
+        g:=-b.V[j][0];
+        for k:=0; k<a.Cols(); k++ {
+            g+=a.V[j][k]*x.V[k][0];
+        }
+        v:=x.V[j][0];
+        test.BasicTest(true,v>=bound.A && v<=bound.B,
+            "The solution was not within the bounds.",t,
+        );
+        if v>bound.A && v<bound.B {
+            test.BasicTest(true,stdMath.Abs(g)<1e-8,
+                "A free variable did not have a zero gradient.",t,
+            );
+        } else if v==bound.A && v!=bound.B {
+            test.BasicTest(true,g>=-1e-8,
+                "A variable at its lower bound could improve the solution.",t,
+            );
+        } else if v==bound.B && v!=bound.A {
+            test.BasicTest(true,g<=1e-8,
+                "A variable at its upper bound could improve the solution.",t,
+            );
+        }
+    }
+}
+
+func TestBoundedLeastSquaresInvalid(t *testing.T){
+    a,b:=boundedTestSystem();
+    _,err:=BoundedLeastSquares(&a,&b,positiveBounds(2),100);
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "The wrong number of bounds did not return an error.",t,
+        );
+    }
+    bounds:=positiveBounds(3);
+    bounds[1]=dataStruct.Pair[float64,float64]{A: 1, B: 0};
+    _,err=BoundedLeastSquares(&a,&b,bounds,100);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid bound did not return an error.",t,
+        );
+    }
+    nonSquare:=NewMatrix(2,3,ZeroFill[float64]);
+    _,err=BoundedLeastSquares(&nonSquare,&b,positiveBounds(2),100);
+    test.BasicTest(true,err!=nil,"A non square matrix did not return an error.",t);
+}
+
+func TestBoundedLeastSquaresUnconstrained(t *testing.T){
+    a,b:=boundedTestSystem();
+    bounds:=[]dataStruct.Pair[float64,float64]{
+        NoOpConstraint[float64](),NoOpConstraint[float64](),NoOpConstraint[float64](),
+    };
+    x,err:=BoundedLeastSquares(&a,&b,bounds,100);
+    test.BasicTest(nil,err,"Bounded least squares returned an error.",t);
+    exp:=a.Copy();
+    exp.Inverse();
+    exp.Mul(&b);
+    ok,_:=x.Equals(&exp,1e-9);
+    test.BasicTest(true,ok,"An unconstrained problem did not match the inverse.",t);
+}
+
+func TestBoundedLeastSquaresNonNegative(t *testing.T){
+    a,b:=boundedTestSystem();
+    bounds:=positiveBounds(3);
+    x,err:=BoundedLeastSquares(&a,&b,bounds,100);
+    test.BasicTest(nil,err,"Bounded least squares returned an error.",t);
+    test.BasicTest(0.0,x.V[1][0],"The negative constant was not held at zero.",t);
+    test.BasicTest(true,x.V[0][0]>0,"The positive constant was not free.",t);
+    checkBoundedKKT(a,b,x,bounds,t);
+}
+
+func TestBoundedLeastSquaresBoxBounds(t *testing.T){
+    a,b:=boundedTestSystem();
+    bounds:=[]dataStruct.Pair[float64,float64]{
+        dataStruct.Pair[float64,float64]{A: 0, B: 2},
+        dataStruct.Pair[float64,float64]{A: -1, B: 1},
+        dataStruct.Pair[float64,float64]{A: -5, B: 5},
+    };
+    x,err:=BoundedLeastSquares(&a,&b,bounds,100);
+    test.BasicTest(nil,err,"Bounded least squares returned an error.",t);
+    test.BasicTest(2.0,x.V[0][0],"The constant was not held at its upper bound.",t);
+    test.BasicTest(-1.0,x.V[1][0],"The constant was not held at its lower bound.",t);
+    checkBoundedKKT(a,b,x,bounds,t);
+}
+
+func TestBoundedLeastSquaresDidNotConverge(t *testing.T){
+    a,b:=boundedTestSystem();
+    _,err:=BoundedLeastSquares(&a,&b,positiveBounds(3),0);
+    if !math.IsDidNotConverge(err) {
+        test.FormatError(math.DidNotConverge(""),err,
+            "Running out of iterations did not return an error.",t,
+        );
+    }
+}
+
+func TestLinearRegBounds(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for i:=1; i<=4; i++ {
+        l.UpdateSummations(map[string]float64{"x": float64(i), "y": 2*float64(i)-1});
+    }
+    err:=l.SetBounds(positiveBounds(1));
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "The wrong number of bounds did not return an error.",t,
+        );
+    }
+    test.BasicTest(nil,l.SetBounds(positiveBounds(2)),
+        "Setting valid bounds returned an error.",t,
+    );
+    res,_,err:=l.Run();
+    test.BasicTest(nil,err,"Running bounded lin reg returned an error.",t);
+    //With the intercept held at zero the slope is sum(xy)/sum(x^2)=50/30
+    closeTo(50.0/30.0,res.GetConstant(0),1e-9,
+        "The slope was not re-fit with the intercept at its bound.",t,
+    );
+    test.BasicTest(0.0,res.GetConstant(1),"The intercept was not held at zero.",t);
+    //SSE=sum((y-5/3x)^2)
+    sse:=0.0;
+    for i:=1; i<=4; i++ {
+        r:=2*float64(i)-1-50.0/30.0*float64(i);
+        sse+=r*r;
+    }
+    closeTo(sse/2,res.GetResidualVariance(),1e-9,
+        "The residual variance did not match the bounded solution.",t,
+    );
+    test.BasicTest(nil,l.SetBounds(nil),"Removing the bounds returned an error.",t);
+    res,_,_=l.Run();
+    closeTo(-1,res.GetConstant(1),1e-9,"Removing the bounds did not work.",t);
+}
@@ -2,6 +2,7 @@ package numeric
 
 import (
 	stdMath "math"
+	"sort"
 
 	"github.com/barbell-math/engine/util/algo/iter"
 	"github.com/barbell-math/engine/util/dataStruct"
@@ -38,6 +39,22 @@ func Abs[N math.Number](v N) N {
     return v;
 }
 
+//Returns the median of the values, the mean of the middle two values is used
+//when there is an even number of values. Zero is returned if there are no
+//values.
+func Median[N math.Number](vals ...N) N {
+    if len(vals)==0 {
+        return N(0);
+    }
+    tmp:=append([]N{},vals...);
+    sort.Slice(tmp,func(i int, j int) bool { return tmp[i]<tmp[j]; });
+    if l:=len(tmp); l%2==1 {
+        return tmp[l/2];
+    } else {
+        return (tmp[l/2-1]+tmp[l/2])/N(2);
+    }
+}
+
 func SqErr[N math.Number](act []N, given []N) ([]N,error) {
     if err:=customerr.ArrayDimsArgree(
         act,given,"MSE requires lists of equal length.",
@@ -62,6 +79,47 @@ func MeanSqErr[N math.Number](act []N, given []N) (N,error) {
     return sum/N(len(act)),err;
 }
 
+//The same as SqErr except that each squared error is multiplied by its weight.
+//Weights must be >=0.
+func WeightedSqErr[N math.Number](
+        act []N,
+        given []N,
+        weights []N) ([]N,error) {
+    if err:=customerr.ArrayDimsArgree(
+        act,weights,"Weighted MSE requires a weight per value.",
+    ); err!=nil {
+        return []N{},err;
+    }
+    rv,err:=SqErr(act,given);
+    for i,_:=range(rv) {
+        if weights[i]<N(0) {
+            return []N{},customerr.ValOutsideRange("weights must be >=0");
+        }
+        rv[i]*=weights[i];
+    }
+    return rv,err;
+}
+//The weighted mean of the squared errors:
+//  sum(w_i*(act_i-given_i)^2)/sum(w_i)
+func WeightedMeanSqErr[N math.Number](
+        act []N,
+        given []N,
+        weights []N) (N,error) {
+    var sum,wSum N=N(0),N(0);
+    sqErrs,err:=WeightedSqErr(act,given,weights);
+    if err!=nil || len(sqErrs)==0 {
+        return sum,err;
+    }
+    for i,v:=range(sqErrs) {
+        sum+=v;
+        wSum+=weights[i];
+    }
+    if wSum==N(0) {
+        return sum,math.DivByZero("the sum of the weights is zero");
+    }
+    return sum/wSum,err;
+}
+
 func NoOpConstraint[N math.Number]() dataStruct.Pair[N,N] {
     return dataStruct.Pair[N, N]{A: N(stdMath.Inf(-1)), B: N(stdMath.Inf(1))};
 }
@@ -3,6 +3,7 @@ package numeric;
 import (
     "testing"
     "github.com/barbell-math/engine/util/test"
+    "github.com/barbell-math/engine/util/math"
     customerr "github.com/barbell-math/engine/util/err"
 )
 
@@ -56,6 +57,54 @@ func TestMeanSqErrZeroLength(t *testing.T){
     test.BasicTest(0 ,res,"Incorrect MSE was returned.",t);
 }
 
+func TestWeightedSqErrIncorrectDimensions(t *testing.T){
+    _,err:=WeightedSqErr([]int{1,2},[]int{1,2},[]int{1});
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "The incorrect error was raised given unequal weight lengths.",t,
+        );
+    }
+    _,err=WeightedSqErr([]int{1,2},[]int{1},[]int{1,1});
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "The incorrect error was raised given unequal input lengths.",t,
+        );
+    }
+}
+
+func TestWeightedSqErr(t *testing.T){
+    res,err:=WeightedSqErr([]int{1,1},[]int{3,3},[]int{2,0});
+    test.BasicTest(nil,err,"Error was raised when it shouldn't have been.",t);
+    test.BasicTest(8,res[0],"Incorrect weighted SE was returned.",t);
+    test.BasicTest(0,res[1],"Incorrect weighted SE was returned.",t);
+    _,err=WeightedSqErr([]int{1},[]int{3},[]int{-1});
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A negative weight did not raise an error.",t,
+        );
+    }
+}
+
+func TestWeightedMeanSqErr(t *testing.T){
+    res,err:=WeightedMeanSqErr(
+        []float64{1,1},[]float64{3,2},[]float64{1,3},
+    );
+    test.BasicTest(nil,err,"Error was raised when it shouldn't have been.",t);
+    test.BasicTest(7.0/4.0,res,"Incorrect weighted MSE was returned.",t);
+    iRes,err:=WeightedMeanSqErr([]int{1,1},[]int{3,3},[]int{1,1});
+    test.BasicTest(nil,err,"Error was raised when it shouldn't have been.",t);
+    test.BasicTest(4,iRes,"Equal weights did not match the MSE.",t);
+    _,err=WeightedMeanSqErr([]int{1},[]int{3},[]int{0});
+    if !math.IsDivByZero(err) {
+        test.FormatError(math.DivByZero(""),err,
+            "Zero total weight did not raise an error.",t,
+        );
+    }
+    iRes,err=WeightedMeanSqErr([]int{},[]int{},[]int{});
+    test.BasicTest(nil,err,"Error was raised when it shouldn't have been.",t);
+    test.BasicTest(0,iRes,"Incorrect weighted MSE was returned.",t);
+}
+
 func TestRangeNoValues(t *testing.T){
     sequence,err:=Range(0,0,0).Collect();
     test.BasicTest(0,len(sequence),
@@ -164,3 +213,11 @@ func TestRangeNegativeStep(t *testing.T){
     test.BasicTest(5,sequence[0],"Range produced incorrect values.",t);
     test.BasicTest(nil,err,"Error was raised when it shouldn't have been.",t);
 }
+
+func TestMedian(t *testing.T){
+    test.BasicTest(0,Median[int](),"The median of no values was not zero.",t);
+    test.BasicTest(3,Median(5,1,3),"The median of an odd list was not correct.",t);
+    test.BasicTest(2.5,Median(4.0,1.0,3.0,2.0),
+        "The median of an even list was not correct.",t,
+    );
+}
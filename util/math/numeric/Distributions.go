@@ -0,0 +1,126 @@
+package numeric
+
+import (
+	"fmt"
+	stdMath "math"
+
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//Returns x such that P(X<=x)=p for a standard normal variable X.
+func NormalQuantile(p float64) (float64,error) {
+    if p<=0 || p>=1 {
+        return stdMath.NaN(),customerr.ValOutsideRange(fmt.Sprintf(
+            "p: %f not in (0,1)",p,
+        ));
+    }
+    return stdMath.Sqrt2*stdMath.Erfinv(2*p-1),nil;
+}
+
+//Returns P(T<=t) for a student t variable T with the given degrees of freedom.
+func StudentTCdf(t float64, dof float64) (float64,error) {
+    if dof<=0 {
+        return stdMath.NaN(),customerr.ValOutsideRange(fmt.Sprintf(
+            "Degrees of freedom: %f<=0",dof,
+        ));
+    }
+    tail:=0.5*regularizedIncompleteBeta(dof/(dof+t*t),dof/2,0.5);
+    if t>0 {
+        return 1-tail,nil;
+    }
+    return tail,nil;
+}
+
+//Returns t such that P(T<=t)=p for a student t variable T with the given
+//degrees of freedom. The cdf is monotonic so the quantile is found by
+//bisection.
+func StudentTQuantile(p float64, dof float64) (float64,error) {
+    if p<=0 || p>=1 {
+        return stdMath.NaN(),customerr.ValOutsideRange(fmt.Sprintf(
+            "p: %f not in (0,1)",p,
+        ));
+    } else if dof<=0 {
+        return stdMath.NaN(),customerr.ValOutsideRange(fmt.Sprintf(
+            "Degrees of freedom: %f<=0",dof,
+        ));
+    }
+    if p<0.5 {
+        rv,err:=StudentTQuantile(1-p,dof);
+        return -rv,err;
+    }
+    low,high:=0.0,1.0;
+    for v,_:=StudentTCdf(high,dof); v<p; v,_=StudentTCdf(high,dof) {
+        low,high=high,2*high;
+    }
+    for i:=0; i<200 && high-low>1e-12*high; i++ {
+        mid:=(low+high)/2;
+        if v,_:=StudentTCdf(mid,dof); v<p {
+            low=mid;
+        } else {
+            high=mid;
+        }
+    }
+    return (low+high)/2,nil;
+}
+
+//The regularized incomplete beta function I_x(a,b), evaluated with the
+//continued fraction expansion. The symmetry relation I_x(a,b)=1-I_{1-x}(b,a)
+//is used to keep the continued fraction in the range where it converges
+//quickly.
+func regularizedIncompleteBeta(x float64, a float64, b float64) float64 {
+    if x<=0 {
+        return 0;
+    } else if x>=1 {
+        return 1;
+    }
+    la,_:=stdMath.Lgamma(a);
+    lb,_:=stdMath.Lgamma(b);
+    lab,_:=stdMath.Lgamma(a+b);
+    front:=stdMath.Exp(lab-la-lb+a*stdMath.Log(x)+b*stdMath.Log(1-x));
+    if x<(a+1)/(a+b+2) {
+        return front*betaContinuedFraction(x,a,b)/a;
+    }
+    return 1-front*betaContinuedFraction(1-x,b,a)/b;
+}
+
+//Lentz's method for the continued fraction of the incomplete beta function.
+func betaContinuedFraction(x float64, a float64, b float64) float64 {
+    const tiny=1e-300;
+    const eps=1e-15;
+    c:=1.0;
+    d:=1-(a+b)*x/(a+1);
+    if stdMath.Abs(d)<tiny {
+        d=tiny;
+    }
+    d=1/d;
+    rv:=d;
+    for m:=1.0; m<=300; m++ {
+        num:=m*(b-m)*x/((a+2*m-1)*(a+2*m));
+        d=1+num*d;
+        if stdMath.Abs(d)<tiny {
+            d=tiny;
+        }
+        c=1+num/c;
+        if stdMath.Abs(c)<tiny {
+            c=tiny;
+        }
+        d=1/d;
+        rv*=d*c;
+        num=-(a+m)*(a+b+m)*x/((a+2*m)*(a+2*m+1));
+        d=1+num*d;
+        if stdMath.Abs(d)<tiny {
+            d=tiny;
+        }
+        c=1+num/c;
+        if stdMath.Abs(c)<tiny {
+            c=tiny;
+        }
+        d=1/d;
+        delta:=d*c;
+        rv*=delta;
+        if stdMath.Abs(delta-1)<eps {
+            break;
+        }
+    }
+    return rv;
+}
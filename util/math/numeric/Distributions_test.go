@@ -0,0 +1,73 @@
+package numeric
+
+import (
+	stdMath "math"
+	"testing"
+
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func closeTo(exp float64, got float64, tol float64, msg string, t *testing.T) {
+    if stdMath.Abs(exp-got)>tol {
+        test.FormatError(exp,got,msg,t);
+    }
+}
+
+func TestNormalQuantile(t *testing.T){
+    v,err:=NormalQuantile(0.975);
+    test.BasicTest(nil,err,"NormalQuantile returned an error.",t);
+    closeTo(1.959964,v,1e-6,"NormalQuantile was not correct.",t);
+    v,_=NormalQuantile(0.5);
+    closeTo(0,v,1e-12,"NormalQuantile was not correct.",t);
+    for _,p:=range([]float64{0,1,-1}) {
+        if _,err=NormalQuantile(p); !customerr.IsValOutsideRange(err) {
+            test.FormatError(customerr.ValOutsideRange(""),err,
+                "An invalid probability did not return an error.",t,
+            );
+        }
+    }
+}
+
+func TestStudentTCdf(t *testing.T){
+    v,err:=StudentTCdf(0,5);
+    test.BasicTest(nil,err,"StudentTCdf returned an error.",t);
+    closeTo(0.5,v,1e-12,"StudentTCdf was not correct.",t);
+    //With 1 degree of freedom the t distribution is the cauchy distribution
+    v,_=StudentTCdf(1,1);
+    closeTo(0.75,v,1e-10,"StudentTCdf was not correct.",t);
+    v,_=StudentTCdf(-1,1);
+    closeTo(0.25,v,1e-10,"StudentTCdf was not correct.",t);
+    if _,err=StudentTCdf(1,0); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Invalid degrees of freedom did not return an error.",t,
+        );
+    }
+}
+
+func TestStudentTQuantile(t *testing.T){
+    for _,vals:=range([][3]float64{
+        {0.975,1,12.706205},
+        {0.975,5,2.570582},
+        {0.975,30,2.042272},
+        {0.95,10,1.812461},
+        {0.025,5,-2.570582},
+    }) {
+        v,err:=StudentTQuantile(vals[0],vals[1]);
+        test.BasicTest(nil,err,"StudentTQuantile returned an error.",t);
+        closeTo(vals[2],v,1e-5,"StudentTQuantile was not correct.",t);
+    }
+    //Large degrees of freedom approach the normal distribution
+    v,_:=StudentTQuantile(0.975,1e6);
+    closeTo(1.959964,v,1e-4,"StudentTQuantile did not approach normal.",t);
+    if _,err:=StudentTQuantile(1,5); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid probability did not return an error.",t,
+        );
+    }
+    if _,err:=StudentTQuantile(0.5,0); !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Invalid degrees of freedom did not return an error.",t,
+        );
+    }
+}
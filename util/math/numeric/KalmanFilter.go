@@ -0,0 +1,132 @@
+package numeric
+
+import (
+	"fmt"
+
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/math"
+)
+
+//A linear kalman filter that uses a random walk as its process model, meaning
+//the state is expected to stay the same between steps apart from some added
+//process noise. Measurements are scalar values that are a linear combination
+//of the state:
+//  z=h_1*x_1+h_2*x_2+...+h_n*x_n+v
+//Where v is the measurement noise. This lines up with the 'summation ops' used
+//by linear reg, h is the result of the iVarOps and z is the result of the dVarOp.
+type KalmanFilter[N math.Float] struct {
+    X Matrix[N];
+    P Matrix[N];
+};
+
+//Creates a kalman filter with the given initial state. The initial covariance
+//is a diagonal matrix with p0 along the diagonal, larger values mean there is
+//less confidence in the initial state.
+func NewKalmanFilter[N math.Float](x0 []N, p0 N) KalmanFilter[N] {
+    return KalmanFilter[N]{
+        X: NewMatrix(len(x0),1,func(r int, c int) N { return x0[r]; }),
+        P: NewMatrix(len(x0),len(x0),func(r int, c int) N {
+            if r==c {
+                return p0;
+            }
+            return N(0);
+        }),
+    };
+}
+
+//Creates a kalman filter from a previously saved state and covariance, allowing
+//updates to resume where they left off.
+func NewKalmanFilterFromState[N math.Float](
+        x []N,
+        p Matrix[N]) (KalmanFilter[N],error) {
+    if p.Rows()!=len(x) || p.Cols()!=len(x) {
+        return KalmanFilter[N]{},customerr.DimensionsDoNotAgree(fmt.Sprintf(
+            "Covariance must be square with the same dims as the state. | len(x)=%d [r=%d c=%d]",
+            len(x),p.Rows(),p.Cols(),
+        ));
+    }
+    return KalmanFilter[N]{
+        X: NewMatrix(len(x),1,func(r int, c int) N { return x[r]; }),
+        P: p.Copy(),
+    },nil;
+}
+
+func (k *KalmanFilter[N])Dims() int { return k.X.Rows(); }
+
+func (k *KalmanFilter[N])State() []N {
+    rv:=make([]N,k.X.Rows());
+    for i,_:=range(rv) {
+        rv[i]=k.X.V[i][0];
+    }
+    return rv;
+}
+
+//Returns the predicted measurement for the current state, h*x.
+func (k *KalmanFilter[N])Measurement(h []N) (N,error) {
+    if err:=customerr.ArrayDimsArgree(
+        h,k.X.V,"Measurement vector must match state dims.",
+    ); err!=nil {
+        return N(0),err;
+    }
+    var rv N=N(0);
+    for i,v:=range(h) {
+        rv+=v*k.X.V[i][0];
+    }
+    return rv,nil;
+}
+
+//The predict step. Given the random walk process model the state does not
+//change, only the covariance grows by the process noise q. Process noise is
+//added along the diagonal of the covariance matrix.
+func (k *KalmanFilter[N])Predict(q N){
+    for i:=0; i<k.P.Rows(); i++ {
+        k.P.V[i][i]+=q;
+    }
+}
+
+//The update step for a single scalar measurement z with measurement vector h
+//and measurement noise r. The Joseph form is used to update the covariance so
+//that it stays symmetric and positive definite. Returns the innovation, the
+//difference between the measurement and the prediction before the update.
+func (k *KalmanFilter[N])Update(h []N, z N, r N) (N,error) {
+    pred,err:=k.Measurement(h);
+    if err!=nil {
+        return N(0),err;
+    }
+    n:=k.Dims();
+    ph:=make([]N,n);
+    for i:=0; i<n; i++ {
+        for j:=0; j<n; j++ {
+            ph[i]+=k.P.V[i][j]*h[j];
+        }
+    }
+    s:=r;
+    for i:=0; i<n; i++ {
+        s+=h[i]*ph[i];
+    }
+    if s<=N(0) {
+        return N(0),math.DivByZero(fmt.Sprintf("Innovation covariance=%v",s));
+    }
+    innovation:=z-pred;
+    gain:=make([]N,n);
+    for i:=0; i<n; i++ {
+        gain[i]=ph[i]/s;
+        k.X.V[i][0]+=gain[i]*innovation;
+    }
+    //(I-Kh)
+    ikh:=NewMatrix(n,n,func(r int, c int) N {
+        return IdentityFill[N](r,c)-gain[r]*h[c];
+    });
+    ikhT:=ikh.Copy();
+    ikhT.Transpose();
+    //Errors are ignored because all matrices are guaranteed to be nxn
+    ikh.Mul(&k.P);
+    ikh.Mul(&ikhT);
+    for i:=0; i<n; i++ {
+        for j:=0; j<n; j++ {
+            ikh.V[i][j]+=gain[i]*r*gain[j];
+        }
+    }
+    k.P=ikh;
+    return innovation,nil;
+}
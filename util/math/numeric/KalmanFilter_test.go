@@ -0,0 +1,104 @@
+package numeric
+
+import (
+	"testing"
+
+	customerr "github.com/barbell-math/engine/util/err"
+	"github.com/barbell-math/engine/util/test"
+)
+
+func TestNewKalmanFilter(t *testing.T){
+    k:=NewKalmanFilter[float64]([]float64{1,2,3},10);
+    test.BasicTest(3,k.Dims(),"Kalman filter has wrong dims.",t);
+    test.SlicesMatch[float64]([]float64{1,2,3},k.State(),t);
+    k.P.Iter(func(r int, c int, v float64){
+        if r==c {
+            test.BasicTest(float64(10),v,"Initial covariance set incorrectly.",t);
+        } else {
+            test.BasicTest(float64(0),v,"Initial covariance set incorrectly.",t);
+        }
+    });
+}
+
+func TestNewKalmanFilterFromStateBadDims(t *testing.T){
+    _,err:=NewKalmanFilterFromState[float64](
+        []float64{1,2},NewMatrix(3,3,IdentityFill[float64]),
+    );
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Mismatched state and covariance dims were not caught.",t,
+        );
+    }
+}
+
+func TestNewKalmanFilterFromState(t *testing.T){
+    p:=NewMatrix(2,2,IdentityFill[float64]);
+    k,err:=NewKalmanFilterFromState[float64]([]float64{1,2},p);
+    test.BasicTest(nil,err,
+        "Creating a kalman filter from a state returned an error.",t,
+    );
+    p.V[0][0]=5;
+    test.BasicTest(float64(1),k.P.V[0][0],
+        "Covariance was not copied when creating a kalman filter.",t,
+    );
+}
+
+func TestKalmanFilterPredict(t *testing.T){
+    k:=NewKalmanFilter[float64]([]float64{1,2},1);
+    k.Predict(0.5);
+    test.SlicesMatch[float64]([]float64{1,2},k.State(),t);
+    k.P.Iter(func(r int, c int, v float64){
+        if r==c {
+            test.BasicTest(float64(1.5),v,"Process noise added incorrectly.",t);
+        } else {
+            test.BasicTest(float64(0),v,"Process noise added incorrectly.",t);
+        }
+    });
+}
+
+func TestKalmanFilterUpdateBadDims(t *testing.T){
+    k:=NewKalmanFilter[float64]([]float64{1,2},1);
+    _,err:=k.Update([]float64{1},1,1);
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Mismatched measurement dims were not caught.",t,
+        );
+    }
+}
+
+func TestKalmanFilterUpdateConverges(t *testing.T){
+    //y=2*x+3, the state should converge to [2,3]
+    k:=NewKalmanFilter[float64]([]float64{0,0},1e6);
+    for i:=0; i<100; i++ {
+        x:=float64(i%10);
+        _,err:=k.Update([]float64{x,1},2*x+3,1e-3);
+        test.BasicTest(nil,err,"Kalman update returned an error.",t);
+    }
+    s:=k.State();
+    if Abs(s[0]-2)>1e-3 || Abs(s[1]-3)>1e-3 {
+        test.FormatError([]float64{2,3},s,
+            "Kalman filter did not converge to the correct state.",t,
+        );
+    }
+    if Abs(k.P.V[0][1]-k.P.V[1][0])>WORKING_PRECISION {
+        test.FormatError(k.P.V[0][1],k.P.V[1][0],
+            "Covariance matrix is not symmetric.",t,
+        );
+    }
+}
+
+func TestKalmanFilterUpdateInnovation(t *testing.T){
+    k:=NewKalmanFilter[float64]([]float64{1,1},1);
+    innovation,err:=k.Update([]float64{1,1},5,1);
+    test.BasicTest(nil,err,"Kalman update returned an error.",t);
+    test.BasicTest(float64(3),innovation,
+        "Kalman update returned the wrong innovation.",t,
+    );
+    //K=[1/3,1/3], x=[1,1]+K*3=[2,2]
+    s:=k.State();
+    if Abs(s[0]-2)>1e-12 || Abs(s[1]-2)>1e-12 {
+        test.FormatError([]float64{2,2},s,
+            "Kalman update produced the wrong state.",t,
+        );
+    }
+}
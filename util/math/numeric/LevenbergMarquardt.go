@@ -0,0 +1,495 @@
+package numeric
+
+import (
+	"fmt"
+	stdMath "math"
+
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//A model whose constants do not need to enter linearly. Given the constants
+//and the variables of a sample the value of the model is returned.
+//Ex:
+//  y=b_1*exp(-x/b_2)
+type ParametricFunc[N math.Float] func(params []N, vals Vars[N]) (N,error);
+
+//Returns the partial derivatives of a parametric function with respect to each
+//of its constants, evaluated at the given constants and variables.
+type ParametricJacobian[N math.Float] func(params []N, vals Vars[N]) ([]N,error);
+
+//Returns a jacobian that is found with central differences. The step for each
+//constant is h scaled by the magnitude of the constant (with a minimum of h)
+//so constants of very different sizes are differentiated accurately.
+func NumericJacobian[N math.Float](f ParametricFunc[N], h N) ParametricJacobian[N] {
+    return func(params []N, vals Vars[N]) ([]N,error) {
+        rv:=make([]N,len(params));
+        p:=append([]N{},params...);
+        for i,v:=range(params) {
+            step:=h*Max(N(1),Abs(v));
+            p[i]=v+step;
+            hi,err:=f(p,vals);
+            if err!=nil {
+                return rv,err;
+            }
+            p[i]=v-step;
+            lo,err:=f(p,vals);
+            if err!=nil {
+                return rv,err;
+            }
+            p[i]=v;
+            rv[i]=(hi-lo)/(2*step);
+        }
+        return rv,nil;
+    }
+}
+
+//The reason an iterative solver stopped. The values are saved with the results
+//of a fit so they must not be re-ordered.
+type ConvergenceReason int;
+const (
+    NotConverged ConvergenceReason=iota
+    GradientTolReached
+    StepTolReached
+    CostTolReached
+    //The damping reached its maximum without finding a step that decreased
+    //the cost. None of the tolerances were reached so the solver did not
+    //converge.
+    DampingSaturated
+);
+
+func (c ConvergenceReason)String() string {
+    switch c {
+        case NotConverged: return "Not Converged";
+        case GradientTolReached: return "Gradient Tol Reached";
+        case StepTolReached: return "Step Tol Reached";
+        case CostTolReached: return "Cost Tol Reached";
+        case DampingSaturated: return "Damping Saturated";
+        default: return "unknown";
+    }
+}
+
+//The options that control the Levenberg-Marquardt solver. Zero values are
+//replaced with the defaults.
+//  - MaxIter: the maximum number of iterations
+//  - Damping: the initial damping, a larger value takes smaller gradient
+//    descent like steps, a value near zero takes Gauss-Newton steps
+//  - GradTol: stops once the largest (projected) gradient is less than this
+//  - StepTol: stops once no constant changes by more than this relative amount
+//  - CostTol: stops once the cost decreases by less than this relative amount
+//  - JacobianStep: the step used by the numeric jacobian
+type LevenbergMarquardtOpts[N math.Float] struct {
+    MaxIter int;
+    Damping N;
+    GradTol N;
+    StepTol N;
+    CostTol N;
+    JacobianStep N;
+};
+
+const (
+    DefaultLevenbergMarquardtMaxIter int=200
+    DefaultLevenbergMarquardtDamping float64=1e-3
+    DefaultLevenbergMarquardtTol float64=1e-10
+    DefaultJacobianStep float64=1e-6
+);
+
+//The damping is kept within these values. Once the damping reaches the maximum
+//the steps are too small to make progress and the solver stops without
+//converging.
+var levenbergMarquardtMinDamping float64=1e-12;
+var levenbergMarquardtMaxDamping float64=1e16;
+
+func (o *LevenbergMarquardtOpts[N])fillDefaults() error {
+    if o.MaxIter<0 || o.Damping<N(0) || o.GradTol<N(0) ||
+        o.StepTol<N(0) || o.CostTol<N(0) || o.JacobianStep<N(0) {
+        return customerr.ValOutsideRange("options must be >=0");
+    }
+    if o.MaxIter==0 {
+        o.MaxIter=DefaultLevenbergMarquardtMaxIter;
+    }
+    d,tol,step:=DefaultLevenbergMarquardtDamping,DefaultLevenbergMarquardtTol,
+        DefaultJacobianStep;
+    for _,v:=range([]dataStruct.Pair[*N,float64]{
+        {A: &o.Damping, B: d}, {A: &o.GradTol, B: tol}, {A: &o.StepTol, B: tol},
+        {A: &o.CostTol, B: tol}, {A: &o.JacobianStep, B: step},
+    }) {
+        if *v.A==N(0) {
+            *v.A=N(v.B);
+        }
+    }
+    return nil;
+}
+
+//The result of a non-linear least squares fit. The covariance and residual
+//variance have the same meaning as they do for linear reg, using the jacobian
+//at the solution in place of the design matrix.
+type NonlinearLeastSquaresResult[N math.Float] struct {
+    Params []N;
+    Covariance Matrix[N];
+    ResidualVariance N;
+    SampleCount int;
+    //The weighted sum of the squared residuals at the solution.
+    Sse N;
+    Iterations int;
+    Reason ConvergenceReason;
+    //The largest component of the projected gradient at the solution.
+    GradNorm N;
+    //The rcond of J^T*W*J at the solution.
+    Rcond float64;
+    f ParametricFunc[N];
+    jac ParametricJacobian[N];
+    normalInv Matrix[N];
+};
+
+func (r *NonlinearLeastSquaresResult[N])GetConstant(i int) N {
+    if i<len(r.Params) {
+        return r.Params[i];
+    }
+    return N(0);
+}
+func (r *NonlinearLeastSquaresResult[N])GetResidualVariance() N {
+    return r.ResidualVariance;
+}
+func (r *NonlinearLeastSquaresResult[N])GetSampleCount() int { return r.SampleCount; }
+func (r *NonlinearLeastSquaresResult[N])Dof() int { return r.SampleCount-len(r.Params); }
+func (r *NonlinearLeastSquaresResult[N])Converged() bool {
+    return r.Reason!=NotConverged && r.Reason!=DampingSaturated;
+}
+
+//Returns the value of the fitted function at the given variables.
+func (r *NonlinearLeastSquaresResult[N])Predict(vals Vars[N]) (N,error) {
+    if r.f==nil {
+        return N(0),nil;
+    }
+    return r.f(r.Params,vals);
+}
+
+//Returns the variance of a new observation at the given variables:
+//  var=s^2+g^T*Cov*g
+//Where g is the jacobian evaluated at the given variables.
+func (r *NonlinearLeastSquaresResult[N])PredictionVariance(vals Vars[N]) (N,error) {
+    rv:=r.ResidualVariance;
+    if r.jac==nil || r.Covariance.Rows()!=len(r.Params) {
+        return rv,nil;
+    }
+    g,err:=r.jac(r.Params,vals);
+    if err!=nil {
+        return rv,err;
+    }
+    return rv+quadForm(&r.Covariance,g,g),nil;
+}
+
+//Returns the (cross) leverage between two sets of variables using the
+//linearization of the function at the solution:
+//  h=g_a^T*(J^T*W*J)^-1*g_b
+func (r *NonlinearLeastSquaresResult[N])Leverage(a Vars[N], b Vars[N]) (N,error) {
+    if r.jac==nil || r.normalInv.Rows()!=len(r.Params) {
+        return N(0),nil;
+    }
+    ga,err:=r.jac(r.Params,a);
+    if err!=nil {
+        return N(0),err;
+    }
+    gb,err:=r.jac(r.Params,b);
+    if err!=nil {
+        return N(0),err;
+    }
+    return quadForm(&r.normalInv,ga,gb),nil;
+}
+
+func quadForm[N math.Number](m *Matrix[N], a []N, b []N) N {
+    var rv N=N(0);
+    for i,_:=range(a) {
+        for j,_:=range(b) {
+            rv+=a[i]*m.V[i][j]*b[j];
+        }
+    }
+    return rv;
+}
+
+//Fits the constants of a parametric function to a set of samples by
+//minimizing the weighted sum of the squared errors with the
+//Levenberg-Marquardt method. Unlike linear reg the samples are kept because the
+//jacobian changes with the constants.
+type NonlinearLeastSquares[N math.Float] struct {
+    f ParametricFunc[N];
+    jac ParametricJacobian[N];
+    numericJac bool;
+    dVarOp SummationOp[N];
+    numParams int;
+    samples []Vars[N];
+    weights []N;
+    bounds []dataStruct.Pair[N,N];
+    opts LevenbergMarquardtOpts[N];
+};
+
+//Creates a solver for the function with the given number of constants. If
+//the jacobian is nil a numeric jacobian is used.
+func NewNonlinearLeastSquares[N math.Float](
+        f ParametricFunc[N],
+        jac ParametricJacobian[N],
+        dVarOp SummationOp[N],
+        numParams int) (NonlinearLeastSquares[N],error) {
+    rv:=NonlinearLeastSquares[N]{
+        f: f, jac: jac, dVarOp: dVarOp, numParams: numParams,
+    };
+    if f==nil || dVarOp==nil {
+        return rv,customerr.InvalidValue("a function and dependent variable are needed");
+    } else if numParams<=0 {
+        return rv,customerr.ValOutsideRange("the number of constants must be >0");
+    }
+    rv.opts.fillDefaults();
+    if rv.jac==nil {
+        rv.jac,rv.numericJac=NumericJacobian(f,rv.opts.JacobianStep),true;
+    }
+    return rv,nil;
+}
+
+func (n *NonlinearLeastSquares[N])NumConstants() int { return n.numParams; }
+func (n *NonlinearLeastSquares[N])Samples() []Vars[N] { return n.samples; }
+
+//Sets the options used by the solver, see LevenbergMarquardtOpts.
+func (n *NonlinearLeastSquares[N])SetOptions(o LevenbergMarquardtOpts[N]) error {
+    if err:=o.fillDefaults(); err!=nil {
+        return err;
+    }
+    if n.numericJac {
+        n.jac=NumericJacobian(n.f,o.JacobianStep);
+    }
+    n.opts=o;
+    return nil;
+}
+
+func (n *NonlinearLeastSquares[N])GetOptions() LevenbergMarquardtOpts[N] { return n.opts; }
+
+//Sets the bounds (inclusive) that each constant must be within. A nil slice
+//removes the bounds.
+func (n *NonlinearLeastSquares[N])SetBounds(bounds []dataStruct.Pair[N,N]) error {
+    if bounds==nil {
+        n.bounds=nil;
+        return nil;
+    }
+    if err:=ValidBounds(n.numParams,bounds); err!=nil {
+        return err;
+    }
+    n.bounds=append([]dataStruct.Pair[N,N]{},bounds...);
+    return nil;
+}
+
+func (n *NonlinearLeastSquares[N])GetBounds() []dataStruct.Pair[N,N] { return n.bounds; }
+
+//Removes all of the samples, leaving the bounds and options unchanged.
+func (n *NonlinearLeastSquares[N])Reset(){
+    n.samples,n.weights=nil,nil;
+}
+
+//Adds a sample with a weight of one.
+func (n *NonlinearLeastSquares[N])AddSample(vals Vars[N]) error {
+    return n.AddSampleWeighted(vals,N(1));
+}
+
+//Adds a weighted sample. Samples with a weight of zero are ignored and
+//negative weights are not allowed.
+func (n *NonlinearLeastSquares[N])AddSampleWeighted(vals Vars[N], weight N) error {
+    if weight<N(0) {
+        return customerr.ValOutsideRange("weights must be >=0");
+    } else if weight==N(0) {
+        return nil;
+    }
+    if _,err:=n.dVarOp(vals); err!=nil {
+        return err;
+    }
+    n.samples=append(n.samples,vals.Copy());
+    n.weights=append(n.weights,weight);
+    return nil;
+}
+
+func (n *NonlinearLeastSquares[N])constrain(p []N){
+    for i,b:=range(n.bounds) {
+        p[i]=Constrain(p[i],b);
+    }
+}
+
+//Returns the weighted sum of the squared residuals.
+func (n *NonlinearLeastSquares[N])cost(p []N) (N,error) {
+    var rv N=N(0);
+    for i,s:=range(n.samples) {
+        y,_:=n.dVarOp(s);
+        v,err:=n.f(p,s);
+        if err!=nil {
+            return rv,err;
+        }
+        rv+=n.weights[i]*(y-v)*(y-v);
+    }
+    return rv,nil;
+}
+
+//Returns J^T*W*J and J^T*W*r, the normal equations of the linearized problem.
+func (n *NonlinearLeastSquares[N])normalEqs(p []N) (Matrix[N],Matrix[N],error) {
+    a:=NewMatrix(n.numParams,n.numParams,ZeroFill[N]);
+    b:=NewMatrix(n.numParams,1,ZeroFill[N]);
+    for i,s:=range(n.samples) {
+        y,_:=n.dVarOp(s);
+        v,err:=n.f(p,s);
+        if err!=nil {
+            return a,b,err;
+        }
+        g,err:=n.jac(p,s);
+        if err!=nil {
+            return a,b,err;
+        } else if err:=customerr.ArrayDimsArgree(
+            g,p,"The jacobian must have one value per constant.",
+        ); err!=nil {
+            return a,b,err;
+        }
+        w:=n.weights[i];
+        for j,_:=range(g) {
+            b.V[j][0]+=w*g[j]*(y-v);
+            for k,_:=range(g) {
+                a.V[j][k]+=w*g[j]*g[k];
+            }
+        }
+    }
+    return a,b,nil;
+}
+
+//Returns the largest component of the gradient of the cost that can be
+//followed without leaving the bounds. b is J^T*W*r, which points in the
+//direction the cost decreases.
+func (n *NonlinearLeastSquares[N])projectedGradNorm(p []N, b *Matrix[N]) N {
+    var rv N=N(0);
+    for i,_:=range(p) {
+        g:=b.V[i][0];
+        if i<len(n.bounds) && ((p[i]<=n.bounds[i].A && g<N(0)) ||
+            (p[i]>=n.bounds[i].B && g>N(0))) {
+            continue;
+        }
+        rv=Max(rv,Abs(g));
+    }
+    return rv;
+}
+
+//Solves for the constants starting from the initial guess, which is moved
+//within the bounds if it is not already. Each iteration solves the damped
+//normal equations:
+//  (J^T*W*J+lambda*diag(J^T*W*J))*delta=J^T*W*r
+//Steps that decrease the cost are accepted and the damping is decreased,
+//moving towards Gauss-Newton. Steps that do not are rejected and the damping is
+//increased, moving towards gradient descent. Steps that leave the bounds are
+//projected back onto them. The best constants that were found are always
+//returned, a DidNotConverge error is returned along with them if none of the
+//tolerances were reached.
+func (n *NonlinearLeastSquares[N])Run(initial []N) (NonlinearLeastSquaresResult[N],error) {
+    rv:=NonlinearLeastSquaresResult[N]{
+        Params: append([]N{},initial...),
+        SampleCount: len(n.samples), f: n.f, jac: n.jac,
+    };
+    if err:=customerr.ArrayDimsArgree(
+        initial,make([]N,n.numParams),"Need one initial value per constant.",
+    ); err!=nil {
+        return rv,err;
+    }
+    n.constrain(rv.Params);
+    cost,err:=n.cost(rv.Params);
+    if err!=nil {
+        return rv,err;
+    }
+    lambda:=n.opts.Damping;
+    var a,b Matrix[N];
+    for ; rv.Iterations<n.opts.MaxIter; rv.Iterations++ {
+        if a,b,err=n.normalEqs(rv.Params); err!=nil {
+            return rv,err;
+        }
+        if rv.GradNorm=n.projectedGradNorm(rv.Params,&b); rv.GradNorm<=n.opts.GradTol {
+            rv.Reason=GradientTolReached;
+            break;
+        }
+        next,nextCost,accepted:=n.step(rv.Params,cost,&a,&b,lambda);
+        if !accepted {
+            if lambda*=10; float64(lambda)>levenbergMarquardtMaxDamping {
+                rv.Reason=DampingSaturated;
+                break;
+            }
+            continue;
+        }
+        lambda=Max(lambda/10,N(levenbergMarquardtMinDamping));
+        change:=N(0);
+        for i,v:=range(next) {
+            change=Max(change,Abs(v-rv.Params[i])/(Abs(rv.Params[i])+n.opts.StepTol));
+        }
+        decrease:=cost-nextCost;
+        rv.Params,cost=next,nextCost;
+        if change<=n.opts.StepTol {
+            rv.Reason=StepTolReached;
+            break;
+        } else if decrease<=n.opts.CostTol*cost {
+            rv.Reason=CostTolReached;
+            break;
+        }
+    }
+    n.setResultStats(&rv,cost);
+    if rv.Reason==NotConverged {
+        return rv,math.DidNotConverge(fmt.Sprintf(
+            "Levenberg-Marquardt did not converge in %d iterations",n.opts.MaxIter,
+        ));
+    } else if rv.Reason==DampingSaturated {
+        return rv,math.DidNotConverge(fmt.Sprintf(
+            "Levenberg-Marquardt could not decrease the cost after %d iterations",
+            rv.Iterations,
+        ));
+    }
+    return rv,nil;
+}
+
+//Attempts a single damped step, returning the new constants, their cost, and
+//whether the step decreased the cost.
+func (n *NonlinearLeastSquares[N])step(
+        p []N,
+        cost N,
+        a *Matrix[N],
+        b *Matrix[N],
+        lambda N) ([]N,N,bool) {
+    damped:=a.Copy();
+    minDiag:=WORKING_PRECISION;
+    for i:=0; i<damped.Rows(); i++ {
+        damped.V[i][i]+=lambda*Max(a.V[i][i],N(minDiag));
+    }
+    if _,err:=damped.Inverse(); err!=nil && !math.IsMatrixSingularToWorkingPrecision(err) {
+        return p,N(0),false;
+    }
+    damped.Mul(b);
+    next:=make([]N,len(p));
+    for i,v:=range(p) {
+        next[i]=v+damped.V[i][0];
+    }
+    n.constrain(next);
+    nextCost,err:=n.cost(next);
+    if err!=nil || stdMath.IsNaN(float64(nextCost)) {
+        return p,N(0),false;
+    }
+    return next,nextCost,nextCost<cost;
+}
+
+//Sets the covariance and residual variance using the jacobian at the
+//solution. If the normal equations are singular the covariance is left empty.
+func (n *NonlinearLeastSquares[N])setResultStats(
+        r *NonlinearLeastSquaresResult[N],
+        cost N) {
+    r.Sse=cost;
+    if r.Dof()>0 {
+        r.ResidualVariance=cost/N(r.Dof());
+    }
+    a,b,err:=n.normalEqs(r.Params);
+    if err!=nil {
+        return;
+    }
+    r.GradNorm=n.projectedGradNorm(r.Params,&b);
+    if r.Rcond,err=a.Inverse(); err!=nil {
+        return;
+    }
+    r.normalInv=a.Copy();
+    a.MulScalar(r.ResidualVariance);
+    r.Covariance=a;
+}
@@ -0,0 +1,181 @@
+package numeric
+
+import (
+	stdMath "math"
+	"testing"
+
+	"github.com/barbell-math/engine/util/dataStruct"
+	"github.com/barbell-math/engine/util/test"
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//y=b_0*exp(-x/b_1)+b_2
+func expDecay(p []float64, vals Vars[float64]) (float64,error) {
+    x,err:=vals.Access("x");
+    return p[0]*stdMath.Exp(-x/p[1])+p[2],err;
+}
+
+func expDecayJacobian(p []float64, vals Vars[float64]) ([]float64,error) {
+    x,err:=vals.Access("x");
+    e:=stdMath.Exp(-x/p[1]);
+    return []float64{e,p[0]*e*x/(p[1]*p[1]),1},err;
+}
+
+func expDecaySolver(
+        jac ParametricJacobian[float64],
+        noise float64,
+        t *testing.T) NonlinearLeastSquares[float64] {
+    n,err:=NewNonlinearLeastSquares(
+        expDecay,jac,LinearSummationOp[float64]("y"),3,
+    );
+    test.BasicTest(nil,err,"Creating a solver returned an error.",t);
+    for i:=0; i<30; i++ {
+        x:=float64(i)/2;
+        y,_:=expDecay([]float64{5,3,1},map[string]float64{"x": x});
+        n.AddSample(map[string]float64{
+            "x": x, "y": y+noise*stdMath.Sin(float64(i*7)),
+        });
+    }
+    return n;
+}
+
+func TestNewNonlinearLeastSquaresInvalid(t *testing.T){
+    _,err:=NewNonlinearLeastSquares(nil,nil,LinearSummationOp[float64]("y"),1);
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A nil function did not return an error.",t,
+        );
+    }
+    _,err=NewNonlinearLeastSquares(expDecay,nil,LinearSummationOp[float64]("y"),0);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Zero constants did not return an error.",t,
+        );
+    }
+    n:=expDecaySolver(nil,0,t);
+    err=n.SetOptions(LevenbergMarquardtOpts[float64]{Damping: -1});
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Invalid options did not return an error.",t,
+        );
+    }
+    _,err=n.Run([]float64{1});
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "An invalid initial guess did not return an error.",t,
+        );
+    }
+    err=n.AddSampleWeighted(map[string]float64{"x": 1, "y": 1},-1);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A negative weight did not return an error.",t,
+        );
+    }
+}
+
+func TestNumericJacobian(t *testing.T){
+    p:=[]float64{5,3,1};
+    vals:=map[string]float64{"x": 2};
+    exp,_:=expDecayJacobian(p,vals);
+    res,err:=NumericJacobian[float64](expDecay,1e-6)(p,vals);
+    test.BasicTest(nil,err,"The numeric jacobian returned an error.",t);
+    for i,_:=range(exp) {
+        closeTo(exp[i],res[i],1e-8,"The numeric jacobian was not correct.",t);
+    }
+}
+
+func TestLevenbergMarquardtExactFit(t *testing.T){
+    for _,jac:=range([]ParametricJacobian[float64]{expDecayJacobian,nil}) {
+        n:=expDecaySolver(jac,0,t);
+        res,err:=n.Run([]float64{1,1,0});
+        test.BasicTest(nil,err,"Levenberg-Marquardt returned an error.",t);
+        test.BasicTest(true,res.Converged(),"The solver did not converge.",t);
+        for i,v:=range([]float64{5,3,1}) {
+            closeTo(v,res.GetConstant(i),1e-6,"The constants were not correct.",t);
+        }
+        test.BasicTest(true,res.Sse<1e-12,"The fit was not exact.",t);
+        test.BasicTest(30,res.GetSampleCount(),"The sample count was not correct.",t);
+    }
+}
+
+func TestLevenbergMarquardtStats(t *testing.T){
+    n:=expDecaySolver(expDecayJacobian,0.05,t);
+    res,err:=n.Run([]float64{1,1,0});
+    test.BasicTest(nil,err,"Levenberg-Marquardt returned an error.",t);
+    test.BasicTest(27,res.Dof(),"The degrees of freedom were not correct.",t);
+    closeTo(res.Sse/27,res.GetResidualVariance(),1e-12,
+        "The residual variance was not correct.",t,
+    );
+    //The gradient of the cost is zero at the solution
+    test.BasicTest(true,res.GradNorm<1e-6,"The solution was not a minimum.",t);
+    vals:=map[string]float64{"x": 4};
+    v,err:=res.PredictionVariance(vals);
+    test.BasicTest(nil,err,"Prediction variance returned an error.",t);
+    test.BasicTest(true,v>res.ResidualVariance,
+        "The prediction variance did not include the constant uncertainty.",t,
+    );
+    //The leverages of the samples sum to the number of constants
+    sum:=0.0;
+    for _,s:=range(n.Samples()) {
+        l,_:=res.Leverage(s,s);
+        sum+=l;
+    }
+    closeTo(3,sum,1e-6,"The leverages did not sum to the number of constants.",t);
+}
+
+func TestLevenbergMarquardtBounds(t *testing.T){
+    n:=expDecaySolver(expDecayJacobian,0,t);
+    err:=n.SetBounds([]dataStruct.Pair[float64,float64]{
+        {A: 0, B: 4}, {A: 0.1, B: 10}, NoOpConstraint[float64](),
+    });
+    test.BasicTest(nil,err,"Setting the bounds returned an error.",t);
+    res,err:=n.Run([]float64{10,1,0});
+    test.BasicTest(nil,err,"Levenberg-Marquardt returned an error.",t);
+    test.BasicTest(4.0,res.GetConstant(0),
+        "The constant was not held at its bound.",t,
+    );
+    test.BasicTest(true,res.GetConstant(1)>=0.1 && res.GetConstant(1)<=10,
+        "A constant was outside of its bounds.",t,
+    );
+    err=n.SetBounds([]dataStruct.Pair[float64,float64]{{A: 0, B: 1}});
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Invalid bounds did not return an error.",t,
+        );
+    }
+}
+
+func TestLevenbergMarquardtDidNotConverge(t *testing.T){
+    n:=expDecaySolver(expDecayJacobian,0.05,t);
+    n.SetOptions(LevenbergMarquardtOpts[float64]{MaxIter: 1});
+    res,err:=n.Run([]float64{1,1,0});
+    if !math.IsDidNotConverge(err) {
+        test.FormatError(math.DidNotConverge(""),err,
+            "Running out of iterations did not return an error.",t,
+        );
+    }
+    test.BasicTest(NotConverged,res.Reason,"The reason was not correct.",t);
+    test.BasicTest(1,res.Iterations,"The iterations were not correct.",t);
+}
+
+func TestLevenbergMarquardtDampingSaturated(t *testing.T){
+    //A jacobian with the wrong sign points every step uphill, so no step is
+    //ever accepted.
+    n:=expDecaySolver(func(p []float64, vals Vars[float64]) ([]float64,error) {
+        rv,err:=expDecayJacobian(p,vals);
+        for i,_:=range(rv) {
+            rv[i]=-rv[i];
+        }
+        return rv,err;
+    },0.05,t);
+    res,err:=n.Run([]float64{1,1,0});
+    if !math.IsDidNotConverge(err) {
+        test.FormatError(math.DidNotConverge(""),err,
+            "Saturating the damping did not return an error.",t,
+        );
+    }
+    test.BasicTest(DampingSaturated,res.Reason,"The reason was not correct.",t);
+    test.BasicTest(false,res.Converged(),"The solver claimed to converge.",t);
+    test.BasicTest(1.0,res.GetConstant(0),"The constants were changed.",t);
+}
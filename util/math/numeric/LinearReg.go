@@ -1,7 +1,11 @@
 package numeric
 
 import (
+	"fmt"
+
+	"github.com/barbell-math/engine/util/dataStruct"
 	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
 )
 
 //A summation op is the function that is associated with a variable
@@ -65,9 +69,22 @@ func LinearSumOpGenWithError[N math.Number](
     return rv,LinearSummationOp[N](dVar);
 }
 
+//The matrix holds the constants that were found. The covariance is the
+//covariance of the constants, which is the inverse of the normal equation
+//matrix scaled by the residual variance. The residual variance is the sum of
+//the squared residuals divided by the degrees of freedom (the number of
+//samples minus the number of constants). If there are not more samples than
+//constants the residual variance (and therefore the covariance) is zero. When
+//the samples are weighted the squared residuals are weighted, making the
+//residual variance the variance of a sample with a weight of one.
 type LinRegResult[N math.Number] struct {
     Matrix[N];
     Predict func(iVars Vars[N]) (N,error);
+    Covariance Matrix[N];
+    ResidualVariance N;
+    SampleCount int;
+    iVarOps []SummationOp[N];
+    normalInv Matrix[N];
 };
 func (l *LinRegResult[N])GetConstant(i int) N {
     if i<l.Matrix.Rows() {
@@ -75,6 +92,85 @@ func (l *LinRegResult[N])GetConstant(i int) N {
     }
     return N(0);
 }
+func (l *LinRegResult[N])GetResidualVariance() N { return l.ResidualVariance; }
+func (l *LinRegResult[N])GetSampleCount() int { return l.SampleCount; }
+
+//The degrees of freedom of the residual variance. A value <=0 means the
+//residual variance could not be estimated.
+func (l *LinRegResult[N])Dof() int { return l.SampleCount-l.Matrix.Rows(); }
+
+//Returns the variance of a new observation at the given variables, which is
+//made up of the residual variance and the uncertainty in the constants:
+//  var=s^2+x^T*Cov*x
+//Where x is the vector of iVarOps evaluated at the given variables.
+func (l *LinRegResult[N])PredictionVariance(iVars Vars[N]) (N,error) {
+    x,err:=l.evalIVarOps(iVars);
+    if err!=nil {
+        return N(0),err;
+    }
+    rv:=l.ResidualVariance;
+    if l.Covariance.Rows()!=len(x) || l.Covariance.Cols()!=len(x) {
+        return rv,nil;
+    }
+    for i,_:=range(x) {
+        for j,_:=range(x) {
+            rv+=x[i]*l.Covariance.V[i][j]*x[j];
+        }
+    }
+    return rv,nil;
+}
+
+//Returns the (cross) leverage between two sets of variables:
+//  h=a^T*(X^T*X)^-1*b
+//Where a and b are the vectors of iVarOps evaluated at the given variables.
+//When both sets of variables are the same this is the diagonal of the hat
+//matrix. Zero is returned if the regression has not been run.
+func (l *LinRegResult[N])Leverage(a Vars[N], b Vars[N]) (N,error) {
+    var rv N=N(0);
+    if l.normalInv.Rows()!=len(l.iVarOps) {
+        return rv,nil;
+    }
+    x,err:=l.evalIVarOps(a);
+    if err!=nil {
+        return rv,err;
+    }
+    y,err:=l.evalIVarOps(b);
+    if err!=nil {
+        return rv,err;
+    }
+    for i,_:=range(x) {
+        for j,_:=range(y) {
+            rv+=x[i]*l.normalInv.V[i][j]*y[j];
+        }
+    }
+    return rv,nil;
+}
+
+func (l *LinRegResult[N])evalIVarOps(iVars Vars[N]) ([]N,error) {
+    rv:=make([]N,len(l.iVarOps));
+    for i,op:=range(l.iVarOps) {
+        v,err:=op(iVars);
+        if err!=nil {
+            return rv,err;
+        }
+        rv[i]=v;
+    }
+    return rv,nil;
+}
+
+//The same as PredictionVariance except that the new observation has the given
+//weight, which scales the residual variance:
+//  var=s^2/w+x^T*Cov*x
+func (l *LinRegResult[N])WeightedPredictionVariance(
+        iVars Vars[N],
+        weight N) (N,error) {
+    if weight<=N(0) {
+        return N(0),customerr.ValOutsideRange("weight must be >0");
+    }
+    rv,err:=l.PredictionVariance(iVars);
+    return rv-l.ResidualVariance+l.ResidualVariance/weight,err;
+}
+
 func (l *LinearReg[N])genLinRegPredict(r *LinRegResult[N]){
     r.Predict=func(iVars Vars[N]) (N,error) {
         var err error;
@@ -87,12 +183,53 @@ func (l *LinearReg[N])genLinRegPredict(r *LinRegResult[N]){
     }
 }
 
+//The regularization that is applied when running linear reg. The constants
+//that are found minimize:
+//  1/2*SSE+L1*sum(p_i*|b_i|)+1/2*L2*sum(p_i*b_i^2)
+//Where p_i is the penalty of the i'th constant. If no penalties are given
+//every constant has a penalty of 1, a penalty of 0 leaves the constant
+//un-regularized. With only L2 (ridge) the regularized normal equations are
+//solved directly. With L1 (LASSO, or elastic-net when L2 is also set) the
+//constants are found with coordinate descent, which stops after MaxIter
+//iterations or once no constant changes by more than Tol.
+type Regularization[N math.Number] struct {
+    L1 N;
+    L2 N;
+    Penalties []N;
+    MaxIter int;
+    Tol N;
+};
+
+//The defaults used when a regularization does not specify them.
+const DefaultRegularizationMaxIter int=1000;
+const DefaultRegularizationTol float64=1e-12;
+
+//The number of rank one updates that are applied to a cached inverse before it
+//is re-calculated from the summations, used when SetInverseCache is given a
+//value of zero.
+const DefaultInverseRefresh int=100;
+
 type LinearReg[N math.Number] struct {
     a Matrix[N];
     b Matrix[N];
+    reg Regularization[N];
+    bounds []dataStruct.Pair[N,N];
     summationOps [][]SummationOp[N];
     iVarOps []SummationOp[N];
     dVarOp SummationOp[N];
+    //The sum of the squared dependent values and the number of samples, both
+    //are needed to calculate the residual variance.
+    yy N;
+    n int;
+    //The cached inverse of the regularized normal equation matrix. Once Run
+    //calculates the inverse it is kept up to date with rank one updates as
+    //samples are added and removed, and is re-calculated after invRefresh
+    //updates to limit the accumulation of round off error.
+    inv Matrix[N];
+    invValid bool;
+    invCached bool;
+    invRefresh int;
+    invUpdates int;
 };
 
 func NewLinearReg[N math.Number](
@@ -152,6 +289,160 @@ func (l *LinearReg[N])bSumOpGen(r int, c int) SummationOp[N] {
     }
 }
 
+func (l *LinearReg[N])NumConstants() int { return len(l.iVarOps); }
+
+//Removes all of the samples, leaving the regularization and bounds unchanged.
+func (l *LinearReg[N])Reset(){
+    l.a.Fill(ZeroFill[N]);
+    l.b.Fill(ZeroFill[N]);
+    l.yy,l.n,l.invValid=N(0),0,false;
+}
+
+//Sets the regularization that is used by Run. The zero value of a
+//regularization removes any regularization.
+func (l *LinearReg[N])SetRegularization(r Regularization[N]) error {
+    if r.L1<N(0) || r.L2<N(0) {
+        return customerr.ValOutsideRange("L1 and L2 must be >=0");
+    } else if len(r.Penalties)!=0 && len(r.Penalties)!=l.NumConstants() {
+        return customerr.ArrayDimsArgree(
+            l.iVarOps,r.Penalties,"Need one penalty per constant",
+        );
+    } else if r.MaxIter<0 || r.Tol<N(0) {
+        return customerr.ValOutsideRange("MaxIter and Tol must be >=0");
+    }
+    for _,p:=range(r.Penalties) {
+        if p<N(0) {
+            return customerr.ValOutsideRange("penalties must be >=0");
+        }
+    }
+    if r.MaxIter==0 {
+        r.MaxIter=DefaultRegularizationMaxIter;
+    }
+    if r.Tol==N(0) {
+        tol:=DefaultRegularizationTol;
+        r.Tol=N(tol);
+    }
+    l.reg=r;
+    l.invValid=false;
+    return nil;
+}
+
+func (l *LinearReg[N])GetRegularization() Regularization[N] { return l.reg; }
+
+//Sets whether the inverse of the normal equation matrix is cached between
+//calls to Run. Samples that are added or removed after the inverse is calculated are
+//applied to it with the Sherman-Morrison formula, so Run does not need to
+//re-invert the matrix. This makes sliding a fixed length window one sample at
+//a time O(n^2) per sample instead of O(n^3) per run. The inverse is
+//re-calculated after refreshEvery updates, a value of zero uses
+//DefaultInverseRefresh.
+func (l *LinearReg[N])SetInverseCache(enabled bool, refreshEvery int) error {
+    if refreshEvery<0 {
+        return customerr.ValOutsideRange("refreshEvery must be >=0");
+    }
+    if refreshEvery==0 {
+        refreshEvery=DefaultInverseRefresh;
+    }
+    l.invCached,l.invRefresh,l.invValid=enabled,refreshEvery,false;
+    return nil;
+}
+
+//Sets the bounds (inclusive) that each constant must be within. The constants
+//are found by solving the bound constrained problem rather than clamping the
+//unconstrained solution, so the constants that are not at a bound are still
+//optimal. A nil slice removes the bounds.
+func (l *LinearReg[N])SetBounds(bounds []dataStruct.Pair[N,N]) error {
+    if bounds==nil {
+        l.bounds=nil;
+        return nil;
+    }
+    if err:=ValidBounds(l.NumConstants(),bounds); err!=nil {
+        return err;
+    }
+    l.bounds=append([]dataStruct.Pair[N,N]{},bounds...);
+    return nil;
+}
+
+func (l *LinearReg[N])GetBounds() []dataStruct.Pair[N,N] { return l.bounds; }
+
+func (l *LinearReg[N])withinBounds(m *Matrix[N]) bool {
+    for i,b:=range(l.bounds) {
+        if m.V[i][0]<b.A || m.V[i][0]>b.B {
+            return false;
+        }
+    }
+    return true;
+}
+
+func (l *LinearReg[N])maxIter() int {
+    if l.reg.MaxIter>0 {
+        return l.reg.MaxIter;
+    }
+    return DefaultRegularizationMaxIter;
+}
+
+func (l *LinearReg[N])penalty(i int) N {
+    if len(l.reg.Penalties)==0 {
+        return N(1);
+    }
+    return l.reg.Penalties[i];
+}
+
+//Returns the normal equation matrix with the L2 penalties added to the
+//diagonal.
+func (l *LinearReg[N])regularizedLHS() Matrix[N] {
+    rv:=l.a.Copy();
+    if l.reg.L2>N(0) {
+        for i:=0; i<rv.Rows(); i++ {
+            rv.V[i][i]+=l.reg.L2*l.penalty(i);
+        }
+    }
+    return rv;
+}
+
+//Coordinate descent only needs the summations, each constant is updated in
+//turn by soft thresholding its partial residual:
+//  z_j=b_j-sum(A_jk*c_k) for k!=j
+//  c_j=sign(z_j)*max(|z_j|-L1*p_j,0)/(A_jj+L2*p_j)
+//If there are bounds each constant is then projected onto its bounds.
+func (l *LinearReg[N])coordinateDescent() Matrix[N] {
+    rv:=NewMatrix(l.a.Rows(),1,ZeroFill[N]);
+    for j,b:=range(l.bounds) {
+        rv.V[j][0]=Constrain(rv.V[j][0],b);
+    }
+    for iter:=0; iter<l.reg.MaxIter; iter++ {
+        var maxChange N=N(0);
+        for j:=0; j<l.a.Rows(); j++ {
+            z:=l.b.V[j][0];
+            for k:=0; k<l.a.Cols(); k++ {
+                if k!=j {
+                    z-=l.a.V[j][k]*rv.V[k][0];
+                }
+            }
+            var next N=N(0);
+            denom:=l.a.V[j][j]+l.reg.L2*l.penalty(j);
+            if thresh:=l.reg.L1*l.penalty(j); denom>N(0) && Abs(z)>thresh {
+                if z>N(0) {
+                    next=(z-thresh)/denom;
+                } else {
+                    next=(z+thresh)/denom;
+                }
+            }
+            if len(l.bounds)>0 {
+                next=Constrain(next,l.bounds[j]);
+            }
+            if c:=Abs(next-rv.V[j][0]); c>maxChange {
+                maxChange=c;
+            }
+            rv.V[j][0]=next;
+        }
+        if maxChange<=l.reg.Tol {
+            break;
+        }
+    }
+    return rv;
+}
+
 func (l *LinearReg[N])sumOpRows() int { return l.a.Rows(); }
 func (l *LinearReg[N])sumOpCols() int { return l.a.Cols()+l.b.Cols(); }
 
@@ -171,32 +462,188 @@ func (l *LinearReg[N])IterRHS(f func(r int, c int, v N)){
     l.b.Iter(f);
 }
 
+//Returns the values of the iVarOps and the dVarOp for the given variables. This
+//is the row of the design matrix (and the corresponding dependent value) that
+//UpdateSummations would use.
+func (l *LinearReg[N])EvalOps(vals Vars[N]) ([]N,N,error) {
+    rv:=make([]N,len(l.iVarOps));
+    for i,op:=range(l.iVarOps) {
+        v,err:=op(vals);
+        if err!=nil {
+            return rv,N(0),err;
+        }
+        rv[i]=v;
+    }
+    d,err:=l.dVarOp(vals);
+    return rv,d,err;
+}
+
 func (l *LinearReg[N])UpdateSummations(vals Vars[N]) error {
+    return l.UpdateSummationsWeighted(vals,N(1));
+}
+
+//Removes a sample that was previously added with UpdateSummations.
+func (l *LinearReg[N])RemoveSummations(vals Vars[N]) error {
+    return l.RemoveSummationsWeighted(vals,N(1));
+}
+
+//The same as UpdateSummations except that the sample is given a weight. The
+//constants that are found minimize the weighted sum of the squared errors:
+//  sum(w_i*(y_i-x_i^T*b)^2)
+//Samples with a weight of zero are ignored and negative weights are not
+//allowed.
+func (l *LinearReg[N])UpdateSummationsWeighted(vals Vars[N], weight N) error {
+    if weight<N(0) {
+        return customerr.ValOutsideRange("weights must be >=0");
+    } else if weight==N(0) {
+        return nil;
+    }
+    return l.changeSummations(vals,weight,false);
+}
+
+//Removes a sample that was previously added with UpdateSummationsWeighted. The
+//weight must be the same weight the sample was added with. Samples with a
+//weight of zero were never added so removing them does nothing.
+func (l *LinearReg[N])RemoveSummationsWeighted(vals Vars[N], weight N) error {
+    if weight<N(0) {
+        return customerr.ValOutsideRange("weights must be >=0");
+    } else if weight==N(0) {
+        return nil;
+    } else if l.n==0 {
+        return customerr.ValOutsideRange("there are no samples to remove");
+    }
+    return l.changeSummations(vals,weight,true);
+}
+
+func (l *LinearReg[N])changeSummations(
+        vals Vars[N],
+        weight N,
+        remove bool) error {
+    x,y,err:=l.EvalOps(vals);
+    if err!=nil {
+        return err;
+    }
     for i,r:=range(l.summationOps) {
         for j,s:=range(r) {
-            if v,err:=s(vals); err==nil {
-                if j<l.a.Cols() {
-                    l.a.V[i][j]+=v;
-                } else {
-                    l.b.V[i][j-l.a.Cols()]+=v;
-                }
-            } else {
+            v,err:=s(vals);
+            if err!=nil {
                 return err;
             }
+            if remove {
+                v=-v;
+            }
+            if j<l.a.Cols() {
+                l.a.V[i][j]+=weight*v;
+            } else {
+                l.b.V[i][j-l.a.Cols()]+=weight*v;
+            }
         }
     }
+    if remove {
+        l.yy-=weight*y*y;
+        l.n--;
+    } else {
+        l.yy+=weight*y*y;
+        l.n++;
+    }
+    l.updateInverse(x,weight,remove);
     return nil;
 }
 
+//Applies the rank one change to the cached inverse. If the update fails, or
+//enough updates have been applied, the inverse is re-calculated on the next
+//run.
+func (l *LinearReg[N])updateInverse(x []N, weight N, remove bool){
+    if !l.invValid {
+        return;
+    }
+    if remove {
+        weight=-weight;
+    }
+    l.invUpdates++;
+    if l.invUpdates>=l.invRefresh || ShermanMorrison(&l.inv,x,weight)!=nil {
+        l.invValid=false;
+    }
+}
+
+//Returns the inverse of the regularized normal equation matrix, using the
+//cached inverse when it is valid. The rcond is calculated the same way as
+//Matrix.Inverse.
+func (l *LinearReg[N])regularizedInverse() (Matrix[N],float64,error) {
+    lhs:=l.regularizedLHS();
+    if l.invCached && l.invValid {
+        rv:=l.inv.Copy();
+        rcond:=1.0/float64(Abs(lhs.getMaxColSum())*Abs(rv.getMaxColSum()));
+        if rcond<WORKING_PRECISION {
+            return rv,rcond,math.MatrixSingularToWorkingPrecision(
+                fmt.Sprintf("RCOND=%e",rcond),
+            );
+        }
+        return rv,rcond,nil;
+    }
+    rcond,err:=lhs.Inverse();
+    if l.invCached && err==nil {
+        l.inv,l.invValid,l.invUpdates=lhs.Copy(),true,0;
+    }
+    return lhs,rcond,err;
+}
+
+//The returned rcond is the rcond of the regularized normal equation matrix.
+//When L1 regularization is used the constants do not depend on the inverse so
+//a singular matrix does not return an error, but the covariance of the
+//constants will be empty. When there are bounds and the unconstrained solution
+//is not within them the bound constrained problem is solved, the covariance is
+//still the unconstrained covariance.
 func (l *LinearReg[N])Run() (LinRegResult[N],float64,error) {
     var rv LinRegResult[N];
-    rv.Matrix=l.a.Copy();
-    rcond,err:=rv.Matrix.Inverse();
-    if !math.IsInverseOfNonSquareMatrix(err) {
+    inv,rcond,err:=l.regularizedInverse();
+    rv.Matrix=inv;
+    if l.reg.L1>N(0) {
+        var inv Matrix[N];
+        if err==nil {
+            inv=rv.Matrix.Copy();
+        }
+        rv.Matrix=l.coordinateDescent();
+        l.setResidualStats(&rv,&inv);
+        err=nil;
+    } else if !math.IsInverseOfNonSquareMatrix(err) {
+        inv:=rv.Matrix.Copy();
         //err in RV can be ignored, matrices are guaranteed to have correct
         //dimensions because they are only managed by the linear reg struct
         rv.Matrix.Mul(&l.b);
+        if len(l.bounds)>0 && (err!=nil || !l.withinBounds(&rv.Matrix)) {
+            var bErr error;
+            lhs:=l.regularizedLHS();
+            rv.Matrix,bErr=BoundedLeastSquares(&lhs,&l.b,l.bounds,l.maxIter());
+            if err==nil {
+                err=bErr;
+            }
+        }
+        l.setResidualStats(&rv,&inv);
     }
     l.genLinRegPredict(&rv);
     return rv,rcond,err;
 }
+
+//The sum of squared residuals can be found from the summations without
+//revisiting the data:
+//  SSE=y^T*y-2*b^T*X^T*y+b^T*X^T*X*b
+//Floating point error can make the result slightly negative when the fit is
+//exact, in which case it is set to zero.
+func (l *LinearReg[N])setResidualStats(r *LinRegResult[N], inv *Matrix[N]){
+    r.SampleCount=l.n;
+    r.iVarOps=l.iVarOps;
+    sse:=l.yy;
+    for i:=0; i<l.a.Rows(); i++ {
+        sse-=2*r.Matrix.V[i][0]*l.b.V[i][0];
+        for j:=0; j<l.a.Cols(); j++ {
+            sse+=r.Matrix.V[i][0]*l.a.V[i][j]*r.Matrix.V[j][0];
+        }
+    }
+    if dof:=l.n-l.a.Rows(); dof>0 && sse>0 {
+        r.ResidualVariance=sse/N(dof);
+    }
+    r.normalInv=inv.Copy();
+    r.Covariance=inv.Copy();
+    r.Covariance.MulScalar(r.ResidualVariance);
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/barbell-math/engine/util/io/csv"
 	"github.com/barbell-math/engine/util/test"
 	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
 )
 
 func TestCreateLinReg(t *testing.T){
@@ -273,6 +274,23 @@ func TestNonStdLinearReg(t *testing.T){
 //    fmt.Println("rcond: ",rcond);
 //}
 
+func TestLinearRegEvalOps(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64](
+        []string{"x1","x2"},"y"),
+    );
+    iVals,dVal,err:=l.EvalOps(map[string]float64{"x1": 2, "x2": 3, "y": 4});
+    test.BasicTest(3,l.NumConstants(),"Wrong number of constants.",t);
+    test.BasicTest(nil,err,"Evaluating ops returned an error.",t);
+    test.SlicesMatch[float64]([]float64{2,3,1},iVals,t);
+    test.BasicTest(float64(4),dVal,"Dependent op evaluated incorrectly.",t);
+    _,_,err=l.EvalOps(map[string]float64{"x1": 2, "y": 4});
+    if !math.IsMissingVariable(err) {
+        test.FormatError(math.MissingVariable(""),err,
+            "Missing variable not caught.",t,
+        );
+    }
+}
+
 func benchmarkStdLinReg(n int, nPnts int, b *testing.B){
     iVars:=make([]string,n);
     for i:=0; i<n; i++ {
@@ -298,3 +316,322 @@ func BenchmarkStdLinReg10_100000(b *testing.B){ benchmarkStdLinReg(10,100000,b);
 func BenchmarkStdLinReg10_1000000(b *testing.B){ benchmarkStdLinReg(10,1000000,b); }
 func BenchmarkStdLinReg100_100(b *testing.B){ benchmarkStdLinReg(100,100,b); }
 func BenchmarkStdLinReg1000_1000(b *testing.B){ benchmarkStdLinReg(1000,1000,b); }
+
+
+func TestLinearRegResidualStats(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    //y=2x+1 with residuals of +-1, the residual variance is 4/(4-2)=2
+    for i,r:=range([]float64{1,-1,-1,1}) {
+        l.UpdateSummations(map[string]float64{
+            "x": float64(i), "y": 2*float64(i)+1+r,
+        });
+    }
+    res,_,err:=l.Run();
+    test.BasicTest(nil,err,"Running lin reg returned an error.",t);
+    test.BasicTest(4,res.GetSampleCount(),"The sample count was not correct.",t);
+    test.BasicTest(2,res.Dof(),"The degrees of freedom were not correct.",t);
+    closeTo(2,res.GetResidualVariance(),1e-9,
+        "The residual variance was not correct.",t,
+    );
+    //var(slope)=s^2/sum((x-mean(x))^2)=2/5
+    closeTo(0.4,res.Covariance.V[0][0],1e-9,
+        "The slope variance was not correct.",t,
+    );
+    v,err:=res.PredictionVariance(map[string]float64{"x": 1.5});
+    test.BasicTest(nil,err,"Prediction variance returned an error.",t);
+    //At the mean of x: var=s^2*(1+1/n)
+    closeTo(2.5,v,1e-9,"The prediction variance was not correct.",t);
+    v2,_:=res.PredictionVariance(map[string]float64{"x": 10});
+    test.BasicTest(true,v2>v,
+        "Prediction variance did not grow away from the data.",t,
+    );
+}
+
+func TestLinearRegExactFit(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for i:=0; i<5; i++ {
+        l.UpdateSummations(map[string]float64{"x": float64(i), "y": 3*float64(i)});
+    }
+    res,_,_:=l.Run();
+    closeTo(0,res.GetResidualVariance(),1e-9,
+        "An exact fit had a non-zero residual variance.",t,
+    );
+}
+
+func TestLinearRegLeverage(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for i,r:=range([]float64{1,-1,-1,1}) {
+        l.UpdateSummations(map[string]float64{
+            "x": float64(i), "y": 2*float64(i)+1+r,
+        });
+    }
+    res,_,_:=l.Run();
+    //The diagonal of the hat matrix sums to the number of constants
+    sum:=0.0;
+    for i:=0; i<4; i++ {
+        h,err:=res.Leverage(
+            map[string]float64{"x": float64(i)},
+            map[string]float64{"x": float64(i)},
+        );
+        test.BasicTest(nil,err,"Leverage returned an error.",t);
+        sum+=h;
+    }
+    closeTo(2,sum,1e-9,"The leverages did not sum to the number of constants.",t);
+    //h_ij=1/n+(x_i-mean)(x_j-mean)/sum((x-mean)^2)
+    h,_:=res.Leverage(map[string]float64{"x": 0},map[string]float64{"x": 3});
+    closeTo(0.25-2.25/5,h,1e-9,"The cross leverage was not correct.",t);
+    var empty LinRegResult[float64];
+    h,err:=empty.Leverage(map[string]float64{},map[string]float64{});
+    test.BasicTest(nil,err,"Leverage of an empty result returned an error.",t);
+    test.BasicTest(0.0,h,"Leverage of an empty result was not zero.",t);
+}
+
+func TestLinearRegSetRegularizationInvalid(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for _,r:=range([]Regularization[float64]{
+        Regularization[float64]{L1: -1},
+        Regularization[float64]{L2: -1},
+        Regularization[float64]{MaxIter: -1},
+        Regularization[float64]{Penalties: []float64{1,-1}},
+    }) {
+        if err:=l.SetRegularization(r); !customerr.IsValOutsideRange(err) {
+            test.FormatError(customerr.ValOutsideRange(""),err,
+                "An invalid regularization did not return an error.",t,
+            );
+        }
+    }
+    err:=l.SetRegularization(Regularization[float64]{Penalties: []float64{1}});
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Penalties of the wrong length did not return an error.",t,
+        );
+    }
+    test.BasicTest(nil,l.SetRegularization(Regularization[float64]{L2: 1}),
+        "A valid regularization returned an error.",t,
+    );
+    test.BasicTest(DefaultRegularizationMaxIter,l.GetRegularization().MaxIter,
+        "The default max iterations were not set.",t,
+    );
+}
+
+func TestLinearRegRidge(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGen[float64]([]string{"x"},"y"));
+    for i:=1; i<=4; i++ {
+        l.UpdateSummations(map[string]float64{"x": float64(i), "y": 2*float64(i)});
+    }
+    l.SetRegularization(Regularization[float64]{L2: 10});
+    res,rcond,err:=l.Run();
+    test.BasicTest(nil,err,"Running ridge regression returned an error.",t);
+    //b=sum(xy)/(sum(x^2)+L2)=60/40
+    closeTo(1.5,res.GetConstant(0),1e-9,"The ridge constant was not correct.",t);
+    test.BasicTest(1.0,rcond,"The rcond of a 1x1 matrix was not 1.",t);
+}
+
+func TestLinearRegRidgeSingular(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGen[float64]([]string{"x","z"},"y"));
+    for i:=1; i<=4; i++ {
+        l.UpdateSummations(map[string]float64{
+            "x": float64(i), "z": float64(i), "y": 2*float64(i),
+        });
+    }
+    _,_,err:=l.Run();
+    test.BasicTest(true,err!=nil,"A singular system did not return an error.",t);
+    l.SetRegularization(Regularization[float64]{L2: 1e-3});
+    res,rcond,err:=l.Run();
+    test.BasicTest(nil,err,"Ridge did not fix the singular system.",t);
+    test.BasicTest(true,rcond>0,"The regularized rcond was not positive.",t);
+    closeTo(res.GetConstant(0),res.GetConstant(1),1e-9,
+        "Collinear constants were not split evenly.",t,
+    );
+    closeTo(2,res.GetConstant(0)+res.GetConstant(1),1e-3,
+        "The collinear constants did not sum to the slope.",t,
+    );
+}
+
+func TestLinearRegLasso(t *testing.T){
+    //Orthogonal design, the LASSO solution is the soft thresholded least
+    //squares solution: b=sign(z)*max(|z|-L1,0)/sum(x^2)
+    l:=NewLinearReg[float64](LinearSumOpGen[float64]([]string{"x","z"},"y"));
+    for _,v:=range([][3]float64{{1,0,3},{-1,0,-3},{0,1,0.5},{0,-1,-0.5}}) {
+        l.UpdateSummations(map[string]float64{"x": v[0], "z": v[1], "y": v[2]});
+    }
+    l.SetRegularization(Regularization[float64]{L1: 2});
+    res,_,err:=l.Run();
+    test.BasicTest(nil,err,"Running LASSO returned an error.",t);
+    closeTo(2,res.GetConstant(0),1e-9,"The LASSO constant was not correct.",t);
+    closeTo(0,res.GetConstant(1),1e-12,"A small constant was not zeroed.",t);
+    //A penalty of zero leaves the constant un-regularized
+    l.SetRegularization(Regularization[float64]{
+        L1: 2, Penalties: []float64{0,1},
+    });
+    res,_,_=l.Run();
+    closeTo(3,res.GetConstant(0),1e-9,
+        "An un-penalized constant was regularized.",t,
+    );
+    //Elastic net: b=(|z|-L1)/(sum(x^2)+L2)
+    l.SetRegularization(Regularization[float64]{L1: 2, L2: 2});
+    res,_,_=l.Run();
+    closeTo(1,res.GetConstant(0),1e-9,"The elastic net constant was not correct.",t);
+}
+
+func TestLinearRegLassoSingular(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGen[float64]([]string{"x","z"},"y"));
+    for i:=1; i<=4; i++ {
+        l.UpdateSummations(map[string]float64{
+            "x": float64(i), "z": float64(i), "y": 2*float64(i),
+        });
+    }
+    l.SetRegularization(Regularization[float64]{L1: 0.1});
+    res,_,err:=l.Run();
+    test.BasicTest(nil,err,"LASSO returned an error for a singular system.",t);
+    test.BasicTest(0,res.Covariance.Rows(),
+        "The covariance was set for a singular system.",t,
+    );
+    p,_:=res.Predict(map[string]float64{"x": 2, "z": 2});
+    closeTo(4,p,0.1,"The LASSO prediction was not close to the data.",t);
+}
+
+func TestLinearRegWeighted(t *testing.T){
+    //Weighting a sample by 2 is the same as adding it twice
+    weighted:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    repeated:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for i,r:=range([]float64{1,-1,-1,1,0.5}) {
+        v:=map[string]float64{"x": float64(i), "y": 2*float64(i)+1+r};
+        w:=float64(1+i%2);
+        test.BasicTest(nil,weighted.UpdateSummationsWeighted(v,w),
+            "Updating with a weight returned an error.",t,
+        );
+        for j:=0; j<int(w); j++ {
+            repeated.UpdateSummations(v);
+        }
+    }
+    wRes,_,err:=weighted.Run();
+    test.BasicTest(nil,err,"Running weighted lin reg returned an error.",t);
+    rRes,_,_:=repeated.Run();
+    closeTo(rRes.GetConstant(0),wRes.GetConstant(0),1e-9,
+        "The weighted slope did not match repeating the samples.",t,
+    );
+    closeTo(rRes.GetConstant(1),wRes.GetConstant(1),1e-9,
+        "The weighted intercept did not match repeating the samples.",t,
+    );
+    test.BasicTest(5,wRes.GetSampleCount(),
+        "Weighted samples were not counted once.",t,
+    );
+    err=weighted.UpdateSummationsWeighted(map[string]float64{"x": 1, "y": 1},-1);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A negative weight did not return an error.",t,
+        );
+    }
+    test.BasicTest(nil,
+        weighted.UpdateSummationsWeighted(map[string]float64{"x": 100, "y": -100},0),
+        "A zero weight returned an error.",t,
+    );
+    zRes,_,_:=weighted.Run();
+    test.BasicTest(wRes.GetConstant(0),zRes.GetConstant(0),
+        "A sample with a weight of zero changed the fit.",t,
+    );
+}
+
+func TestWeightedPredictionVariance(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for i,r:=range([]float64{1,-1,-1,1}) {
+        l.UpdateSummations(map[string]float64{
+            "x": float64(i), "y": 2*float64(i)+1+r,
+        });
+    }
+    res,_,_:=l.Run();
+    v,err:=res.WeightedPredictionVariance(map[string]float64{"x": 1.5},2);
+    test.BasicTest(nil,err,"Weighted prediction variance returned an error.",t);
+    //s^2/w+s^2/n=2/2+2/4
+    closeTo(1.5,v,1e-9,"The weighted prediction variance was not correct.",t);
+    _,err=res.WeightedPredictionVariance(map[string]float64{"x": 1.5},0);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "A zero weight did not return an error.",t,
+        );
+    }
+}
+
+func linearRegRollingVals(i int) map[string]float64 {
+    x:=float64(i%7)+0.1*float64(i);
+    return map[string]float64{
+        "x": x, "y": 3*x-2+float64((i*5)%3)-1,
+    };
+}
+
+func TestLinearRegRemoveSummations(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    exp:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    err:=l.RemoveSummations(linearRegRollingVals(0));
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Removing from an empty regression did not return an error.",t,
+        );
+    }
+    for i:=0; i<10; i++ {
+        l.UpdateSummations(linearRegRollingVals(i));
+        if i>=3 {
+            exp.UpdateSummations(linearRegRollingVals(i));
+        }
+    }
+    for i:=0; i<3; i++ {
+        test.BasicTest(nil,l.RemoveSummations(linearRegRollingVals(i)),
+            "Removing a sample returned an error.",t,
+        );
+    }
+    res,_,err:=l.Run();
+    test.BasicTest(nil,err,"Running lin reg returned an error.",t);
+    expRes,_,_:=exp.Run();
+    test.BasicTest(7,res.GetSampleCount(),"Removed samples were counted.",t);
+    for i:=0; i<2; i++ {
+        closeTo(expRes.GetConstant(i),res.GetConstant(i),1e-9,
+            "Removing samples did not match never adding them.",t,
+        );
+    }
+    closeTo(expRes.GetResidualVariance(),res.GetResidualVariance(),1e-9,
+        "Removing samples did not update the residual variance.",t,
+    );
+}
+
+func TestLinearRegInverseCache(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    err:=l.SetInverseCache(true,-1);
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "An invalid refresh did not return an error.",t,
+        );
+    }
+    test.BasicTest(nil,l.SetInverseCache(true,7),
+        "Enabling the inverse cache returned an error.",t,
+    );
+    const win int=10;
+    for i:=0; i<win; i++ {
+        l.UpdateSummations(linearRegRollingVals(i));
+    }
+    //Slide a fixed length window one sample at a time
+    for i:=win; i<40; i++ {
+        res,rcond,err:=l.Run();
+        test.BasicTest(nil,err,"Running a cached lin reg returned an error.",t);
+        exp:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+        for j:=i-win; j<i; j++ {
+            exp.UpdateSummations(linearRegRollingVals(j));
+        }
+        expRes,expRcond,_:=exp.Run();
+        for j:=0; j<2; j++ {
+            closeTo(expRes.GetConstant(j),res.GetConstant(j),1e-9,
+                "The rolling window did not match re-fitting the window.",t,
+            );
+        }
+        closeTo(expRcond,rcond,1e-9,"The cached rcond was not correct.",t);
+        eq,_:=res.Covariance.Equals(&expRes.Covariance,1e-9);
+        test.BasicTest(true,eq,"The cached covariance was not correct.",t);
+        l.RemoveSummations(linearRegRollingVals(i-win));
+        l.UpdateSummations(linearRegRollingVals(i));
+        if i==win {
+            test.BasicTest(true,l.invValid,
+                "The cached inverse was not updated in place.",t,
+            );
+        }
+    }
+}
@@ -0,0 +1,51 @@
+package numeric
+
+import (
+	"fmt"
+
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//Updates the inverse of a symmetric matrix A in place so that it becomes the
+//inverse of A+w*u*u^T using the Sherman-Morrison formula:
+//  (A+w*u*u^T)^-1=A^-1-w*(A^-1*u)*(A^-1*u)^T/(1+w*u^T*A^-1*u)
+//A negative weight removes (downdates) the vector. The update takes O(n^2)
+//operations rather than the O(n^3) operations needed to re-invert the matrix.
+//If the updated matrix would be singular the inverse is left unchanged and an
+//error is returned.
+func ShermanMorrison[N math.Number](inv *Matrix[N], u []N, weight N) error {
+    if err:=rankOneErrorCheck(inv,u); err!=nil {
+        return err;
+    }
+    invU:=make([]N,len(u));
+    var denom N=N(1);
+    for i,_:=range(u) {
+        for j,_:=range(u) {
+            invU[i]+=inv.V[i][j]*u[j];
+        }
+        denom+=weight*u[i]*invU[i];
+    }
+    if float64(Abs(denom))<WORKING_PRECISION {
+        return math.SingularMatrix(fmt.Sprintf(
+            "Sherman-Morrison denominator=%e",float64(denom),
+        ));
+    }
+    for i,_:=range(u) {
+        for j,_:=range(u) {
+            inv.V[i][j]-=weight*invU[i]*invU[j]/denom;
+        }
+    }
+    return nil;
+}
+
+func rankOneErrorCheck[N math.Number](m *Matrix[N], u []N) error {
+    if err:=squareMatrixErrorCheck(m); err!=nil {
+        return err;
+    } else if len(u)!=m.Rows() {
+        return customerr.DimensionsDoNotAgree(fmt.Sprintf(
+            "Matrix: %dx%d Vector: %d",m.Rows(),m.Cols(),len(u),
+        ));
+    }
+    return nil;
+}
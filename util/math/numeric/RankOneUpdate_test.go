@@ -0,0 +1,67 @@
+package numeric
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/util/test"
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func rankOneTestMatrix() Matrix[float64] {
+    return NewMatrix(3,3,ArrayFill([][]float64{
+        {4, 1, 2},
+        {1, 5, 1},
+        {2, 1, 6},
+    }));
+}
+
+func outerAdd(m *Matrix[float64], u []float64, w float64){
+    for i,_:=range(u) {
+        for j,_:=range(u) {
+            m.V[i][j]+=w*u[i]*u[j];
+        }
+    }
+}
+
+func TestShermanMorrison(t *testing.T){
+    u:=[]float64{1,-2,0.5};
+    for _,w:=range([]float64{1,2.5,-0.5}) {
+        inv:=rankOneTestMatrix();
+        inv.Inverse();
+        err:=ShermanMorrison(&inv,u,w);
+        test.BasicTest(nil,err,"Sherman-Morrison returned an error.",t);
+        exp:=rankOneTestMatrix();
+        outerAdd(&exp,u,w);
+        exp.Inverse();
+        eq,_:=inv.Equals(&exp,1e-12);
+        test.BasicTest(true,eq,
+            "Sherman-Morrison did not match re-inverting the matrix.",t,
+        );
+    }
+}
+
+func TestShermanMorrisonErrors(t *testing.T){
+    inv:=NewMatrix(2,2,IdentityFill[float64]);
+    err:=ShermanMorrison(&inv,[]float64{1},1);
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Mismatched dimensions did not return an error.",t,
+        );
+    }
+    nonSquare:=NewMatrix(2,3,ZeroFill[float64]);
+    err=ShermanMorrison(&nonSquare,[]float64{1,1},1);
+    if !math.IsInverseOfNonSquareMatrix(err) {
+        test.FormatError(math.InverseOfNonSquareMatrix(""),err,
+            "A non-square matrix did not return an error.",t,
+        );
+    }
+    //Removing the only contribution to a direction makes the matrix singular
+    err=ShermanMorrison(&inv,[]float64{1,0},-1);
+    if !math.IsSingularMatrix(err) {
+        test.FormatError(math.SingularMatrix(""),err,
+            "A singular update did not return an error.",t,
+        );
+    }
+    test.BasicTest(1.0,inv.V[0][0],"A failed update modified the inverse.",t);
+}
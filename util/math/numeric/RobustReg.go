@@ -0,0 +1,200 @@
+package numeric
+
+import (
+	"fmt"
+
+	"github.com/barbell-math/engine/util/math"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+//Returns the weight of a sample given its scaled residual, u=r/s. Robust
+//weight functions give samples with large residuals less weight so that a
+//single bad sample can not dominate a fit.
+type RobustWeightFunc[N math.Float] func(u N) N;
+
+//The tuning constants that give 95% efficiency when the errors are normal.
+const (
+    DefaultHuberK float64=1.345
+    DefaultTukeyC float64=4.685
+);
+
+//The MAD of normally distributed values is this fraction of their standard
+//deviation.
+const madToStdDev float64=0.6745;
+
+//Returns Huber weights:
+//  w=1 if |u|<=k, k/|u| otherwise
+func HuberWeights[N math.Float](k N) RobustWeightFunc[N] {
+    return func(u N) N {
+        if a:=Abs(u); a>k {
+            return k/a;
+        }
+        return N(1);
+    }
+}
+
+//Returns Tukey bisquare weights, which completely ignore samples with scaled
+//residuals larger than c:
+//  w=(1-(u/c)^2)^2 if |u|<c, 0 otherwise
+func TukeyBisquareWeights[N math.Float](c N) RobustWeightFunc[N] {
+    return func(u N) N {
+        if Abs(u)>=c {
+            return N(0);
+        }
+        tmp:=1-(u/c)*(u/c);
+        return tmp*tmp;
+    }
+}
+
+//Returns a robust estimate of the standard deviation of the residuals, the
+//median absolute residual divided by 0.6745. Residuals with a weight of zero
+//are not included.
+func MadScale[N math.Float](residuals []N, weights []N) N {
+    abs:=make([]N,0,len(residuals));
+    for i,r:=range(residuals) {
+        if i>=len(weights) || weights[i]>N(0) {
+            abs=append(abs,Abs(r));
+        }
+    }
+    tmp:=madToStdDev;
+    return Median(abs...)/N(tmp);
+}
+
+//The options that control iteratively reweighted least squares. Zero values
+//for MaxIter and Tol are replaced with the defaults.
+//  - Weight: the robust weight function
+//  - MaxIter: the maximum number of times the weights are updated
+//  - Tol: stops once no weight changes by more than this
+type RobustOpts[N math.Float] struct {
+    Weight RobustWeightFunc[N];
+    MaxIter int;
+    Tol N;
+};
+
+const (
+    DefaultRobustMaxIter int=50
+    DefaultRobustTol float64=1e-6
+);
+
+func (o *RobustOpts[N])fillDefaults() error {
+    if o.Weight==nil {
+        return customerr.InvalidValue("a robust weight function is needed");
+    } else if o.MaxIter<0 || o.Tol<N(0) {
+        return customerr.ValOutsideRange("MaxIter and Tol must be >=0");
+    }
+    if o.MaxIter==0 {
+        o.MaxIter=DefaultRobustMaxIter;
+    }
+    if o.Tol==N(0) {
+        tol:=DefaultRobustTol;
+        o.Tol=N(tol);
+    }
+    return nil;
+}
+
+//The results of iteratively reweighted least squares.
+//  - Weights: the final weight of each sample, including its base weight
+//  - Scale: the robust scale of the residuals used for the final weights
+//  - Iterations: the number of times the weights were updated
+type IRLSResult[N math.Float] struct {
+    Weights []N;
+    Scale N;
+    Iterations int;
+    Converged bool;
+};
+
+//Runs iteratively reweighted least squares. The fit function is given the
+//weight of each sample and must return the residual of each sample. Each
+//iteration the residuals are scaled by MadScale and the weights are set to
+//the base weights multiplied by the robust weights. The last call to fit is
+//always made with the returned weights. If the residuals have a scale of zero
+//the fit is exact and the weights are not changed.
+func IRLS[N math.Float](
+        baseWeights []N,
+        fit func(weights []N) ([]N,error),
+        o RobustOpts[N]) (IRLSResult[N],error) {
+    rv:=IRLSResult[N]{Weights: append([]N{},baseWeights...)};
+    if err:=o.fillDefaults(); err!=nil {
+        return rv,err;
+    }
+    residuals,err:=fit(rv.Weights);
+    for ; err==nil && rv.Iterations<o.MaxIter; rv.Iterations++ {
+        if err=customerr.ArrayDimsArgree(
+            residuals,baseWeights,"Need one residual per sample.",
+        ); err!=nil {
+            return rv,err;
+        }
+        if rv.Scale=MadScale(residuals,baseWeights); rv.Scale==N(0) {
+            rv.Converged=true;
+            break;
+        }
+        change:=N(0);
+        for i,r:=range(residuals) {
+            w:=baseWeights[i]*o.Weight(r/rv.Scale);
+            change=Max(change,Abs(w-rv.Weights[i]));
+            rv.Weights[i]=w;
+        }
+        residuals,err=fit(rv.Weights);
+        if change<=o.Tol {
+            rv.Converged=true;
+            rv.Iterations++;
+            break;
+        }
+    }
+    if err==nil && !rv.Converged {
+        err=math.DidNotConverge(fmt.Sprintf(
+            "IRLS did not converge in %d iterations",o.MaxIter,
+        ));
+    }
+    return rv,err;
+}
+
+//Fits the samples to the linear reg with iteratively reweighted least squares,
+//replacing any samples that were previously added to it. Each sample may be given a base weight
+//with the baseWeights argument, nil gives every sample a weight of one. The
+//returned result and rcond are from the final weighted fit.
+func RobustLinearReg[N math.Float](
+        l *LinearReg[N],
+        samples []Vars[N],
+        baseWeights []N,
+        o RobustOpts[N]) (LinRegResult[N],IRLSResult[N],float64,error) {
+    var res LinRegResult[N];
+    var rcond float64;
+    if baseWeights==nil {
+        baseWeights=make([]N,len(samples));
+        for i,_:=range(baseWeights) {
+            baseWeights[i]=N(1);
+        }
+    } else if err:=customerr.ArrayDimsArgree(
+        samples,baseWeights,"Need one weight per sample.",
+    ); err!=nil {
+        return res,IRLSResult[N]{},rcond,err;
+    }
+    irls,err:=IRLS(baseWeights,func(weights []N) ([]N,error) {
+        var err error;
+        l.Reset();
+        for i,s:=range(samples) {
+            if err=l.UpdateSummationsWeighted(s,weights[i]); err!=nil {
+                return nil,err;
+            }
+        }
+        res,rcond,err=l.Run();
+        if err!=nil {
+            return nil,err;
+        }
+        residuals:=make([]N,len(samples));
+        for i,s:=range(samples) {
+            y,err:=l.dVarOp(s);
+            if err!=nil {
+                return nil,err;
+            }
+            p,err:=res.Predict(s);
+            if err!=nil {
+                return nil,err;
+            }
+            residuals[i]=y-p;
+        }
+        return residuals,nil;
+    },o);
+    return res,irls,rcond,err;
+}
@@ -0,0 +1,105 @@
+package numeric
+
+import (
+	"testing"
+
+	"github.com/barbell-math/engine/util/test"
+	customerr "github.com/barbell-math/engine/util/err"
+)
+
+func TestRobustWeights(t *testing.T){
+    h:=HuberWeights(2.0);
+    test.BasicTest(1.0,h(-1.5),"A small residual was down weighted.",t);
+    test.BasicTest(0.5,h(-4),"The huber weight was not correct.",t);
+    b:=TukeyBisquareWeights(2.0);
+    test.BasicTest(1.0,b(0),"A zero residual was down weighted.",t);
+    test.BasicTest(0.5625,b(1),"The bisquare weight was not correct.",t);
+    test.BasicTest(0.0,b(-2),"A large residual was not ignored.",t);
+}
+
+func TestMadScale(t *testing.T){
+    closeTo(2/0.6745,MadScale([]float64{1,-2,3,100},[]float64{1,1,1,0}),1e-12,
+        "The MAD scale was not correct.",t,
+    );
+}
+
+func TestIRLSInvalidOpts(t *testing.T){
+    fit:=func(w []float64) ([]float64,error) { return w,nil; };
+    _,err:=IRLS([]float64{1},fit,RobustOpts[float64]{});
+    if !customerr.IsInvalidValue(err) {
+        test.FormatError(customerr.InvalidValue(""),err,
+            "A missing weight function did not return an error.",t,
+        );
+    }
+    _,err=IRLS([]float64{1},fit,RobustOpts[float64]{
+        Weight: HuberWeights(DefaultHuberK), MaxIter: -1,
+    });
+    if !customerr.IsValOutsideRange(err) {
+        test.FormatError(customerr.ValOutsideRange(""),err,
+            "Invalid options did not return an error.",t,
+        );
+    }
+}
+
+func robustTestSamples() []Vars[float64] {
+    rv:=[]Vars[float64]{};
+    for i:=0; i<20; i++ {
+        x:=float64(i);
+        y:=2*x+1+0.1*float64((i*7)%5-2);
+        if i==15 {
+            y+=40;
+        }
+        rv=append(rv,map[string]float64{"x": x, "y": y});
+    }
+    return rv;
+}
+
+func TestRobustLinearReg(t *testing.T){
+    samples:=robustTestSamples();
+    ols:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    for _,s:=range(samples) {
+        ols.UpdateSummations(s);
+    }
+    olsRes,_,_:=ols.Run();
+    for _,w:=range([]RobustWeightFunc[float64]{
+        HuberWeights(DefaultHuberK),TukeyBisquareWeights(DefaultTukeyC),
+    }) {
+        l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+        res,irls,_,err:=RobustLinearReg(&l,samples,nil,RobustOpts[float64]{Weight: w});
+        test.BasicTest(nil,err,"Robust lin reg returned an error.",t);
+        test.BasicTest(true,irls.Converged,"Robust lin reg did not converge.",t);
+        test.BasicTest(true,
+            Abs(res.GetConstant(0)-2)<Abs(olsRes.GetConstant(0)-2),
+            "The robust slope was not closer than the least squares slope.",t,
+        );
+        test.BasicTest(true,Abs(res.GetConstant(0)-2)<0.05,
+            "The robust slope was not correct.",t,
+        );
+        test.BasicTest(true,irls.Weights[15]<0.1,
+            "The outlier was not down weighted.",t,
+        );
+    }
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    _,_,_,err:=RobustLinearReg(&l,samples,[]float64{1},RobustOpts[float64]{
+        Weight: HuberWeights(DefaultHuberK),
+    });
+    if !customerr.IsDimensionsDoNotAgree(err) {
+        test.FormatError(customerr.DimensionsDoNotAgree(""),err,
+            "Mismatched weights did not return an error.",t,
+        );
+    }
+}
+
+func TestRobustLinearRegExactFit(t *testing.T){
+    l:=NewLinearReg[float64](LinearSumOpGenWithError[float64]([]string{"x"},"y"));
+    samples:=[]Vars[float64]{};
+    for i:=0; i<5; i++ {
+        samples=append(samples,map[string]float64{"x": float64(i), "y": float64(3*i)});
+    }
+    res,irls,_,err:=RobustLinearReg(&l,samples,nil,RobustOpts[float64]{
+        Weight: TukeyBisquareWeights(DefaultTukeyC),
+    });
+    test.BasicTest(nil,err,"Robust lin reg returned an error.",t);
+    test.BasicTest(0,irls.Iterations,"An exact fit was reweighted.",t);
+    closeTo(3,res.GetConstant(0),1e-9,"The slope was not correct.",t);
+}